@@ -11,7 +11,17 @@ import (
 	"github.com/carabiner-dev/drop/pkg/drop"
 )
 
+type checkUpdateOptions struct {
+	Scope string
+}
+
+// AddFlags adds the subcommands flags
+func (co *checkUpdateOptions) AddFlags(cmd *cobra.Command) {
+	addScopeFlag(cmd, &co.Scope)
+}
+
 func addCheckUpdate(parentCmd *cobra.Command) {
+	opts := &checkUpdateOptions{}
 	attCmd := &cobra.Command{
 		Short: "checks if the apps installed with drop have new releases",
 		Long: fmt.Sprintf(`
@@ -21,8 +31,11 @@ The %s subcommand goes through the apps installed with drop and
 checks their GitHub repositories to see if any of them has published a newer
 release.
 
-The data of the installed apps is read from drop's inventory, only apps
-installed through %s are checked.
+The data of the installed apps is read from drop's inventories, only apps
+installed through %s are checked. Apps installed for the user are
+recorded in ~/.config/drop and those installed system-wide (packages and
+binaries outside the home directory) in /var/lib/drop, where root and
+every user see them. Both are checked unless --scope selects one.
 
 `, DropBanner("Check installed apps for new releases"), w2("check-update"), w2("drop install")),
 		Use:               "check-update",
@@ -39,7 +52,7 @@ installed through %s are checked.
 				return fmt.Errorf("creating dropper: %w", err)
 			}
 
-			statuses, err := dropper.CheckUpdates()
+			statuses, err := dropper.CheckUpdates(opts.Scope)
 			if err != nil {
 				return err
 			}
@@ -78,5 +91,6 @@ installed through %s are checked.
 			return nil
 		},
 	}
+	opts.AddFlags(attCmd)
 	parentCmd.AddCommand(attCmd)
 }
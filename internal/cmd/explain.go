@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+)
+
+// explainSelection returns a selection explainer that prints the scored
+// variants of an installable to stderr so it does not mix with the
+// downloaded data when writing to stdout.
+func explainSelection() drop.SelectionExplainer {
+	return func(r *drop.Ranking) {
+		printRanking(os.Stderr, r)
+	}
+}
+
+// printRanking renders the variant ranking as a table
+func printRanking(w io.Writer, r *drop.Ranking) {
+	_, _ = fmt.Fprintf(w, "\nAsset selection for %s (%s):\n", r.Installable, r.Platform) //nolint:errcheck
+	tw := tabwriter.NewWriter(w, 2, 2, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "  \tSCORE\tASSET\tKIND\tPLATFORM\tREASONS") //nolint:errcheck
+	for _, sv := range r.Variants {
+		mark := ""
+		if sv.Chosen {
+			mark = "→"
+		}
+
+		platform := sv.Asset.Os + "/" + sv.Asset.Arch
+		if sv.Asset.ArchLevel != "" {
+			platform += "/" + sv.Asset.ArchLevel
+		}
+
+		score := "-"
+		reasons := "rejected: " + sv.Rejected
+		if sv.Eligible() {
+			score = fmt.Sprintf("%d", sv.Score)
+			parts := make([]string, 0, len(sv.Reasons))
+			for _, reason := range sv.Reasons {
+				parts = append(parts, fmt.Sprintf("%s (%+d)", reason.Name, reason.Points))
+			}
+			reasons = strings.Join(parts, ", ")
+		}
+
+		_, _ = fmt.Fprintf( //nolint:errcheck
+			tw, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			mark, score, sv.Asset.GetName(), sv.Kind, platform, reasons,
+		)
+	}
+	_ = tw.Flush()         //nolint:errcheck
+	_, _ = fmt.Fprintln(w) //nolint:errcheck
+}
@@ -15,18 +15,29 @@ import (
 	"github.com/carabiner-dev/drop/internal/notifier"
 	"github.com/carabiner-dev/drop/pkg/drop"
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
 
 type getOptions struct {
-	AppUrl       string
-	Platform     string
-	PolicyRepo   string
-	DownloadType string
-	Timeout      int
-	Quiet        bool
-	Insecure     bool
-	Directory    string
+	AppUrl         string
+	Platform       string
+	PolicyRepo     string
+	PolicyFiles    []string
+	NoPolicyRepo   bool
+	DownloadType   string
+	Timeout        int
+	Quiet          bool
+	Insecure       bool
+	Directory      string
+	NoFallback     bool
+	AllowEmulated  bool
+	FallbackArches []string
+	Explain        bool
+	Report         string
+	MinLevel       string
+	MinisignKeys   []string
+	GPGKeys        []string
 }
 
 var downloadTypes = []string{"binary", "package", "archive"}
@@ -47,6 +58,10 @@ func (io *getOptions) Validate() error {
 		errs = append(errs, fmt.Errorf("invalid download type valid types are %v", downloadTypes))
 	}
 
+	if io.NoPolicyRepo && len(io.PolicyFiles) == 0 {
+		errs = append(errs, errors.New("--no-policy-repo requires local policies (--policy)"))
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -62,11 +77,19 @@ func (io *getOptions) AddFlags(cmd *cobra.Command) {
 	)
 
 	cmd.PersistentFlags().StringVarP(
-		&io.Platform, "platform", "p", platform, "platform slug to download and verify",
+		&io.Platform, "platform", "p", platform, "platform slug to download and verify (os/arch[/level])",
 	)
 
 	cmd.PersistentFlags().StringVar(
-		&io.PolicyRepo, "policy-repo", "", "alternative repository to use as policy source",
+		&io.PolicyRepo, "policy-repo", "", "alternative repository to use as policy source, pin it to a branch, tag or commit with @ref",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.PolicyFiles, "policy", nil, "local policy set file or directory to verify against (can be repeated)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.NoPolicyRepo, "no-policy-repo", false, "don't fetch policies from the policy repository, use only --policy files",
 	)
 
 	cmd.PersistentFlags().IntVar(
@@ -88,6 +111,39 @@ func (io *getOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(
 		&io.Directory, "directory", "d", ".", "Output directory",
 	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.NoFallback, "no-fallback", false, "don't fall back to compatible arches when there is no native variant",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.AllowEmulated, "allow-emulated", false, "allow falling back to arches that run under emulation (eg amd64 on arm64)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.FallbackArches, "fallback-arch", nil, "custom list of arches to fall back to, in order of preference",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.Explain, "explain", false, "print the scores of the release files and why one was chosen",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.Report, "report", "", "save the full verification results as JSON to a file (for audits)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.MinLevel, "min-level", string(integrity.LevelSignature),
+		fmt.Sprintf("weakest verification accepted for releases without policies (%v)", integrity.Levels[1:]),
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.MinisignKeys, "minisign-key", nil, "minisign public key file trusted to sign releases (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.GPGKeys, "gpg-key", nil, "GPG public key file trusted to sign releases (can be repeated)",
+	)
 }
 
 func addGet(parentCmd *cobra.Command) {
@@ -106,7 +162,49 @@ file published as a release asset.
 By default, %s looks for attestations published along the artifacts and 
 security policies in a specially named .ampel directory in the same GitHub
 organization where the files are hosted. You can specify an alternative 
-policy repository.
+policy repository with --policy-repo or map orgs and repositories to policy
+repositories in the configuration file (~/.config/drop/config.yaml):
+
+  policies:
+    default: github.com/example/policies
+    repositories:
+      github.com/sigstore: github.com/example/sigstore-policies
+      github.com/cli/cli: github.com/example/gh-policies
+
+The most specific pattern matching the artifact's repository wins, the
+default applies to the rest. Installed apps are updated and verified again
+with the same mapping.
+
+Policy repositories can be pinned to a reviewed branch, tag or commit by
+adding @ref (github.com/example/policies@v1.2.0), both in the configuration
+and in --policy-repo. The commit the policies were read from is printed and
+recorded in the inventory. To only trust policies from commits signed by
+your policy team, list their GPG public keys in the configuration:
+
+  policies:
+    signingKeys:
+      - policy-team.asc
+
+Policies can also be read from local files with --policy, pointing to a
+PolicySet file, a signed policy attestation or a directory of them. Local
+policies are evaluated along those in the policy repository, add
+--no-policy-repo to verify only against the local files (eg when iterating
+on policies or in air-gapped systems):
+
+  drop get --policy ./policies/ --no-policy-repo github.com/org/repo
+
+When the project publishes no policies, drop falls back to the checksums
+and signatures in the release, using the strongest it finds: sigstore
+bundles signed by the repository's GitHub Actions workflows (sigstore),
+minisign or GPG signatures by the keys passed with --minisign-key and
+--gpg-key (signature) and, last, the published checksum files (checksum).
+Signatures can cover the artifact or the checksum file listing it. The
+level achieved is printed and recorded in the inventory. At least a
+signature is required by default, releases only publishing checksums are
+refused unless --min-level=checksum is passed. Use --min-level to refuse
+weaker verifications (--min-level=policy requires policies):
+
+  drop get --min-level=sigstore github.com/org/repo
 
 Artifacts in a release are grouped into an "installable". This is a named entry
 that groups together all platform variants, packages and archives as well as 
@@ -124,6 +222,17 @@ the variant to download with --platform:
 
   drop get --platform=linux/amd64 github.com/org/repo
 
+When a release publishes variants optimized for CPU feature levels (amd64v3,
+armv7, arm64-v8.2...), drop picks the best one the local machine can run. The
+level can be specified in the platform slug too:
+
+  drop get --platform=linux/amd64/v2 github.com/org/repo
+
+To see how each file in the release scored and why one was picked over the
+others, add --explain:
+
+  drop get --explain github.com/org/repo
+
 If the installable does not match the repo name or the release has more than
 one installable, you can specify another adding a frament (data after #) to the
 app URL. For example, if "repo" publishes a "server" binary, you can dowload it
@@ -134,6 +243,12 @@ with:
 
 %s
 
+After verifying, drop prints a summary of the results of each policy and
+tenet, with the attestations and signer identities used. To keep the full
+results for audits, save them as JSON with --report:
+
+  drop get --report=verification.json github.com/org/repo
+
 All downloads are verified. If you really *really* want to skip the verification
 process, you can add the --insecure flag:
 
@@ -194,24 +309,51 @@ of %s policies to secure their releases ✨
 				lstnr = &drop.NoopListener{}
 			}
 
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
 			// Create the new dropper instance
 			dropper, err := drop.New(
 				drop.WithPolicyRepository(opts.PolicyRepo),
+				drop.WithPolicyMap(&conf.Policies),
+				drop.WithPolicySigningKeys(conf.Policies.SigningKeys...),
+				drop.WithPolicyFiles(opts.PolicyFiles...),
+				drop.WithRepoPolicies(!opts.NoPolicyRepo),
+				drop.WithMinisignKeys(opts.MinisignKeys...),
+				drop.WithGPGKeys(opts.GPGKeys...),
 				drop.WithListener(lstnr),
 			)
 			if err != nil {
 				return fmt.Errorf("cerating dropper: %w", err)
 			}
 
-			// Run the download:
-			if err := dropper.Get(
-				asset,
+			getOpts := []drop.FuncGetOption{
 				drop.WithDownloadPath(opts.Directory),
 				drop.WithTransferTimeOut(opts.Timeout),
 				drop.WithPlatform(opts.Platform),
 				drop.WithVerifyDownloads(!opts.Insecure),
+				drop.WithMinVerificationLevel(opts.MinLevel),
 				drop.WithDownloadType(opts.DownloadType),
-			); err != nil {
+				drop.WithFallbacks(!opts.NoFallback),
+				drop.WithEmulation(opts.AllowEmulated),
+				drop.WithFallbackArches(opts.FallbackArches...),
+			}
+
+			if opts.Explain {
+				getOpts = append(getOpts, drop.WithExplainer(explainSelection()))
+			}
+
+			if !opts.Quiet {
+				getOpts = append(getOpts, drop.WithVerificationReporter(reportVerification()))
+			}
+			if opts.Report != "" {
+				getOpts = append(getOpts, drop.WithReportPath(opts.Report))
+			}
+
+			// Run the download:
+			if err := dropper.Get(asset, getOpts...); err != nil {
 				return fmt.Errorf("error downloading: %w", err)
 			}
 			return nil
@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"os"
@@ -16,19 +17,38 @@ import (
 	"github.com/carabiner-dev/drop/internal/notifier"
 	"github.com/carabiner-dev/drop/pkg/drop"
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 )
 
 type installOptions struct {
-	AppUrl      string
-	PolicyRepo  string
-	InstallType string
-	Timeout     int
-	Quiet       bool
-	Insecure    bool
-	BinDir      string
+	AppUrl         string
+	PolicyRepo     string
+	PolicyFiles    []string
+	NoPolicyRepo   bool
+	InstallType    string
+	Timeout        int
+	Quiet          bool
+	Insecure       bool
+	BinDir         string
+	System         bool
+	Prefix         string
+	Escalation     string
+	AppsDir        string
+	Desktop        bool
+	Completions    bool
+	NoFallback     bool
+	AllowEmulated  bool
+	FallbackArches []string
+	Explain        bool
+	Report         string
+	MinLevel       string
+	MinisignKeys   []string
+	GPGKeys        []string
+	MaxSeverity    string
+	IgnoreVulns    bool
 }
 
-var installTypes = []string{string(drop.ArtifactBinary), string(drop.ArtifactPackage)}
+var installTypes = []string{string(drop.ArtifactBinary), string(drop.ArtifactPackage), string(drop.ArtifactAppImage)}
 
 // Validates the options in context with arguments
 func (io *installOptions) Validate() error {
@@ -42,15 +62,19 @@ func (io *installOptions) Validate() error {
 	}
 
 	switch io.InstallType {
-	case "", "b", "p", string(drop.ArtifactBinary), string(drop.ArtifactPackage):
+	case "", "b", "p", "i", string(drop.ArtifactBinary), string(drop.ArtifactPackage), string(drop.ArtifactAppImage):
 	case "a", "archive":
 		errs = append(errs, errors.New("archives cannot be installed, use \"drop get\" to download them"))
 	default:
 		errs = append(errs, fmt.Errorf("invalid install type, valid types are %v", installTypes))
 	}
 
-	if io.BinDir == "" {
-		errs = append(errs, errors.New("binary directory cannot be empty"))
+	if io.Prefix != "" && io.System {
+		errs = append(errs, errors.New("--prefix and --system cannot be used together"))
+	}
+
+	if io.NoPolicyRepo && len(io.PolicyFiles) == 0 {
+		errs = append(errs, errors.New("--no-policy-repo requires local policies (--policy)"))
 	}
 
 	return errors.Join(errs...)
@@ -63,7 +87,15 @@ func (io *installOptions) AddFlags(cmd *cobra.Command) {
 	)
 
 	cmd.PersistentFlags().StringVar(
-		&io.PolicyRepo, "policy-repo", "", "alternative repository to use as policy source",
+		&io.PolicyRepo, "policy-repo", "", "alternative repository to use as policy source, pin it to a branch, tag or commit with @ref",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.PolicyFiles, "policy", nil, "local policy set file or directory to verify against (can be repeated)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.NoPolicyRepo, "no-policy-repo", false, "don't fetch policies from the policy repository, use only --policy files",
 	)
 
 	cmd.PersistentFlags().IntVar(
@@ -83,7 +115,70 @@ func (io *installOptions) AddFlags(cmd *cobra.Command) {
 	)
 
 	cmd.PersistentFlags().StringVar(
-		&io.BinDir, "bin-dir", "/usr/local/bin", "directory to install binaries into",
+		&io.BinDir, "bin-dir", "", "directory to install binaries into (default ~/.local/bin, /usr/local/bin for root)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.System, "system", false, "install system-wide (into /usr/local/bin) even when not running as root",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.Prefix, "prefix", "", "unpack packages into a user directory (eg ~/.local) instead of using the package manager",
+	)
+
+	addEscalationFlag(cmd, &io.Escalation)
+
+	cmd.PersistentFlags().StringVar(
+		&io.AppsDir, "apps-dir", "", fmt.Sprintf("directory to install AppImages into (default ~/Applications, %s for system installs)", drop.SystemAppsDir),
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.Desktop, "desktop", false, "install the desktop entry and icon of AppImages",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.Completions, "completions", false, "install shell completions and man pages of binaries",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.NoFallback, "no-fallback", false, "don't fall back to compatible arches when there is no native variant",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.AllowEmulated, "allow-emulated", false, "allow falling back to arches that run under emulation (eg amd64 on arm64)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.FallbackArches, "fallback-arch", nil, "custom list of arches to fall back to, in order of preference",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.Explain, "explain", false, "print the scores of the release files and why one was chosen",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.Report, "report", "", "save the full verification results as JSON to a file (for audits)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.MinLevel, "min-level", string(integrity.LevelSignature),
+		fmt.Sprintf("weakest verification accepted for releases without policies (%v)", integrity.Levels[1:]),
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.MinisignKeys, "minisign-key", nil, "minisign public key file trusted to sign releases (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&io.GPGKeys, "gpg-key", nil, "GPG public key file trusted to sign releases (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&io.MaxSeverity, "max-severity", "", "most serious vulnerability accepted: low, medium, high or critical (default medium)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&io.IgnoreVulns, "ignore-vulns", false, "install apps with vulnerabilities above --max-severity, only reporting them",
 	)
 }
 
@@ -99,9 +194,31 @@ and installs it in the local system.
 
 After verifying the artifact, %s picks the best way to install the
 app: if the release only publishes a binary for the local platform, it gets
-installed into the binaries directory (--bin-dir, /usr/local/bin by default).
+installed into the binaries directory (--bin-dir).
 If the release only ships a package matching the system's package format
-(rpm, deb, apk), drop installs it using the package manager.
+(rpm, deb, apk, pacman), drop installs it using the package manager.
+
+After verifying, drop prints a summary of the policy results listing what
+passed, what failed and the attestations and signers used. Use --report to
+save the full results as JSON. Like "drop get", install can verify against
+local policy files (--policy), alongside or, with --no-policy-repo, instead
+of the policy repository.
+The policies and attestations used are stored in drop's data directory so
+installed apps can be verified again without network access with
+"drop verify --offline".
+
+When the project publishes no policies, drop falls back to the checksums
+and signatures in the release, using the strongest it finds: sigstore
+bundles signed by the repository's GitHub Actions workflows (sigstore),
+minisign or GPG signatures by the keys passed with --minisign-key and
+--gpg-key (signature) and, last, the published checksum files (checksum).
+Signatures can cover the artifact or the checksum file listing it. The
+level achieved is printed and recorded in the inventory. At least a
+signature is required by default, releases only publishing checksums are
+refused unless --min-level=checksum is passed. Use --min-level to refuse
+weaker verifications (--min-level=policy requires policies):
+
+  drop install --min-level=sigstore github.com/org/repo
 
 When both a binary and a package are available, %s first checks if
 the app is already installed as a package (to keep it managed by the package
@@ -110,8 +227,46 @@ choice without prompting:
 
   drop install --type=package github.com/org/repo
 
-Installing to system locations usually requires elevated privileges: drop
-shells out to sudo, which may ask for your password.
+With --completions, drop also sets up the shell completions (bash, zsh and
+fish) and man pages of installed binaries. They are copied from the
+completions/ and man/ directories of the release archive for the platform,
+which is verified like the binary, or generated by running the binary's
+completion subcommand when it has one. The files are installed into the
+XDG data directory (~/.local/share) or /usr/local/share when running as root.
+
+Desktop apps shipped as AppImages are installed into ~/Applications,
+/opt/drop/apps for system installs (or --apps-dir) with the executable bit
+set. With --desktop, drop also installs the desktop entry and icon embedded
+in the AppImage so the app shows in the desktop menus, from ~/.local/share
+or /usr/local/share for system installs.
+
+When the release has no build for the local arch, drop falls back to
+compatible ones: a universal binary on macOS or a 386 build on x86_64
+machines. Builds that only run under emulation (such as amd64 binaries on
+arm64) are only considered with --allow-emulated.
+
+Binaries are installed for the current user into ~/.local/bin (or
+$XDG_BIN_HOME), no privileges needed. drop warns when the directory is not
+in your PATH (see "drop path") or when another binary with the same name
+comes first in it. When running as root, or with --system, binaries go to
+/usr/local/bin. Installing to system locations and installing packages
+usually requires elevated privileges, as does recording them in the system
+inventory: drop shells out to the first privilege escalation helper it
+finds (sudo, doas, run0 or pkexec), which may ask for your password. Use --escalation, or the escalation
+key of the configuration file, to choose one.
+
+To install without root, use --prefix to unpack the contents of deb, rpm,
+apk or pacman packages into a user directory. Paths are mapped into the
+prefix (/usr/bin to <prefix>/bin, /usr/share to <prefix>/share, and so on)
+and binaries are installed into <prefix>/bin. The unpacked files are
+recorded in the inventory:
+
+  drop install --prefix ~/.local github.com/org/repo
+
+Before installing, apps are scanned for known vulnerabilities when a local
+copy of the OSV database is available (see "drop vulns --help"). Apps with
+vulnerabilities more serious than --max-severity (medium by default) are
+refused unless --ignore-vulns is passed.
 
 `, DropBanner("Download, verify and install apps from GitHub releases"), w2("install"), w2("drop install"), w2("drop install")),
 		Use:               "install",
@@ -152,20 +307,53 @@ shells out to sudo, which may ask for your password.
 				lstnr = &drop.NoopListener{}
 			}
 
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
 			// Create the new dropper instance
 			dropper, err := drop.New(
 				drop.WithPolicyRepository(opts.PolicyRepo),
+				drop.WithPolicyMap(&conf.Policies),
+				drop.WithPolicySigningKeys(conf.Policies.SigningKeys...),
+				drop.WithPolicyFiles(opts.PolicyFiles...),
+				drop.WithRepoPolicies(!opts.NoPolicyRepo),
+				drop.WithMinisignKeys(opts.MinisignKeys...),
+				drop.WithGPGKeys(opts.GPGKeys...),
 				drop.WithListener(lstnr),
+				drop.WithEscalation(cmp.Or(opts.Escalation, conf.Escalation)),
+				drop.WithVulnDatabase(conf.Vulnerabilities.Database),
 			)
 			if err != nil {
 				return fmt.Errorf("creating dropper: %w", err)
 			}
 
+			scope := drop.DefaultScope()
+			if opts.System {
+				scope = drop.ScopeSystem
+			}
+
 			installOpts := []drop.FuncGetOption{
 				drop.WithTransferTimeOut(opts.Timeout),
 				drop.WithVerifyDownloads(!opts.Insecure),
+				drop.WithMinVerificationLevel(opts.MinLevel),
 				drop.WithDownloadType(opts.InstallType),
-				drop.WithBinDir(opts.BinDir),
+				drop.WithScope(scope),
+				drop.WithFallbacks(!opts.NoFallback),
+				drop.WithEmulation(opts.AllowEmulated),
+				drop.WithFallbackArches(opts.FallbackArches...),
+				drop.WithPrefix(opts.Prefix),
+				drop.WithAppsDir(opts.AppsDir),
+				drop.WithDesktopIntegration(opts.Desktop),
+				drop.WithCompletions(opts.Completions),
+				drop.WithIgnoreVulnerabilities(opts.IgnoreVulns),
+			}
+			if sev := cmp.Or(opts.MaxSeverity, conf.Vulnerabilities.MaxSeverity); sev != "" {
+				installOpts = append(installOpts, drop.WithMaxSeverity(sev))
+			}
+			if opts.BinDir != "" {
+				installOpts = append(installOpts, drop.WithBinDir(opts.BinDir))
 			}
 
 			// When running interactively (and no type was forced), let the
@@ -175,6 +363,17 @@ shells out to sudo, which may ask for your password.
 				installOpts = append(installOpts, drop.WithArtifactSelector(huhSelector()))
 			}
 
+			if opts.Explain {
+				installOpts = append(installOpts, drop.WithExplainer(explainSelection()))
+			}
+
+			if !opts.Quiet {
+				installOpts = append(installOpts, drop.WithVerificationReporter(reportVerification()))
+			}
+			if opts.Report != "" {
+				installOpts = append(installOpts, drop.WithReportPath(opts.Report))
+			}
+
 			// Run the installation:
 			if err := dropper.Install(asset, installOpts...); err != nil {
 				if errors.Is(err, drop.ErrOnlyArchives) || errors.Is(err, drop.ErrNoInstallableArtifact) {
@@ -196,8 +395,11 @@ func huhSelector() drop.ArtifactSelector {
 		huhOpts := make([]huh.Option[*drop.InstallArtifact], 0, len(candidates))
 		for _, candidate := range candidates {
 			label := "Binary"
-			if candidate.Kind == drop.ArtifactPackage {
+			switch candidate.Kind {
+			case drop.ArtifactPackage:
 				label = strings.ToUpper(candidate.PackageFormat) + " package"
+			case drop.ArtifactAppImage:
+				label = "AppImage"
 			}
 			huhOpts = append(huhOpts, huh.NewOption(label, candidate))
 		}
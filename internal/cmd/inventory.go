@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+type inventoryMigrateOptions struct {
+	DryRun bool
+}
+
+// AddFlags adds the subcommands flags
+func (mo *inventoryMigrateOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(
+		&mo.DryRun, "dry-run", false, "print the migrations without rewriting the inventory",
+	)
+}
+
+func addInventory(parentCmd *cobra.Command) {
+	inventoryCmd := &cobra.Command{
+		Short: "manages the inventory of installed apps",
+		Long: fmt.Sprintf(`
+%s
+
+drop records the apps it installs in inventory files: user installs in
+the user's configuration directory (~/.config/drop/installed.json) and
+system-wide installs in a shared inventory (%s). The
+inventories are used to verify, update and report on the installed apps.
+
+`, DropBanner("Manage the inventory"), filepath.Join(inventory.SystemDir, inventory.FileName)),
+		Use:               "inventory",
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+	}
+
+	opts := &inventoryMigrateOptions{}
+	migrateCmd := &cobra.Command{
+		Short: "upgrades the inventory to the current schema version",
+		Long: fmt.Sprintf(`
+%s
+
+The inventory files are versioned. Inventories written by older versions
+of drop are upgraded when loaded and rewritten the next time drop saves
+them, keeping a copy of the original next to the file
+(installed.json.v<version>.bak).
+
+The %s subcommand rewrites the user and system inventories right
+away, migrating the system inventory requires running as root. Use
+--dry-run to list the migrations the inventories need without changing
+them.
+
+`, DropBanner("Migrate the inventory"), w2("inventory migrate")),
+		Use:               "migrate",
+		Example:           fmt.Sprintf("%s inventory migrate --dry-run", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			errs := []error{}
+			found := 0
+			for _, scope := range inventory.Scopes {
+				path, err := inventory.ScopePath(scope)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				found++
+				if err := migrateInventory(path, opts.DryRun); err != nil {
+					errs = append(errs, fmt.Errorf("migrating %s inventory: %w", scope, err))
+				}
+			}
+			if found == 0 && len(errs) == 0 {
+				fmt.Println("  📭 No apps installed with drop yet.")
+			}
+			return errors.Join(errs...)
+		},
+	}
+	opts.AddFlags(migrateCmd)
+
+	// Users that cannot write to the system inventory update it through
+	// the privilege escalation helper, running these as root.
+	putCmd := &cobra.Command{
+		Short:  "records an app in the system inventory",
+		Use:    drop.InventoryCmdPut + " record.json",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("reading record: %w", err)
+			}
+			record := &inventory.Record{}
+			if err := json.Unmarshal(data, record); err != nil {
+				return fmt.Errorf("parsing record: %w", err)
+			}
+			return inventory.UpdateFile(inventory.SystemPath(), func(inv *inventory.Inventory) error {
+				inv.Add(record)
+				return nil
+			})
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Short:  "deletes an app from the system inventory",
+		Use:    drop.InventoryCmdDelete + " key",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return inventory.UpdateFile(inventory.SystemPath(), func(inv *inventory.Inventory) error {
+				if !inv.Remove(args[0]) {
+					return fmt.Errorf("%s is not in the system inventory", args[0])
+				}
+				return nil
+			})
+		},
+	}
+
+	inventoryCmd.AddCommand(migrateCmd, putCmd, deleteCmd)
+	parentCmd.AddCommand(inventoryCmd)
+}
+
+// migrateInventory rewrites an inventory file in the current schema version,
+// printing the migrations it goes through.
+func migrateInventory(path string, dryRun bool) error {
+	inv, err := inventory.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("opening install inventory: %w", err)
+	}
+
+	from := inv.MigratedFrom()
+	if from == 0 {
+		fmt.Printf("  ✅ %s is up to date (version %d)\n", path, inventory.Version)
+		return nil
+	}
+
+	fmt.Printf("  📦 %s is version %d, migrating to version %d:\n", path, from, inventory.Version)
+	for _, m := range inventory.MigrationsFrom(from) {
+		fmt.Printf("     v%d → v%d: %s\n", m.From, m.From+1, m.Description)
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println("  ℹ️  Dry run, the inventory was not changed.")
+		return nil
+	}
+
+	// Saving through UpdateFile rewrites the migrated inventory under its
+	// lock, backing up the original.
+	if err := inventory.UpdateFile(path, func(*inventory.Inventory) error { return nil }); err != nil {
+		return err
+	}
+	fmt.Printf("  ✨ Inventory migrated, the original was saved to %s\n", inventory.BackupPath(path, from))
+	return nil
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/render"
+	"github.com/carabiner-dev/drop/pkg/render/drivers"
+)
+
+// Output formats of the list subcommand
+const (
+	listFormatTable = "table"
+	listFormatJSON  = "json"
+)
+
+var listKinds = []string{string(drop.ArtifactBinary), string(drop.ArtifactPackage), string(drop.ArtifactAppImage)}
+
+type listOptions struct {
+	Format     string
+	Org        string
+	Kind       string
+	Unverified bool
+	Scope      string
+}
+
+// Validates the options in context with arguments
+func (lo *listOptions) Validate() error {
+	errs := []error{}
+	if lo.Format != listFormatTable && lo.Format != listFormatJSON {
+		errs = append(errs, fmt.Errorf("unknown output format %q, valid formats are %s and %s", lo.Format, listFormatTable, listFormatJSON))
+	}
+	if lo.Kind != "" && !slices.Contains(listKinds, lo.Kind) {
+		errs = append(errs, fmt.Errorf("unknown app kind %q, valid kinds are %v", lo.Kind, listKinds))
+	}
+	return errors.Join(errs...)
+}
+
+// AddFlags adds the subcommands flags
+func (lo *listOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
+		&lo.Format, "format", "f", listFormatTable, fmt.Sprintf("output format, %s or %s", listFormatTable, listFormatJSON),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&lo.Org, "org", "", "only list the apps released by an organization",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&lo.Kind, "kind", "", fmt.Sprintf("only list the apps installed as a kind of artifact %v", listKinds),
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&lo.Unverified, "unverified", false, "only list the apps installed without verification",
+	)
+
+	addScopeFlag(cmd, &lo.Scope)
+}
+
+// Filter returns the inventory filter set by the options
+func (lo *listOptions) Filter() *inventory.Filter {
+	return &inventory.Filter{Org: lo.Org, Kind: lo.Kind, Unverified: lo.Unverified}
+}
+
+func addList(parentCmd *cobra.Command) {
+	opts := &listOptions{}
+	listCmd := &cobra.Command{
+		Short: "lists the apps installed with drop",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand prints the apps recorded in drop's inventories: their
+name, version, the kind of artifact installed, where it lives, how it was
+verified and when it was installed and last updated.
+
+The apps can be filtered by the organization releasing them (--org), the
+kind of artifact (--kind), the install scope (--scope) or to only those
+installed without verification (--unverified):
+
+  drop list --kind binary --unverified
+
+Use --format json for a machine readable listing with the full inventory
+records. %s prints the same listing.
+
+`, DropBanner("List the installed apps"), w2("list"), w2("drop ls --installed")),
+		Use:               "list",
+		Example:           fmt.Sprintf("%s list --org carabiner-dev", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			var drv render.Driver = drivers.NewLsTTY()
+			if opts.Format == listFormatJSON {
+				drv = drivers.NewJSON()
+			}
+			return renderInstalled(drv, opts.Scope, opts.Filter(), opts.Format == listFormatTable)
+		},
+	}
+	opts.AddFlags(listCmd)
+	parentCmd.AddCommand(listCmd)
+}
+
+// renderInstalled renders the installed apps passing a filter. With tty set,
+// an empty listing prints a note instead of an empty table.
+func renderInstalled(drv render.Driver, scope string, filter *inventory.Filter, tty bool) error {
+	records, err := inventory.Installed(scope)
+	if err != nil {
+		return fmt.Errorf("opening install inventory: %w", err)
+	}
+	records = filter.Apply(records)
+	if tty && len(records) == 0 {
+		if scope == "" && *filter == (inventory.Filter{}) {
+			fmt.Println("  📭 No apps installed with drop yet.")
+		} else {
+			fmt.Println("  📭 No installed apps match the filters.")
+		}
+		return nil
+	}
+
+	eng, err := render.New(render.WithDriver(drv))
+	if err != nil {
+		return err
+	}
+	return eng.RenderInstalledApps(os.Stdout, records)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/render"
 	"github.com/carabiner-dev/drop/pkg/render/drivers"
 )
@@ -21,12 +22,13 @@ type lsOptions struct {
 	Long         bool
 	All          bool
 	ListReleases bool
+	Installed    bool
 }
 
 // Validates the options in context with arguments
 func (lo *lsOptions) Validate() error {
 	errs := []error{}
-	if lo.AppUrl == "" {
+	if lo.AppUrl == "" && !lo.Installed {
 		errs = append(errs, errors.New("github url not set"))
 	}
 
@@ -46,6 +48,10 @@ func (lo *lsOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(
 		&lo.ListReleases, "releases", "r", false, "list releases in the repo instead of artifacts",
 	)
+
+	cmd.PersistentFlags().BoolVar(
+		&lo.Installed, "installed", false, "list the apps installed with drop instead of a repository (see drop list)",
+	)
 }
 
 func addLs(parentCmd *cobra.Command) {
@@ -113,7 +119,11 @@ see a list of the available releases, use the -r|--releases switch:
 
   %s ls -lr github.com/app/repo
 
-  `, appname, appname, appname, appname),
+To list the apps installed with drop instead, use --installed:
+
+  %s ls -l --installed
+
+  `, appname, appname, appname, appname, appname),
 		SilenceUsage:      false,
 		SilenceErrors:     true,
 		PersistentPreRunE: initLogging,
@@ -130,6 +140,12 @@ see a list of the available releases, use the -r|--releases switch:
 			}
 			cmd.SilenceUsage = true
 
+			if opts.Installed {
+				drv := drivers.NewLsTTY()
+				drv.Options.Long = opts.Long
+				return renderInstalled(drv, "", &inventory.Filter{}, true)
+			}
+
 			// Parse the asset URL
 			asset := github.NewAssetFromURLString(opts.AppUrl)
 			if asset == nil {
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+type pathOptions struct {
+	Shell  string
+	BinDir string
+	Append bool
+}
+
+// Validates the options in context with arguments
+func (po *pathOptions) Validate() error {
+	errs := []error{}
+	if po.Shell == "" {
+		errs = append(errs, errors.New("unable to detect the shell, set one with --shell"))
+	} else if !slices.Contains([]string{system.ShellBash, system.ShellZsh, system.ShellFish}, po.Shell) {
+		errs = append(errs, fmt.Errorf("unsupported shell %q, supported shells are bash, zsh and fish", po.Shell))
+	}
+	return errors.Join(errs...)
+}
+
+// AddFlags adds the subcommands flags
+func (po *pathOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&po.Shell, "shell", system.ShellFromEnv(), "shell to configure (bash, zsh or fish)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&po.BinDir, "bin-dir", "", "binaries directory to add to the PATH (default ~/.local/bin)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&po.Append, "append", false, "append the snippet to the shell startup file instead of printing it",
+	)
+}
+
+func addPath(parentCmd *cobra.Command) {
+	opts := &pathOptions{}
+	pathCmd := &cobra.Command{
+		Short: "sets up the PATH to run apps installed by drop",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand prints the shell snippet that adds the user binaries
+directory (~/.local/bin) to the PATH. With --append, drop adds it to the
+shell startup file (~/.bashrc, ~/.zshrc or ~/.config/fish/config.fish)
+unless it is already there:
+
+  drop path --shell bash --append
+
+It also checks the binaries recorded in the inventory and warns about any
+of them shadowed by a binary with the same name in an earlier PATH entry.
+
+`, DropBanner("Set up the PATH for apps installed by drop"), w2("path")),
+		Use:               "path",
+		Example:           fmt.Sprintf(`%s path --shell zsh`, appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			dir := opts.BinDir
+			if dir == "" {
+				d, err := system.UserBinDir()
+				if err != nil {
+					return err
+				}
+				dir = d
+			}
+
+			if !opts.Append {
+				snippet, err := system.PathSnippet(opts.Shell, dir)
+				if err != nil {
+					return err
+				}
+				fmt.Print(snippet)
+			} else {
+				rc, err := system.ShellRCFile(opts.Shell)
+				if err != nil {
+					return err
+				}
+				changed, err := system.AppendPathSnippet(rc, opts.Shell, dir)
+				if err != nil {
+					return err
+				}
+				if changed {
+					fmt.Fprintf(os.Stderr, "Added %s to the PATH in %s, open a new shell to use it\n", dir, rc)
+				} else {
+					fmt.Fprintf(os.Stderr, "%s already adds %s to the PATH\n", rc, dir)
+				}
+			}
+
+			warnShadowed()
+			return nil
+		},
+	}
+	opts.AddFlags(pathCmd)
+	parentCmd.AddCommand(pathCmd)
+}
+
+// warnShadowed prints a warning for each binary in the inventory shadowed
+// by another one found earlier in the PATH.
+func warnShadowed() {
+	records, err := inventory.Installed("")
+	if err != nil {
+		return
+	}
+	pathEnv := os.Getenv("PATH")
+	for _, record := range records {
+		if record.BinPath == "" {
+			continue
+		}
+		if other := system.ShadowingBinary(record.BinPath, pathEnv); other != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s is shadowed by %s, which comes first in your PATH\n", record.BinPath, other)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+)
+
+func addRefresh(parentCmd *cobra.Command) {
+	attCmd := &cobra.Command{
+		Short: "refreshes the stored policies and attestations of installed apps",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand fetches fresh copies of the policies and attestations
+of the apps installed with drop and stores them for offline verification
+(see %s). Evidence of apps no longer installed is removed.
+
+Pass app names to refresh only those apps.
+
+`, DropBanner("Refresh the verification evidence"), w2("refresh"), w2("drop verify --offline")),
+		Use:               "refresh [app...]",
+		Example:           fmt.Sprintf("%s refresh", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			dropper, err := drop.New(
+				drop.WithPolicyMap(&conf.Policies),
+				drop.WithPolicySigningKeys(conf.Policies.SigningKeys...),
+			)
+			if err != nil {
+				return fmt.Errorf("creating dropper: %w", err)
+			}
+
+			records, err := selectRecords(args, "")
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, record := range records {
+				if err := dropper.RefreshEvidence(record); err != nil {
+					failed++
+					fmt.Printf("  ⚠️  %s: refresh failed: %v\n", w(record.Name), err)
+					continue
+				}
+				fmt.Printf("  ✔️  %s %s refreshed\n", w(record.Name), record.Version)
+			}
+
+			if len(args) == 0 {
+				removed, err := dropper.PruneEvidence()
+				if err != nil {
+					return err
+				}
+				if removed > 0 {
+					fmt.Printf("  🧹 Removed %d evidence, SBOM and VEX entries of uninstalled apps\n", removed)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("could not refresh %d of %d apps", failed, len(records))
+			}
+			return nil
+		},
+	}
+	parentCmd.AddCommand(attCmd)
+}
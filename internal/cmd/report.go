@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+)
+
+// reportVerification returns a verification reporter that prints the
+// summary of the policy evaluation.
+func reportVerification() drop.VerificationReporter {
+	return func(r *drop.VerificationReport) {
+		printVerificationReport(os.Stdout, r)
+	}
+}
+
+// printVerificationReport renders the results of each policy and tenet with
+// the attestations and signer identities they were evaluated on.
+func printVerificationReport(w io.Writer, r *drop.VerificationReport) {
+	_, _ = fmt.Fprintf(w, "\n  📋 Verification report for %s:\n", r.Asset) //nolint:errcheck
+	if len(r.Policies) == 0 {
+		_, _ = fmt.Fprintln(w, "      no policies were evaluated") //nolint:errcheck
+	}
+	for _, p := range r.Policies {
+		_, _ = fmt.Fprintf(w, "      %s policy %s: %s\n", statusMark(p.Status), p.ID, p.Status) //nolint:errcheck
+		for _, t := range p.Tenets {
+			line := fmt.Sprintf("          %s %s: %s", statusMark(t.Status), t.ID, t.Status)
+			if t.Error != "" {
+				line += " (" + t.Error + ")"
+			}
+			_, _ = fmt.Fprintln(w, line) //nolint:errcheck
+			if t.Guidance != "" {
+				_, _ = fmt.Fprintf(w, "             guidance: %s\n", t.Guidance) //nolint:errcheck
+			}
+			if len(t.Attestations) > 0 {
+				_, _ = fmt.Fprintf(w, "             attestations: %s\n", strings.Join(t.Attestations, ", ")) //nolint:errcheck
+			}
+			if len(t.Signers) > 0 {
+				_, _ = fmt.Fprintf(w, "             signed by: %s\n", strings.Join(t.Signers, ", ")) //nolint:errcheck
+			}
+		}
+	}
+	_, _ = fmt.Fprintln(w) //nolint:errcheck
+}
+
+// statusMark returns the icon of a policy evaluation status
+func statusMark(status string) string {
+	switch status {
+	case papi.StatusPASS:
+		return "✅"
+	case papi.StatusSOFTFAIL:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
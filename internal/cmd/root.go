@@ -5,12 +5,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/release-utils/log"
 	"sigs.k8s.io/release-utils/version"
+
+	"github.com/carabiner-dev/drop/pkg/config"
 )
 
 const (
@@ -96,7 +99,8 @@ Install the same, but using a system package:
 }
 
 type commandLineOptions struct {
-	logLevel string
+	logLevel   string
+	configPath string
 }
 
 var commandLineOpts = commandLineOptions{}
@@ -105,17 +109,42 @@ func initLogging(*cobra.Command, []string) error {
 	return log.SetupGlobalLogger(commandLineOpts.logLevel)
 }
 
+// loadConfig reads the configuration file set with --config or, by default,
+// the one in the user's configuration directory.
+func loadConfig() (*config.Config, error) {
+	if commandLineOpts.configPath == "" {
+		return config.Load()
+	}
+	if _, err := os.Stat(commandLineOpts.configPath); err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+	return config.LoadFile(commandLineOpts.configPath)
+}
+
 // Execute builds the command
 func Execute() {
 	rootCmd.PersistentFlags().StringVar(
 		&commandLineOpts.logLevel,
 		"log-level", "info", fmt.Sprintf("the logging verbosity, either %s", log.LevelNames()),
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&commandLineOpts.configPath,
+		"config", "", "configuration file (default ~/.config/drop/"+config.FileName+")",
+	)
 	addInstall(rootCmd)
+	addUninstall(rootCmd)
 	addLs(rootCmd)
+	addList(rootCmd)
 	addGet(rootCmd)
 	addCheckUpdate(rootCmd)
 	addUpdate(rootCmd)
+	addPath(rootCmd)
+	addVerify(rootCmd)
+	addRefresh(rootCmd)
+	addTrust(rootCmd)
+	addSBOM(rootCmd)
+	addVulns(rootCmd)
+	addInventory(rootCmd)
 	rootCmd.AddCommand(version.WithFont("doom"))
 
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/sbom"
+)
+
+type sbomOptions struct {
+	Format string
+	Output string
+}
+
+// AddFlags adds the subcommands flags
+func (so *sbomOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
+		&so.Format, "format", "f", "", fmt.Sprintf("convert the SBOM to a format %v, printed as published when not set", sbom.Formats),
+	)
+
+	cmd.PersistentFlags().StringVarP(
+		&so.Output, "output", "o", "", "write the SBOM to a file instead of STDOUT",
+	)
+}
+
+func addSBOM(parentCmd *cobra.Command) {
+	opts := &sbomOptions{}
+	sbomCmd := &cobra.Command{
+		Short: "prints the SBOM of an installed app",
+		Long: fmt.Sprintf(`
+%s
+
+When a release publishes an SBOM for the installed artifact, drop downloads
+it and stores it in its data directory (~/.local/share/drop/sboms). If the
+release also has an attestation for the SBOM, the SBOM must match its
+subject digest or it is not stored.
+
+The %s subcommand prints the stored SBOM of an app. Use --format to
+convert it to SPDX or CycloneDX JSON and --output to write it to a file:
+
+  drop sbom cosign --format cyclonedx --output cosign.cdx.json
+
+`, DropBanner("Print the SBOM of an app"), w2("sbom")),
+		Use:               "sbom app",
+		Example:           fmt.Sprintf("%s sbom cosign --format spdx", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("specify the app to print the SBOM of")
+			}
+
+			format := ""
+			if opts.Format != "" {
+				f, err := sbom.ParseFormat(opts.Format)
+				if err != nil {
+					return err
+				}
+				format = f
+			}
+			cmd.SilenceUsage = true
+
+			records, err := selectRecords(args, "")
+			if err != nil {
+				return err
+			}
+			if len(records) != 1 {
+				return fmt.Errorf("%q matches %d installed apps, use the full app key", args[0], len(records))
+			}
+
+			data, err := drop.ReadSBOM(records[0])
+			if err != nil {
+				return fmt.Errorf("reading SBOM of %s: %w", records[0].Name, err)
+			}
+
+			var out io.Writer = os.Stdout
+			if opts.Output != "" {
+				f, err := os.Create(opts.Output)
+				if err != nil {
+					return fmt.Errorf("opening output file: %w", err)
+				}
+				defer f.Close() //nolint:errcheck
+				out = f
+			}
+
+			if format == "" {
+				if _, err := out.Write(data); err != nil {
+					return fmt.Errorf("writing SBOM: %w", err)
+				}
+				return nil
+			}
+			return sbom.Convert(data, format, out)
+		},
+	}
+	opts.AddFlags(sbomCmd)
+	parentCmd.AddCommand(sbomCmd)
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+)
+
+func addTrust(parentCmd *cobra.Command) {
+	trustCmd := &cobra.Command{
+		Short: "manages the signer identities trusted for installed apps",
+		Long: fmt.Sprintf(`
+%s
+
+When an app is installed, drop records the identities that signed it (or
+its attestations): the workflow that built it, the minisign key or the GPG
+key. Updates signed by a different identity are refused, as a change of
+signer can mean the project's release process was compromised.
+
+If the change is legitimate, use %s to forget the recorded
+identities. The next update trusts the identities signing it.
+
+`, DropBanner("Manage trusted signers"), w2("drop trust reset <app>")),
+		Use:               "trust",
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+	}
+
+	resetCmd := &cobra.Command{
+		Short: "forgets the signer identities pinned for apps",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand forgets the signer identities recorded for the
+named apps, accepting a change of signer. The identities signing the next
+update are recorded and trusted from then on.
+
+`, DropBanner("Reset the trusted signers"), w2("trust reset")),
+		Use:               "reset app...",
+		Example:           fmt.Sprintf("%s trust reset drop", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("specify the apps to reset")
+			}
+			cmd.SilenceUsage = true
+
+			dropper, err := drop.New()
+			if err != nil {
+				return fmt.Errorf("creating dropper: %w", err)
+			}
+
+			records, err := selectRecords(args, "")
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				pinned, err := dropper.ResetTrust(record)
+				if err != nil {
+					return fmt.Errorf("resetting %s: %w", record.Name, err)
+				}
+				if len(pinned) == 0 {
+					fmt.Printf("  ℹ️  %s has no pinned signers\n", w(record.Name))
+					continue
+				}
+				fmt.Printf("  ✔️  %s: forgot %d signer(s), the next update will pin new ones\n", w(record.Name), len(pinned))
+				for _, signer := range pinned {
+					fmt.Printf("      %s\n", signer)
+				}
+			}
+			return nil
+		},
+	}
+
+	trustCmd.AddCommand(resetCmd)
+	parentCmd.AddCommand(trustCmd)
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+)
+
+type uninstallOptions struct {
+	Scope      string
+	Escalation string
+}
+
+// AddFlags adds the subcommands flags
+func (uo *uninstallOptions) AddFlags(cmd *cobra.Command) {
+	addScopeFlag(cmd, &uo.Scope)
+
+	addEscalationFlag(cmd, &uo.Escalation)
+}
+
+func addUninstall(parentCmd *cobra.Command) {
+	opts := &uninstallOptions{}
+	uninstallCmd := &cobra.Command{
+		Short: "removes apps installed with drop",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand removes the named apps and forgets them:
+
+  - Binaries and AppImages are deleted along with the files installed
+    with them: shell completions, man pages, desktop entries and icons.
+  - System packages are removed with the package manager.
+  - Packages unpacked into a prefix have their files deleted, along
+    with the directories drop created for them once empty.
+
+Apps are looked up in the user and system inventories, use --scope to
+only look in one of them. Removing system-wide installs needs privileges,
+drop runs the privilege escalation helper it finds (sudo, doas, run0 or
+pkexec) when needed. Use --escalation, or the escalation
+key of the configuration file, to choose one.
+
+The verification evidence, SBOMs and VEX documents stored for the apps
+are deleted too.
+
+`, DropBanner("Uninstall apps"), w2("uninstall")),
+		Use:               "uninstall app...",
+		Example:           fmt.Sprintf("%s uninstall --scope user cosign", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("specify the apps to uninstall")
+			}
+			cmd.SilenceUsage = true
+
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			dropper, err := drop.New(drop.WithEscalation(cmp.Or(opts.Escalation, conf.Escalation)))
+			if err != nil {
+				return fmt.Errorf("creating dropper: %w", err)
+			}
+
+			records, err := selectRecords(args, opts.Scope)
+			if err != nil {
+				return err
+			}
+
+			// Authenticate once before the removals start printing
+			if slices.ContainsFunc(records, drop.UninstallRequiresPrivileges) {
+				if err := dropper.Authenticate(); err != nil {
+					return err
+				}
+			}
+
+			errs := []error{}
+			for _, record := range records {
+				if err := dropper.Uninstall(record); err != nil {
+					fmt.Printf("  ❌ uninstalling %s failed: %v\n", record.Name, err)
+					errs = append(errs, fmt.Errorf("uninstalling %s: %w", record.Name, err))
+					continue
+				}
+				fmt.Printf("  🗑️  %s %s uninstalled (%s)\n", w(record.Name), record.Version, record.Scope)
+			}
+
+			if _, err := dropper.PruneEvidence(); err != nil {
+				logrus.Warnf("apps uninstalled, but removing their stored evidence failed: %v", err)
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("%d of %d apps could not be uninstalled: %w", len(errs), len(records), errors.Join(errs...))
+			}
+			return nil
+		},
+	}
+	opts.AddFlags(uninstallCmd)
+	parentCmd.AddCommand(uninstallCmd)
+}
@@ -5,9 +5,11 @@ package cmd
 
 import (
 	"bufio"
+	"cmp"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
@@ -15,11 +17,20 @@ import (
 
 	"github.com/carabiner-dev/drop/internal/notifier"
 	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 )
 
 type updateOptions struct {
-	Yes   bool
-	Quiet bool
+	Yes          bool
+	Quiet        bool
+	Escalation   string
+	MinisignKeys []string
+	GPGKeys      []string
+
+	WarnSignerChange bool
+	MaxSeverity      string
+	IgnoreVulns      bool
+	Scope            string
 }
 
 // AddFlags adds the subcommands flags
@@ -31,6 +42,30 @@ func (uo *updateOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(
 		&uo.Quiet, "quiet", "q", false, "less verbose output (for scripts, etc)",
 	)
+
+	addEscalationFlag(cmd, &uo.Escalation)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&uo.MinisignKeys, "minisign-key", nil, "minisign public key file trusted to sign releases (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&uo.GPGKeys, "gpg-key", nil, "GPG public key file trusted to sign releases (can be repeated)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&uo.WarnSignerChange, "warn-signer-change", false, "update apps signed by a new identity, warning instead of refusing them",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&uo.MaxSeverity, "max-severity", "", "most serious vulnerability accepted: low, medium, high or critical (default medium)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&uo.IgnoreVulns, "ignore-vulns", false, "update apps with vulnerabilities above --max-severity, only reporting them",
+	)
+
+	addScopeFlag(cmd, &uo.Scope)
 }
 
 func addUpdate(parentCmd *cobra.Command) {
@@ -50,7 +85,29 @@ asks for confirmation. Use --yes/-y to skip the confirmation prompt:
 
   drop update -y
 
-`, DropBanner("Update the apps installed with drop"), w2("update"), w2("drop update")),
+When any of the updates needs elevated privileges (system packages or
+binaries in system directories), drop asks for your password once before
+starting so the prompts don't get mixed with the progress output.
+
+Updates must be verified at least as strongly as the installed version
+(see the verification levels in "drop install --help"), so a release that
+stops publishing signatures is refused instead of being installed with a
+weaker check. Apps verified with minisign or GPG signatures need the same
+keys passed again with --minisign-key or --gpg-key.
+
+The identities that signed each app when it was installed are pinned and
+updates signed by someone else (another workflow, repository or key) are
+refused. Pass --warn-signer-change to update them with a warning instead,
+or accept the new identity with %s.
+
+Like installs, updates with vulnerabilities more serious than
+--max-severity are refused unless --ignore-vulns is passed.
+
+Apps installed for the user and system-wide are updated, use --scope user
+or --scope system to update only one of them. Run drop as root to update
+the system-wide apps of every user.
+
+`, DropBanner("Update the apps installed with drop"), w2("update"), w2("drop update"), w2("drop trust reset <app>")),
 		Use:               "update",
 		Example:           fmt.Sprintf("%s update", appname),
 		SilenceUsage:      false,
@@ -64,12 +121,25 @@ asks for confirmation. Use --yes/-y to skip the confirmation prompt:
 				lstnr = &drop.NoopListener{}
 			}
 
-			dropper, err := drop.New(drop.WithListener(lstnr))
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			dropper, err := drop.New(
+				drop.WithListener(lstnr),
+				drop.WithPolicyMap(&conf.Policies),
+				drop.WithPolicySigningKeys(conf.Policies.SigningKeys...),
+				drop.WithEscalation(cmp.Or(opts.Escalation, conf.Escalation)),
+				drop.WithMinisignKeys(opts.MinisignKeys...),
+				drop.WithGPGKeys(opts.GPGKeys...),
+				drop.WithVulnDatabase(conf.Vulnerabilities.Database),
+			)
 			if err != nil {
 				return fmt.Errorf("creating dropper: %w", err)
 			}
 
-			statuses, err := dropper.CheckUpdates()
+			statuses, err := dropper.CheckUpdates(opts.Scope)
 			if err != nil {
 				return err
 			}
@@ -95,8 +165,12 @@ asks for confirmation. Use --yes/-y to skip the confirmation prompt:
 			tw := tabwriter.NewWriter(os.Stdout, 2, 2, 2, ' ', 0)
 			for _, status := range updates {
 				kind := status.Record.Kind
-				if !status.Record.Verified {
+				switch level := status.Record.VerificationLevel; level {
+				case string(integrity.LevelNone):
 					kind += " (unverified)"
+				case string(integrity.LevelPolicy), "":
+				default:
+					kind += " (" + level + ")"
 				}
 				_, _ = fmt.Fprintf( //nolint:errcheck
 					tw, "  %s\t%s → %s\t%s\t%s/%s/%s\n",
@@ -114,10 +188,25 @@ asks for confirmation. Use --yes/-y to skip the confirmation prompt:
 				return nil
 			}
 
+			// Authenticate once before the updates start printing
+			if slices.ContainsFunc(updates, (*drop.UpdateStatus).RequiresPrivileges) {
+				if err := dropper.Authenticate(); err != nil {
+					return err
+				}
+			}
+
+			updateOpts := []drop.FuncGetOption{
+				drop.WithSignerChangeWarning(opts.WarnSignerChange),
+				drop.WithIgnoreVulnerabilities(opts.IgnoreVulns),
+			}
+			if sev := cmp.Or(opts.MaxSeverity, conf.Vulnerabilities.MaxSeverity); sev != "" {
+				updateOpts = append(updateOpts, drop.WithMaxSeverity(sev))
+			}
+
 			errs := []error{}
 			for _, status := range updates {
 				fmt.Printf("\n⬆️  Updating %s to %s:\n", w(status.Record.Name), status.LatestVersion)
-				if err := dropper.Update(status); err != nil {
+				if err := dropper.Update(status, updateOpts...); err != nil {
 					fmt.Printf("  ❌ updating %s failed: %v\n", status.Record.Name, err)
 					errs = append(errs, fmt.Errorf("updating %s: %w", status.Record.Name, err))
 				}
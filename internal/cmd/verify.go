@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+type verifyOptions struct {
+	Offline bool
+	Details bool
+	Scope   string
+}
+
+// AddFlags adds the subcommands flags
+func (vo *verifyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(
+		&vo.Offline, "offline", false, "verify with the policies and attestations stored at install time, without network access",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&vo.Details, "details", false, "print the policy results of every app, not only of those failing",
+	)
+
+	addScopeFlag(cmd, &vo.Scope)
+}
+
+func addVerify(parentCmd *cobra.Command) {
+	opts := &verifyOptions{}
+	attCmd := &cobra.Command{
+		Short: "verifies the apps installed with drop again",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand evaluates the security policies of the apps installed
+with drop again, checking the verified artifact digests recorded in the
+inventory. Binaries and AppImages are also checked to still match the
+verified files.
+
+When installing, drop stores the policies and attestations used to verify
+each app in its data directory (~/.local/share/drop/evidence). With
+--offline, apps are verified with those copies and no network access:
+
+  drop verify --offline
+
+Use %s while online to replace the stored copies with fresh ones.
+
+Pass app names to verify only those apps, or --scope to verify only the
+apps installed for the user or system-wide.
+
+`, DropBanner("Verify the installed apps"), w2("verify"), w2("drop refresh")),
+		Use:               "verify [app...]",
+		Example:           fmt.Sprintf("%s verify --offline", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			dropper, err := drop.New(
+				drop.WithPolicyMap(&conf.Policies),
+				drop.WithPolicySigningKeys(conf.Policies.SigningKeys...),
+			)
+			if err != nil {
+				return fmt.Errorf("creating dropper: %w", err)
+			}
+
+			records, err := selectRecords(args, opts.Scope)
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				fmt.Println("  📭 No apps installed with drop yet.")
+				return nil
+			}
+
+			failed := 0
+			for _, record := range records {
+				passed, results, err := dropper.VerifyInstalled(record, opts.Offline)
+				switch {
+				case err != nil:
+					failed++
+					fmt.Printf("  ❌ %s %s: verification failed: %v\n", w(record.Name), record.Version, err)
+				case !passed:
+					failed++
+					fmt.Printf("  ❌ %s %s does not pass its policies\n", w(record.Name), record.Version)
+				default:
+					fmt.Printf("  ✅ %s %s verified\n", w(record.Name), record.Version)
+				}
+				if results != nil && (opts.Details || !passed) {
+					printVerificationReport(os.Stdout, drop.NewVerificationReport(record.Asset, results))
+				}
+			}
+
+			fmt.Println()
+			if failed > 0 {
+				return fmt.Errorf("%d of %d apps failed verification", failed, len(records))
+			}
+			fmt.Printf("  ✨ %d app(s) verified!\n", len(records))
+			return nil
+		},
+	}
+	opts.AddFlags(attCmd)
+	parentCmd.AddCommand(attCmd)
+}
+
+// selectRecords returns the inventory records of the named apps, or all of
+// them when no names are specified. A non-empty scope only selects the apps
+// installed in it.
+func selectRecords(names []string, scope string) ([]*inventory.Record, error) {
+	records, err := inventory.Installed(scope)
+	if err != nil {
+		return nil, fmt.Errorf("opening install inventory: %w", err)
+	}
+
+	ret := []*inventory.Record{}
+	found := map[string]bool{}
+	for _, record := range records {
+		key := record.Key()
+		if len(names) > 0 && !slices.Contains(names, record.Name) && !slices.Contains(names, key) {
+			continue
+		}
+		found[record.Name] = true
+		found[key] = true
+		ret = append(ret, record)
+	}
+	for _, name := range names {
+		if !found[name] {
+			if scope != "" {
+				return nil, fmt.Errorf("app %q is not installed with drop in the %s scope", name, scope)
+			}
+			return nil, fmt.Errorf("app %q is not installed with drop", name)
+		}
+	}
+	return ret, nil
+}
+
+// addEscalationFlag adds the --escalation flag choosing the privilege
+// escalation helper, which overrides the escalation configuration key
+func addEscalationFlag(cmd *cobra.Command, helper *string) {
+	cmd.PersistentFlags().StringVar(
+		helper, "escalation", "", fmt.Sprintf("privilege escalation helper %v, from the configuration or detected when not set", drop.EscalationHelpers),
+	)
+}
+
+// addScopeFlag adds the --scope flag selecting the inventory of the apps
+func addScopeFlag(cmd *cobra.Command, scope *string) {
+	cmd.PersistentFlags().StringVar(
+		scope, "scope", "", fmt.Sprintf("only the apps installed in a scope %v, both when not set", inventory.Scopes),
+	)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/vuln"
+)
+
+type vulnsOptions struct {
+	MaxSeverity string
+	Dismissed   bool
+}
+
+// AddFlags adds the subcommands flags
+func (vo *vulnsOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&vo.MaxSeverity, "max-severity", "", "most serious vulnerability accepted: low, medium, high or critical (default medium)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&vo.Dismissed, "dismissed", false, "also print the vulnerabilities dismissed by VEX documents",
+	)
+}
+
+func addVulns(parentCmd *cobra.Command) {
+	opts := &vulnsOptions{}
+	vulnsCmd := &cobra.Command{
+		Short: "scans the installed apps for known vulnerabilities",
+		Long: fmt.Sprintf(`
+%s
+
+The %s subcommand looks up the installed apps and the components listed
+in their SBOMs in a local copy of the OSV vulnerability database. Scans
+need no network access.
+
+The database is read from ~/.local/share/drop/osv, or the directory set in
+the vulnerabilities section of the configuration file. It holds the OSV
+JSON records or the ecosystem archives published by OSV, for example:
+
+  mkdir -p ~/.local/share/drop/osv
+  curl -o ~/.local/share/drop/osv/Go.zip \
+    https://osv-vulnerabilities.storage.googleapis.com/Go/all.zip
+
+Download the GIT archive too to match apps by their repository tags.
+
+Vulnerabilities that the OpenVEX documents published in a release state
+do not affect the app are dismissed. The documents are stored when the
+app is installed.
+
+The command fails when a vulnerability is more serious than
+--max-severity. Pass app names to scan only those apps.
+
+`, DropBanner("Scan the installed apps for vulnerabilities"), w2("vulns")),
+		Use:               "vulns [app...]",
+		Example:           fmt.Sprintf("%s vulns --max-severity high", appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			maxSeverity := vuln.SeverityMedium
+			if sev := cmp.Or(opts.MaxSeverity, conf.Vulnerabilities.MaxSeverity); sev != "" {
+				if maxSeverity, err = vuln.ParseSeverity(sev); err != nil {
+					return err
+				}
+			}
+			cmd.SilenceUsage = true
+
+			dropper, err := drop.New(drop.WithVulnDatabase(conf.Vulnerabilities.Database))
+			if err != nil {
+				return fmt.Errorf("creating dropper: %w", err)
+			}
+
+			records, err := selectRecords(args, "")
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				fmt.Println("  📭 No apps installed with drop yet.")
+				return nil
+			}
+
+			reports, err := dropper.ScanInstalled(records)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for i, record := range records {
+				report := reports[i]
+				exceeding := report.Exceeding(maxSeverity)
+				switch {
+				case len(exceeding) > 0:
+					failed++
+					fmt.Printf("  ❌ %s %s: %d vulnerabilities above %s\n", w(record.Name), record.Version, len(exceeding), maxSeverity)
+				case len(report.Findings) > 0:
+					fmt.Printf("  ⚠️  %s %s: %d vulnerabilities\n", w(record.Name), record.Version, len(report.Findings))
+				default:
+					fmt.Printf("  ✅ %s %s: no known vulnerabilities\n", w(record.Name), record.Version)
+				}
+				for _, f := range report.Findings {
+					fmt.Printf("     🐛 %s\n", f)
+				}
+				if opts.Dismissed {
+					for _, f := range report.Dismissed {
+						fmt.Printf("     🔇 %s (dismissed by VEX)\n", f)
+					}
+				}
+			}
+
+			fmt.Println()
+			if failed > 0 {
+				return fmt.Errorf("%d of %d apps have vulnerabilities above %s", failed, len(records), maxSeverity)
+			}
+			fmt.Printf("  ✨ %d app(s) scanned!\n", len(records))
+			return nil
+		},
+	}
+	opts.AddFlags(vulnsCmd)
+	parentCmd.AddCommand(vulnsCmd)
+}
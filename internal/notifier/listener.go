@@ -6,10 +6,12 @@ package notifier
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 
 	"github.com/carabiner-dev/drop/pkg/drop"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 )
 
 var w = color.New(color.FgHiWhite, color.BgBlack).SprintFunc()
@@ -27,6 +29,9 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 			if s := event.GetDataField("repo"); s != "" {
 				repo = fmt.Sprintf(" (source: %s)", s)
 			}
+			if s := event.GetDataField("path"); s != "" {
+				repo = fmt.Sprintf(" (local files: %s)", s)
+			}
 			fmt.Printf("  💫 %s%s\n", w("Looking for policies"), repo)
 		case drop.EventVerbDone:
 			sets := "0"
@@ -34,6 +39,13 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 				sets = s
 			}
 			fmt.Printf("      ✔️  %s policy sets found\n", sets)
+			if commit := event.GetDataField("commit"); commit != "" {
+				signed := ""
+				if signer := event.GetDataField("signer"); signer != "" {
+					signed = ", signed by " + signer
+				}
+				fmt.Printf("      ✔️  policy repository at commit %s%s\n", commit, signed)
+			}
 		}
 	case drop.EventObjectAsset:
 		switch event.Verb {
@@ -53,6 +65,19 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 			fmt.Printf("  ⏬ %s%s\n", w(fmt.Sprintf("Downloading %s", f)), size)
 		case drop.EventVerbDone:
 			fmt.Println("      ✔️  done")
+		case drop.EventVerbFallback:
+			platform := fmt.Sprintf("%s/%s", event.GetDataField("os"), event.GetDataField("wanted"))
+			if event.GetDataField("emulated") == "true" {
+				fmt.Printf("  ⚠️  %s\n", w(fmt.Sprintf(
+					"No %s build found, using the %s build which runs under emulation",
+					platform, event.GetDataField("arch"),
+				)))
+			} else {
+				fmt.Printf("  ℹ️  %s\n", w(fmt.Sprintf(
+					"No %s build found, falling back to the compatible %s build",
+					platform, event.GetDataField("arch"),
+				)))
+			}
 		case drop.EventVerbSaved:
 			p := ""
 			if s := event.GetDataField("path"); s != "" {
@@ -60,19 +85,36 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 			}
 			fmt.Printf("  💾 %s%s\n", w("Download complete!"), p)
 		}
+	case drop.EventObjectExtras:
+		if event.Verb == drop.EventVerbDone {
+			fmt.Printf("  📚 %s\n", w(fmt.Sprintf("Installed %s completion and man page files into %s",
+				event.GetDataField("count"), event.GetDataField("path"))))
+		}
 	case drop.EventObjectInstall:
 		switch event.Verb {
 		case drop.EventVerbRunning:
 			sudo := ""
-			if event.GetDataField("sudo") == "true" {
-				sudo = " with sudo (you may be asked for your password)"
+			if helper := event.GetDataField("escalation"); helper != "" {
+				sudo = fmt.Sprintf(" with %s (you may be asked for your password)", helper)
 			}
 			if event.GetDataField("kind") == string(drop.ArtifactPackage) {
 				format := event.GetDataField("format")
-				fmt.Printf("  📦 %s\n", w(fmt.Sprintf("Installing %s package%s...", format, sudo)))
+				pkg := ""
+				if v := event.GetDataField("version"); v != "" {
+					pkg = fmt.Sprintf(" %s %s", event.GetDataField("name"), v)
+				}
+				if prefix := event.GetDataField("prefix"); prefix != "" {
+					fmt.Printf("  📦 %s\n", w(fmt.Sprintf("Unpacking %s package%s into %s...", format, pkg, prefix)))
+				} else {
+					fmt.Printf("  📦 %s\n", w(fmt.Sprintf("Installing %s package%s%s...", format, pkg, sudo)))
+				}
 			} else {
+				what := "binary"
+				if event.GetDataField("kind") == string(drop.ArtifactAppImage) {
+					what = "AppImage"
+				}
 				target := event.GetDataField("target")
-				fmt.Printf("  🔧 %s\n", w(fmt.Sprintf("Installing binary to %s%s...", target, sudo)))
+				fmt.Printf("  🔧 %s\n", w(fmt.Sprintf("Installing %s to %s%s...", what, target, sudo)))
 			}
 		case drop.EventVerbDone:
 			name := "app"
@@ -85,6 +127,47 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 				fmt.Printf("      ℹ️  %s\n", reason)
 			}
 		}
+	case drop.EventObjectPath:
+		switch event.Verb {
+		case drop.EventVerbMissing:
+			fmt.Printf("  ⚠️  %s is not in your PATH, run \"drop path --append\" to add it\n", event.GetDataField("dir"))
+		case drop.EventVerbShadowed:
+			fmt.Printf("  ⚠️  %s is shadowed by %s, which comes first in your PATH\n",
+				event.GetDataField("path"), event.GetDataField("by"))
+		}
+	case drop.EventObjectSBOM:
+		if event.Verb == drop.EventVerbSaved {
+			attested := ""
+			if event.GetDataField("attested") == "true" {
+				attested = ", matches its attestation"
+			}
+			fmt.Printf("  📋 %s\n", w(fmt.Sprintf("Stored %s SBOM %s%s",
+				event.GetDataField("format"), event.GetDataField("name"), attested)))
+		}
+	case drop.EventObjectVulnerabilities:
+		switch event.Verb {
+		case drop.EventVerbRunning:
+			fmt.Printf("  🐛 %s\n", w("Scanning for known vulnerabilities..."))
+		case drop.EventVerbDone:
+			components := ""
+			if n := event.GetDataField("components"); n != "" && n != "0" {
+				components = fmt.Sprintf(" (app and %s SBOM components)", n)
+			}
+			fmt.Printf("      ✔️  %s vulnerabilities found%s\n", event.GetDataField("count"), components)
+			if n := event.GetDataField("dismissed"); n != "" && n != "0" {
+				fmt.Printf("      ℹ️  %s dismissed by the release VEX documents\n", n)
+			}
+			if findings := event.GetDataField("findings"); findings != "" {
+				for _, line := range strings.Split(findings, "\n") {
+					fmt.Printf("      ⚠️  %s\n", line)
+				}
+			}
+		}
+	case drop.EventObjectSigners:
+		if event.Verb == drop.EventVerbChanged {
+			fmt.Printf("  ⚠️  %s\n", w(fmt.Sprintf("Signer changed: now signed by %s", event.GetDataField("signers"))))
+			fmt.Printf("      pinned: %s\n", event.GetDataField("pinned"))
+		}
 	case drop.EventObjectVerification:
 		switch event.Verb {
 		case drop.EventVerbRunning:
@@ -92,7 +175,12 @@ func (l *Listener) HandleEvent(event *drop.Event) {
 		case drop.EventVerbSkipped:
 			fmt.Printf("  🚫  %s\n", w("Security verification skipped"))
 		case drop.EventVerbDone:
-			if s := event.GetDataField("passed"); s != "" {
+			if level := event.GetDataField("level"); level != "" {
+				fmt.Printf("      ✅  %s: %s\n", level, event.GetDataField("details"))
+				if level == string(integrity.LevelChecksum) {
+					fmt.Println("      ⚠️  checksums catch corrupted downloads but not a compromised release")
+				}
+			} else if s := event.GetDataField("passed"); s != "" {
 				if s == "true" {
 					fmt.Println("      ✅  PASS")
 				} else {
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package config reads the drop configuration file. The file is YAML and
+// lives in the drop directory of the user's configuration directory, next
+// to the inventory. A missing file is the same as an empty configuration.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+)
+
+const dirName = "drop"
+
+// FileName is the name of the configuration file
+const FileName = "config.yaml"
+
+// Config is the drop configuration. Escalation is the privilege escalation
+// helper (sudo, doas, run0 or pkexec) drop runs privileged commands with,
+// unless one is chosen with --escalation. When empty, the first one found
+// is used.
+//
+//	escalation: doas
+type Config struct {
+	Policies        PolicyMap       `yaml:"policies"`
+	Vulnerabilities Vulnerabilities `yaml:"vulnerabilities"`
+	Escalation      string          `yaml:"escalation,omitempty"`
+}
+
+// Vulnerabilities configures the scanning of apps for known
+// vulnerabilities. Database is the local OSV database, a directory of OSV
+// records or zip archives (by default ~/.local/share/drop/osv). Installs
+// and updates affected by vulnerabilities more serious than MaxSeverity
+// (low, medium, high or critical) are refused.
+//
+//	vulnerabilities:
+//	  database: ~/osv
+//	  maxSeverity: high
+type Vulnerabilities struct {
+	Database    string `yaml:"database,omitempty"`
+	MaxSeverity string `yaml:"maxSeverity,omitempty"`
+}
+
+// PolicyMap chooses the repository to read the policies of an artifact from.
+// Repositories maps host/org or host/org/repo patterns to policy
+// repositories, the most specific pattern matching an artifact wins. Default
+// is used for artifacts matching no pattern, when empty the policies are
+// read from the .ampel repository of the artifact's org. Repositories can be
+// pinned to a branch, tag or commit with an @ref suffix.
+//
+// SigningKeys are GPG public key files trusted to sign the commits of the
+// policy repositories. When set, policies are only read from signed commits.
+//
+//	policies:
+//	  default: github.com/example/policies@v1.2.0
+//	  repositories:
+//	    github.com/sigstore: github.com/example/sigstore-policies
+//	    github.com/cli/cli: github.com/example/gh-policies@4b8c2f1
+//	  signingKeys:
+//	    - ~/.config/drop/policy-team.asc
+type PolicyMap struct {
+	Default      string            `yaml:"default,omitempty"`
+	Repositories map[string]string `yaml:"repositories,omitempty"`
+	SigningKeys  []string          `yaml:"signingKeys,omitempty"`
+}
+
+// DefaultPath returns the location of the configuration file in the user's
+// configuration directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user configuration directory: %w", err)
+	}
+	return filepath.Join(dir, dirName, FileName), nil
+}
+
+// Load reads the configuration from its default location
+func Load() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the configuration from a file, returning an empty
+// configuration if it does not exist.
+func LoadFile(path string) (*Config, error) {
+	conf := &Config{}
+	data, err := os.ReadFile(path) //nolint:gosec // reading the configuration is the point
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return conf, nil
+		}
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("parsing configuration %s: %w", path, err)
+	}
+	if err := conf.Policies.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid policy repositories in %s: %w", path, err)
+	}
+
+	// Key files and the database are relative to the configuration file
+	for i, key := range conf.Policies.SigningKeys {
+		conf.Policies.SigningKeys[i] = relativeTo(path, key)
+	}
+	if conf.Vulnerabilities.Database != "" {
+		conf.Vulnerabilities.Database = relativeTo(path, conf.Vulnerabilities.Database)
+	}
+	return conf, nil
+}
+
+// relativeTo resolves a relative path against the directory of the
+// configuration file, paths starting with ~ are left for the caller.
+func relativeTo(confPath, path string) string {
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+	return filepath.Join(filepath.Dir(confPath), path)
+}
+
+// normalize checks the patterns and turns the repositories into URLs
+func (pm *PolicyMap) normalize() error {
+	if pm.Default != "" {
+		u, err := normalizeRepo(pm.Default)
+		if err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+		pm.Default = u
+	}
+
+	repos := make(map[string]string, len(pm.Repositories))
+	for pattern, repo := range pm.Repositories {
+		key := strings.ToLower(strings.Trim(pattern, "/"))
+		if parts := strings.Split(key, "/"); len(parts) < 2 || len(parts) > 3 || strings.Contains(key, "//") {
+			return fmt.Errorf("pattern %q must be host/org or host/org/repo", pattern)
+		}
+		u, err := normalizeRepo(repo)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pattern, err)
+		}
+		repos[key] = u
+	}
+	pm.Repositories = repos
+	return nil
+}
+
+// normalizeRepo turns a policy repository into its URL, keeping the ref it
+// is pinned to.
+func normalizeRepo(repo string) (string, error) {
+	u, ref, err := github.RepoRefFromString(repo)
+	if err != nil {
+		return "", err
+	}
+	if ref != "" {
+		u += "@" + ref
+	}
+	return u, nil
+}
+
+// Resolve returns the policy repository for the artifacts of a repository,
+// or an empty string when the map has no entry or default for it.
+func (pm *PolicyMap) Resolve(host, org, repo string) string {
+	if pm == nil {
+		return ""
+	}
+	prefix := strings.ToLower(host + "/" + org)
+	if u, ok := pm.Repositories[prefix+"/"+strings.ToLower(repo)]; ok {
+		return u
+	}
+	if u, ok := pm.Repositories[prefix]; ok {
+		return u
+	}
+	return pm.Default
+}
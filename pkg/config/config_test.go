@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		expect  PolicyMap
+		mustErr bool
+	}{
+		{name: "empty", data: "", expect: PolicyMap{Repositories: map[string]string{}}},
+		{
+			name: "mapped",
+			data: "policies:\n  default: github.com/example/policies\n  repositories:\n    GitHub.com/Sigstore/: https://github.com/example/sigstore\n",
+			expect: PolicyMap{
+				Default:      "https://github.com/example/policies",
+				Repositories: map[string]string{"github.com/sigstore": "https://github.com/example/sigstore"},
+			},
+		},
+		{
+			name: "pinned",
+			data: "policies:\n  default: github.com/example/policies@v1.2.0\n  signingKeys: [team.asc, /etc/drop/key.asc]\n",
+			expect: PolicyMap{
+				Default:      "https://github.com/example/policies@v1.2.0",
+				Repositories: map[string]string{},
+				SigningKeys:  []string{"team.asc", "/etc/drop/key.asc"},
+			},
+		},
+		{name: "bad-pattern", data: "policies:\n  repositories:\n    sigstore: github.com/example/sigstore\n", mustErr: true},
+		{name: "bad-repo", data: "policies:\n  repositories:\n    github.com/sigstore: example\n", mustErr: true},
+		{name: "bad-yaml", data: "policies: [", mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), FileName)
+			require.NoError(t, os.WriteFile(path, []byte(tc.data), 0o600))
+			conf, err := LoadFile(path)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for i, key := range tc.expect.SigningKeys {
+				if !filepath.IsAbs(key) {
+					tc.expect.SigningKeys[i] = filepath.Join(filepath.Dir(path), key)
+				}
+			}
+			require.Equal(t, tc.expect, conf.Policies)
+		})
+	}
+}
+
+func TestLoadFileVulnerabilities(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		database string
+		expect   string
+	}{
+		{name: "relative", database: "osv", expect: "osv"},
+		{name: "absolute", database: "/srv/osv", expect: "/srv/osv"},
+		{name: "home", database: "~/osv", expect: "~/osv"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), FileName)
+			data := "vulnerabilities:\n  database: " + tc.database + "\n  maxSeverity: high\n"
+			require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+			conf, err := LoadFile(path)
+			require.NoError(t, err)
+			if tc.name == "relative" {
+				tc.expect = filepath.Join(filepath.Dir(path), tc.expect)
+			}
+			require.Equal(t, tc.expect, conf.Vulnerabilities.Database)
+			require.Equal(t, "high", conf.Vulnerabilities.MaxSeverity)
+		})
+	}
+}
+
+func TestLoadFileEscalation(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("escalation: doas\n"), 0o600))
+	conf, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "doas", conf.Escalation)
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	t.Parallel()
+	conf, err := LoadFile(filepath.Join(t.TempDir(), FileName))
+	require.NoError(t, err)
+	require.Empty(t, conf.Policies.Resolve("github.com", "org", "repo"))
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	pm := &PolicyMap{
+		Default: "https://github.com/example/policies",
+		Repositories: map[string]string{
+			"github.com/sigstore":        "https://github.com/example/sigstore",
+			"github.com/sigstore/cosign": "https://github.com/example/cosign",
+		},
+	}
+	require.Equal(t, "https://github.com/example/cosign", pm.Resolve("github.com", "sigstore", "cosign"))
+	require.Equal(t, "https://github.com/example/sigstore", pm.Resolve("github.com", "Sigstore", "rekor"))
+	require.Equal(t, "https://github.com/example/policies", pm.Resolve("github.com", "cli", "cli"))
+
+	var empty *PolicyMap
+	require.Empty(t, empty.Resolve("github.com", "cli", "cli"))
+}
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/png" // Decodes the size of png icons
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	appImageSuffix     = ".AppImage"
+	appImageExtractDir = "squashfs-root"
+	appImageExtractArg = "--appimage-extract"
+)
+
+// SystemAppsDir is where AppImages are installed system-wide
+const SystemAppsDir = "/opt/drop/apps"
+
+// systemDataDir is the data directory of the desktop entries and icons of
+// AppImages installed system-wide
+const systemDataDir = "/usr/local/share"
+
+// defaultAppsDir returns the directory where AppImages of a scope are
+// installed when none is configured: ~/Applications for the user and
+// SystemAppsDir for system-wide installs.
+func defaultAppsDir(scope string) (string, error) {
+	if scope == ScopeSystem {
+		return SystemAppsDir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, "Applications"), nil
+}
+
+// appImageDir returns the directory AppImages are installed into
+func appImageDir(opts *GetOptions) (string, error) {
+	if opts.AppsDir != "" {
+		return opts.AppsDir, nil
+	}
+	return defaultAppsDir(opts.Scope)
+}
+
+// appImageDataDir returns the data directory where the desktop entries and
+// icons of AppImages are installed: /usr/local/share for system installs,
+// where every user's desktop finds them, or the user's XDG data directory.
+func appImageDataDir(opts *GetOptions) (string, error) {
+	if opts.Scope == ScopeSystem {
+		return systemDataDir, nil
+	}
+	return xdgDataHome()
+}
+
+// xdgDataHome returns the base directory for user data files
+func xdgDataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// installAppImage copies the AppImage into the applications directory with
+// the executable bit set and, if enabled, installs its desktop entry and
+// icon. The written files are recorded in the artifact.
+func (di *defaultImplementation) installAppImage(
+	opts *GetOptions, artifact *InstallArtifact, path string,
+) error {
+	dir, err := appImageDir(opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating applications directory: %w", err)
+	}
+
+	target := filepath.Join(dir, artifact.InstallName+appImageSuffix)
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectInstall, Verb: EventVerbRunning,
+		Data: map[string]string{
+			dataKeyKind: string(ArtifactAppImage),
+			dataKeyName: artifact.InstallName,
+			"target":    target,
+		},
+	})
+
+	// Write to a temporary file first, the AppImage may be running
+	tmp := target + ".drop-new"
+	if err := copyFile(path, tmp, 0o755); err != nil {
+		return fmt.Errorf("installing AppImage: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp) //nolint:errcheck
+		return fmt.Errorf("installing AppImage: %w", err)
+	}
+	artifact.Files = []string{target}
+
+	if opts.DesktopIntegration {
+		dataHome, err := appImageDataDir(opts)
+		if err != nil {
+			return err
+		}
+		files, err := di.integrateAppImage(artifact.InstallName, target, dataHome)
+		artifact.Files = append(artifact.Files, files...)
+		if err != nil {
+			logrus.Debugf("desktop integration failed: %v", err)
+			opts.Listener.HandleEvent(&Event{
+				Object: EventObjectInstall, Verb: EventVerbSkipped,
+				Data: map[string]string{"reason": "desktop entry not installed: " + err.Error()},
+			})
+		}
+	}
+
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectInstall, Verb: EventVerbDone,
+		Data: map[string]string{
+			dataKeyKind: string(ArtifactAppImage),
+			dataKeyName: artifact.InstallName,
+			"path":      target,
+		},
+	})
+	return nil
+}
+
+// integrateAppImage extracts the desktop entry and icon embedded in the
+// AppImage and installs them into the XDG data directories so the app shows
+// up in the desktop menus. The entry is rewritten to launch the installed
+// AppImage. The extraction runs the (verified) AppImage itself, as its
+// runtime knows how to read the embedded filesystem.
+func (di *defaultImplementation) integrateAppImage(name, appImage, dataHome string) ([]string, error) {
+	tmp, err := os.MkdirTemp("", "drop-appimage-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+
+	if err := di.runner.RunSilentIn(tmp, []string{appImage, appImageExtractArg, "*.desktop"}); err != nil {
+		return nil, fmt.Errorf("extracting desktop entry: %w", err)
+	}
+	root := filepath.Join(tmp, appImageExtractDir)
+	entries, err := filepath.Glob(filepath.Join(root, "*.desktop"))
+	if err != nil || len(entries) == 0 {
+		return nil, errors.New("AppImage has no desktop entry")
+	}
+	entry, err := os.ReadFile(entries[0])
+	if err != nil {
+		return nil, fmt.Errorf("reading desktop entry: %w", err)
+	}
+
+	iconName := "drop-" + name
+	files := []string{}
+
+	// The icon is best effort, a missing one does not block the entry
+	if icon := desktopEntryValue(entry, "Icon"); icon != "" && !strings.Contains(icon, "/") {
+		if err := di.runner.RunSilentIn(tmp, []string{appImage, appImageExtractArg, icon + ".*"}); err != nil {
+			logrus.Debugf("extracting AppImage icon: %v", err)
+		}
+		if path, err := installAppImageIcon(root, icon, iconName, dataHome); err != nil {
+			logrus.Debugf("installing AppImage icon: %v", err)
+		} else {
+			files = append(files, path)
+		}
+	}
+
+	desktopPath := filepath.Join(dataHome, "applications", iconName+".desktop")
+	if err := os.MkdirAll(filepath.Dir(desktopPath), 0o755); err != nil {
+		return files, fmt.Errorf("creating applications directory: %w", err)
+	}
+	if err := os.WriteFile(desktopPath, rewriteDesktopEntry(entry, appImage, iconName), 0o644); err != nil { //nolint:gosec // desktop entries are world readable
+		return files, fmt.Errorf("writing desktop entry: %w", err)
+	}
+	return append([]string{desktopPath}, files...), nil
+}
+
+// installAppImageIcon copies the extracted icon into the hicolor theme,
+// sized from the png header (svg icons go to the scalable directory).
+func installAppImageIcon(root, icon, iconName, dataHome string) (string, error) {
+	for _, ext := range []string{".svg", ".png"} {
+		src := filepath.Join(root, icon+ext)
+		data, err := os.ReadFile(src) //nolint:gosec // file extracted from the verified AppImage
+		if err != nil {
+			continue
+		}
+
+		size := "scalable"
+		if ext == ".png" {
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				return "", fmt.Errorf("reading icon size: %w", err)
+			}
+			size = fmt.Sprintf("%dx%d", cfg.Width, cfg.Height)
+		}
+
+		dst := filepath.Join(dataHome, "icons", "hicolor", size, "apps", iconName+ext)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return "", fmt.Errorf("creating icons directory: %w", err)
+		}
+		if err := os.WriteFile(dst, data, 0o644); err != nil { //nolint:gosec // icons are world readable
+			return "", fmt.Errorf("writing icon: %w", err)
+		}
+		return dst, nil
+	}
+	return "", fmt.Errorf("icon %q not found in AppImage", icon)
+}
+
+// desktopEntryValue returns the value of a key in the [Desktop Entry] group
+func desktopEntryValue(entry []byte, key string) string {
+	inGroup := false
+	scanner := bufio.NewScanner(strings.NewReader(string(entry)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inGroup = line == "[Desktop Entry]"
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if inGroup && ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// rewriteDesktopEntry points the Exec and TryExec keys (in all groups, to
+// cover desktop actions) to the installed AppImage and sets the icon name.
+func rewriteDesktopEntry(entry []byte, appImage, iconName string) []byte {
+	var out strings.Builder
+	quoted := `"` + strings.ReplaceAll(appImage, `"`, `\"`) + `"`
+	scanner := bufio.NewScanner(strings.NewReader(string(entry)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		k, v, ok := strings.Cut(line, "=")
+		switch key := strings.TrimSpace(k); {
+		case ok && key == "Exec":
+			// Keep the arguments (%U, %F...) after the program
+			_, args, _ := strings.Cut(strings.TrimSpace(v), " ")
+			line = strings.TrimSpace("Exec=" + quoted + " " + args)
+		case ok && key == "TryExec":
+			line = "TryExec=" + appImage
+		case ok && key == "Icon":
+			line = "Icon=" + iconName
+		}
+		out.WriteString(line + "\n")
+	}
+	return []byte(out.String())
+}
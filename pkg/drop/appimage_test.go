@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+const testDesktopEntry = `[Desktop Entry]
+Name=Drop
+Exec=drop-gui %U
+TryExec=drop-gui
+Icon=drop-gui
+Type=Application
+
+[Desktop Action New]
+Exec=drop-gui --new-window
+`
+
+func TestRewriteDesktopEntry(t *testing.T) {
+	t.Parallel()
+	res := string(rewriteDesktopEntry([]byte(testDesktopEntry), "/home/u/Applications/drop.AppImage", "drop-drop"))
+	require.Contains(t, res, "Exec=\"/home/u/Applications/drop.AppImage\" %U\n")
+	require.Contains(t, res, "Exec=\"/home/u/Applications/drop.AppImage\" --new-window\n")
+	require.Contains(t, res, "TryExec=/home/u/Applications/drop.AppImage\n")
+	require.Contains(t, res, "Icon=drop-drop\n")
+	require.Contains(t, res, "Name=Drop\n")
+	require.Equal(t, "drop-gui", desktopEntryValue([]byte(testDesktopEntry), "Icon"))
+	require.Empty(t, desktopEntryValue([]byte(testDesktopEntry), "Missing"))
+}
+
+// fakeExtractor simulates --appimage-extract writing the files matching the
+// pattern into squashfs-root
+func fakeExtractor(t *testing.T, files map[string][]byte) func(string, []string) error {
+	t.Helper()
+	return func(dir string, argv []string) error {
+		root := filepath.Join(dir, appImageExtractDir)
+		if err := os.MkdirAll(root, 0o755); err != nil {
+			return err
+		}
+		for name, data := range files {
+			if ok, _ := filepath.Match(argv[len(argv)-1], name); ok { //nolint:errcheck
+				if err := os.WriteFile(filepath.Join(root, name), data, 0o600); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func TestAppImageDirs(t *testing.T) {
+	t.Parallel()
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	dir, err := appImageDir(&GetOptions{Scope: ScopeUser})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "Applications"), dir)
+
+	dir, err = appImageDir(&GetOptions{Scope: ScopeSystem})
+	require.NoError(t, err)
+	require.Equal(t, SystemAppsDir, dir)
+
+	dir, err = appImageDir(&GetOptions{Scope: ScopeSystem, AppsDir: "/srv/apps"})
+	require.NoError(t, err)
+	require.Equal(t, "/srv/apps", dir)
+
+	dir, err = appImageDataDir(&GetOptions{Scope: ScopeSystem})
+	require.NoError(t, err)
+	require.Equal(t, "/usr/local/share", dir)
+}
+
+func TestInstallAppImage(t *testing.T) {
+	t.Parallel()
+	var icon bytes.Buffer
+	require.NoError(t, png.Encode(&icon, image.NewRGBA(image.Rect(0, 0, 64, 64))))
+
+	src := filepath.Join(t.TempDir(), "Drop-1.0.0-x86_64.AppImage")
+	require.NoError(t, os.WriteFile(src, []byte("appimage"), 0o600))
+
+	t.Run("install", func(t *testing.T) {
+		t.Parallel()
+		di := &defaultImplementation{runner: &fakeRunner{}}
+		opts := &GetOptions{AppsDir: filepath.Join(t.TempDir(), "Applications")}
+		opts.Listener = &NoopListener{}
+		artifact := &InstallArtifact{Kind: ArtifactAppImage, InstallName: testAppName}
+
+		require.NoError(t, di.InstallAsset(opts, nil, artifact, src))
+		target := filepath.Join(opts.AppsDir, testAppName+appImageSuffix)
+		require.Equal(t, []string{target}, artifact.Files)
+		st, err := os.Stat(target)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o755), st.Mode().Perm())
+	})
+
+	t.Run("desktop-integration", func(t *testing.T) {
+		t.Parallel()
+		runner := &fakeRunner{inDir: fakeExtractor(t, map[string][]byte{
+			"drop-gui.desktop": []byte(testDesktopEntry),
+			"drop-gui.png":     icon.Bytes(),
+		})}
+		di := &defaultImplementation{runner: runner}
+		dataHome := t.TempDir()
+
+		files, err := di.integrateAppImage(testAppName, "/apps/drop.AppImage", dataHome)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dataHome, "applications", "drop-drop.desktop"),
+			filepath.Join(dataHome, "icons", "hicolor", "64x64", "apps", "drop-drop.png"),
+		}, files)
+		require.Len(t, runner.silent, 2)
+		require.Equal(t, []string{"/apps/drop.AppImage", appImageExtractArg, "drop-gui.*"}, runner.silent[1])
+
+		entry, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		require.True(t, strings.Contains(string(entry), "Icon=drop-drop\n"))
+	})
+
+	t.Run("uninstall", func(t *testing.T) {
+		t.Parallel()
+		di := &defaultImplementation{runner: &fakeRunner{inDir: fakeExtractor(t, map[string][]byte{
+			"drop-gui.desktop": []byte(testDesktopEntry),
+			"drop-gui.png":     icon.Bytes(),
+		})}}
+		dir := t.TempDir()
+		opts := &GetOptions{AppsDir: filepath.Join(dir, "Applications")}
+		opts.Listener = &NoopListener{}
+		artifact := &InstallArtifact{Kind: ArtifactAppImage, InstallName: testAppName}
+		require.NoError(t, di.InstallAsset(opts, nil, artifact, src))
+
+		files, err := di.integrateAppImage(testAppName, artifact.Files[0], filepath.Join(dir, "share"))
+		require.NoError(t, err)
+		artifact.Files = append(artifact.Files, files...)
+		require.Len(t, artifact.Files, 3)
+
+		record := &inventory.Record{
+			Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Name: testAppName,
+			Kind: string(ArtifactAppImage), BinPath: artifact.Files[0], Files: artifact.Files,
+		}
+		invPath := filepath.Join(dir, inventory.FileName)
+		inv, err := inventory.OpenFile(invPath)
+		require.NoError(t, err)
+		inv.Add(record)
+		require.NoError(t, inv.Save())
+
+		di.inventoryPath = invPath
+		require.NoError(t, di.UninstallApp(&Options{}, record))
+		for _, f := range artifact.Files {
+			require.NoFileExists(t, f)
+		}
+		inv, err = inventory.OpenFile(invPath)
+		require.NoError(t, err)
+		require.Nil(t, inv.Get(record.Key()))
+	})
+
+	t.Run("no-desktop-entry", func(t *testing.T) {
+		t.Parallel()
+		di := &defaultImplementation{runner: &fakeRunner{inDir: fakeExtractor(t, nil)}}
+		_, err := di.integrateAppImage(testAppName, "/apps/drop.AppImage", t.TempDir())
+		require.Error(t, err)
+	})
+}
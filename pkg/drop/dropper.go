@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 )
 
 const defaultPolicyRepo = ".ampel"
@@ -73,12 +74,14 @@ func (dropper *Dropper) Get(spec github.AssetDataProvider, funcs ...FuncGetOptio
 	}
 
 	// Look for the asset polcies
-	policies, err := dropper.impl.FetchPolicies(&dropper.Options, asset)
+	policies, _, err := dropper.impl.FetchPolicies(&opts.Options, dropper.client, asset)
 	if err != nil {
 		return fmt.Errorf("finding asset polcies: %w", err)
 	}
 
-	if len(policies) == 0 && !opts.SkipVerification {
+	// Without policies, the asset is verified with the checksums and
+	// signatures in its release unless only policies are accepted.
+	if len(policies) == 0 && !opts.SkipVerification && opts.MinLevel == integrity.LevelPolicy {
 		return ErrNoPolicyAvailable
 	}
 
@@ -88,22 +91,9 @@ func (dropper *Dropper) Get(spec github.AssetDataProvider, funcs ...FuncGetOptio
 	}
 
 	// Verify the asset data
-	if opts.SkipVerification {
-		opts.Listener.HandleEvent(
-			&Event{Object: EventObjectVerification, Verb: EventVerbSkipped},
-		)
-	} else {
-		ok, _, err := dropper.impl.VerifyAsset(&dropper.Options, policies, asset, downloadPath)
-		if err != nil {
-			_ = os.Remove(downloadPath) //nolint:errcheck
-			return fmt.Errorf("error verifying asset: %w", err)
-		}
-
-		// If verification failed, we're done
-		if !ok {
-			_ = os.Remove(downloadPath) //nolint:errcheck
-			return ErrVerificationFailed
-		}
+	if _, err := dropper.verifyDownload(&opts, asset, policies, downloadPath); err != nil {
+		_ = os.Remove(downloadPath) //nolint:errcheck
+		return err
 	}
 
 	opts.Listener.HandleEvent(
@@ -142,13 +132,19 @@ func (dropper *Dropper) Install(spec github.AssetDataProvider, funcs ...FuncGetO
 		return fmt.Errorf("unable to locate a suitable asset: %w", err)
 	}
 
+	// Installs that cannot be recorded are refused up front, an app missing
+	// from its inventory cannot be updated or uninstalled.
+	if err := dropper.impl.CheckInventory(&opts, artifact); err != nil {
+		return err
+	}
+
 	// Look for the asset polcies
-	policies, err := dropper.impl.FetchPolicies(&dropper.Options, artifact.Asset)
+	policies, source, err := dropper.impl.FetchPolicies(&opts.Options, dropper.client, artifact.Asset)
 	if err != nil {
 		return fmt.Errorf("finding asset polcies: %w", err)
 	}
 
-	if len(policies) == 0 && !opts.SkipVerification {
+	if len(policies) == 0 && !opts.SkipVerification && opts.MinLevel == integrity.LevelPolicy {
 		return ErrNoPolicyAvailable
 	}
 
@@ -162,34 +158,62 @@ func (dropper *Dropper) Install(spec github.AssetDataProvider, funcs ...FuncGetO
 	defer os.RemoveAll(filepath.Dir(downloadPath)) //nolint:errcheck
 
 	// Verify the asset data
-	if opts.SkipVerification {
-		opts.Listener.HandleEvent(
-			&Event{Object: EventObjectVerification, Verb: EventVerbSkipped},
-		)
-	} else {
-		ok, _, err := dropper.impl.VerifyAsset(&dropper.Options, policies, artifact.Asset, downloadPath)
-		if err != nil {
-			return fmt.Errorf("error verifying asset: %w", err)
-		}
+	verification, err := dropper.verifyDownload(&opts, artifact.Asset, policies, downloadPath)
+	if err != nil {
+		return err
+	}
+	if verification.Level == integrity.LevelPolicy {
+		verification.PolicySource = source
+	}
 
-		// If verification failed, we're done
-		if !ok {
-			return ErrVerificationFailed
-		}
+	// Check the signers against the ones pinned when the app was installed
+	if err := checkSigners(&opts, verification.Signers); err != nil {
+		return err
 	}
 
-	// TODO(puerco): Probably here we should output a summary of the verification
+	// Scan the app, and the components of its SBOM, for vulnerabilities
+	docs := dropper.fetchReleaseDocuments(&opts, artifact.Asset)
+	if err := checkVulnerabilities(&opts, artifact.Asset, docs); err != nil {
+		return err
+	}
 
 	// Install the asset in the system
 	if err := dropper.impl.InstallAsset(&opts, sysinfo, artifact, downloadPath); err != nil {
 		return fmt.Errorf("installing asset: %w", err)
 	}
 
+	// Set up the completions and man pages when requested. As with the
+	// inventory, the app is already installed so failures only warn.
+	if opts.Completions && artifact.Kind == ArtifactBinary {
+		if err := dropper.installExtras(&opts, artifact); err != nil {
+			logrus.Warnf("app installed, but installing its completions failed: %v", err)
+		}
+	}
+
+	// Keep the policies and attestations to verify the app again offline
+	if verification.Level == integrity.LevelPolicy {
+		if err := dropper.storeEvidence(&opts, artifact.Asset, policies, downloadPath); err != nil {
+			logrus.Warnf("app installed, but storing its verification evidence failed: %v", err)
+		}
+	}
+
+	// Keep the SBOM and VEX documents published with the artifact, if any
+	if err := dropper.storeReleaseDocuments(&opts, artifact, downloadPath, docs); err != nil {
+		logrus.Warnf("app installed, but storing its SBOM and VEX documents failed: %v", err)
+	}
+
 	// Register the installation in the inventory. The app is already
 	// installed at this point, so a recording failure is not fatal.
-	if err := dropper.impl.RecordInstall(&opts, artifact, downloadPath, !opts.SkipVerification); err != nil {
+	if err := dropper.impl.RecordInstall(&opts, artifact, downloadPath, verification); err != nil {
 		logrus.Warnf("app installed, but recording it in the inventory failed: %v", err)
 	}
 
 	return nil
 }
+
+// Authenticate prompts for the credentials of the privilege escalation
+// helper up front, so installing several apps does not interleave password
+// prompts with the progress output.
+func (dropper *Dropper) Authenticate() error {
+	return dropper.impl.Authenticate(&dropper.Options)
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Privilege escalation helpers drop can shell out to
+const (
+	EscalationSudo   = "sudo"
+	EscalationDoas   = "doas"
+	EscalationRun0   = "run0"
+	EscalationPkexec = "pkexec"
+)
+
+// EscalationHelpers lists the supported privilege escalation helpers in the
+// order they are looked for when none is configured. pkexec goes last as it
+// usually prompts through a graphical agent.
+var EscalationHelpers = []string{EscalationSudo, EscalationDoas, EscalationRun0, EscalationPkexec}
+
+var ErrNoEscalationHelper = errors.New("no privilege escalation helper found")
+
+// resolveEscalationHelper returns the helper to run privileged commands: the
+// configured one if set (it must be available) or the first one found in
+// the system.
+func resolveEscalationHelper(configured string, lookPath func(string) (string, error)) (string, error) {
+	if configured != "" {
+		if _, err := lookPath(configured); err != nil {
+			return "", fmt.Errorf("%w: %s not found in PATH", ErrNoEscalationHelper, configured)
+		}
+		return configured, nil
+	}
+	for _, helper := range EscalationHelpers {
+		if _, err := lookPath(helper); err == nil {
+			return helper, nil
+		}
+	}
+	return "", fmt.Errorf("%w (looked for %v)", ErrNoEscalationHelper, EscalationHelpers)
+}
+
+// escalatedArgv prefixes a command with the escalation helper
+func escalatedArgv(helper string, argv []string) []string {
+	return append([]string{helper}, argv...)
+}
+
+// escalationAuthArgv returns the command that authenticates the user with
+// the helper, caching the credentials for the commands that follow. Helpers
+// that do not cache credentials (run0, pkexec) return nil, they prompt on
+// every command.
+func escalationAuthArgv(helper string) []string {
+	switch helper {
+	case EscalationSudo:
+		return []string{EscalationSudo, "-v"}
+	case EscalationDoas:
+		// Only remembered when doas.conf sets the persist option
+		return []string{EscalationDoas, "true"}
+	default:
+		return nil
+	}
+}
+
+// escalationHelper returns the helper used to run privileged commands
+func (di *defaultImplementation) escalationHelper(opts *Options) (string, error) {
+	return resolveEscalationHelper(opts.Escalation, di.runner.LookPath)
+}
+
+// Authenticate asks the user for their credentials with the escalation
+// helper before running privileged commands. It does nothing when running
+// as root.
+func (di *defaultImplementation) Authenticate(opts *Options) error {
+	if os.Geteuid() == 0 {
+		return nil
+	}
+	helper, err := di.escalationHelper(opts)
+	if err != nil {
+		return err
+	}
+	argv := escalationAuthArgv(helper)
+	if argv == nil {
+		return nil
+	}
+	if err := di.runner.Run(argv); err != nil {
+		return fmt.Errorf("authenticating with %s: %w", helper, err)
+	}
+	return nil
+}
+
+// validEscalationHelper returns true if the helper is supported
+func validEscalationHelper(helper string) bool {
+	return slices.Contains(EscalationHelpers, helper)
+}
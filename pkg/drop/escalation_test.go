@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEscalationHelper(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name       string
+		configured string
+		paths      map[string]bool
+		expect     string
+		expectErr  bool
+	}{
+		{
+			name:   "sudo-first",
+			paths:  map[string]bool{EscalationSudo: true, EscalationDoas: true, EscalationRun0: true},
+			expect: EscalationSudo,
+		},
+		{
+			name:   "doas-without-sudo",
+			paths:  map[string]bool{EscalationDoas: true, EscalationPkexec: true},
+			expect: EscalationDoas,
+		},
+		{
+			name:   "run0-before-pkexec",
+			paths:  map[string]bool{EscalationPkexec: true, EscalationRun0: true},
+			expect: EscalationRun0,
+		},
+		{
+			name:       "configured",
+			configured: EscalationRun0,
+			paths:      map[string]bool{EscalationSudo: true, EscalationRun0: true},
+			expect:     EscalationRun0,
+		},
+		{
+			name:       "configured-missing",
+			configured: EscalationDoas,
+			paths:      map[string]bool{EscalationSudo: true},
+			expectErr:  true,
+		},
+		{
+			name:      "none-available",
+			paths:     map[string]bool{},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runner := &fakeRunner{paths: tc.paths}
+			helper, err := resolveEscalationHelper(tc.configured, runner.LookPath)
+			if tc.expectErr {
+				require.ErrorIs(t, err, ErrNoEscalationHelper)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, helper)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("running as root, no authentication needed")
+	}
+	for _, tc := range []struct {
+		name   string
+		helper string
+		expect [][]string
+	}{
+		{name: "sudo", helper: EscalationSudo, expect: [][]string{{EscalationSudo, "-v"}}},
+		{name: "doas", helper: EscalationDoas, expect: [][]string{{EscalationDoas, "true"}}},
+		{name: "run0-no-cache", helper: EscalationRun0, expect: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runner := &fakeRunner{paths: map[string]bool{tc.helper: true}}
+			di := &defaultImplementation{runner: runner}
+			require.NoError(t, di.Authenticate(&Options{Escalation: tc.helper}))
+			require.Equal(t, tc.expect, runner.run)
+		})
+	}
+}
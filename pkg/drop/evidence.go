@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/drop/pkg/evidence"
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/sbom"
+	"github.com/carabiner-dev/drop/pkg/vuln"
+)
+
+// maxAttestationSize caps the size of the attestation files kept as evidence
+const maxAttestationSize = 32 << 20
+
+var ErrInstalledFileModified = errors.New("installed file does not match the verified artifact")
+
+// attestationSuffixes are the extensions of the release files holding
+// attestations, copied to the evidence store.
+var attestationSuffixes = []string{
+	".intoto.jsonl", ".intoto.json", ".sigstore.json", ".sigstore", ".bundle", ".jsonl",
+}
+
+// isAttestationFile returns true for release files holding attestations
+func isAttestationFile(name string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range attestationSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectEvidence gathers the policies and the attestations published in the
+// release of an artifact.
+func (dropper *Dropper) collectEvidence(
+	opts *GetOptions, asset github.AssetDataProvider, policies []*papi.PolicySet,
+) (*evidence.Bundle, error) {
+	bundle := &evidence.Bundle{Attestations: map[string][]byte{}}
+	for _, pset := range policies {
+		data, err := protojson.Marshal(pset)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling policy set: %w", err)
+		}
+		bundle.Policies = append(bundle.Policies, data)
+	}
+
+	assets, err := dropper.client.ListReleaseAssets(asset)
+	if err != nil {
+		return nil, fmt.Errorf("listing release assets: %w", err)
+	}
+	for _, a := range assets {
+		if !isAttestationFile(a.GetName()) {
+			continue
+		}
+		if a.GetSize() > maxAttestationSize {
+			logrus.Debugf("skipping %s, too large to keep as evidence", a.GetName())
+			continue
+		}
+		var buf bytes.Buffer
+		if err := dropper.impl.DownloadAssetToWriter(opts, &buf, a); err != nil {
+			return nil, fmt.Errorf("downloading %s: %w", a.GetName(), err)
+		}
+		bundle.Attestations[a.GetName()] = buf.Bytes()
+	}
+	return bundle, nil
+}
+
+// storeEvidence saves the policies and attestations used to verify an
+// artifact, keyed by its digest.
+func (dropper *Dropper) storeEvidence(
+	opts *GetOptions, asset github.AssetDataProvider, policies []*papi.PolicySet, path string,
+) error {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	bundle, err := dropper.collectEvidence(opts, asset, policies)
+	if err != nil {
+		return err
+	}
+	store, err := evidence.Open()
+	if err != nil {
+		return err
+	}
+	return store.Save(digest, bundle)
+}
+
+// recordAsset returns the release asset an app was installed from
+func recordAsset(record *inventory.Record) *github.Asset {
+	return &github.Asset{
+		Host: record.Host, Org: record.Org, Repo: record.Repo,
+		Version: record.Version, Name: record.Asset,
+	}
+}
+
+// recordDigest returns the sha256 digest of the artifact of an install
+func recordDigest(record *inventory.Record) (string, error) {
+	if d := record.Digest["sha256"]; d != "" {
+		return d, nil
+	}
+	return "", errors.New("the install has no recorded digest")
+}
+
+// checkInstalledFile compares the installed binary or AppImage with the
+// verified artifact. Packages are left to the package manager.
+func checkInstalledFile(record *inventory.Record) error {
+	if record.BinPath == "" || (record.Kind != string(ArtifactBinary) && record.Kind != string(ArtifactAppImage)) {
+		return nil
+	}
+	digest, err := fileDigest(record.BinPath)
+	if err != nil {
+		return fmt.Errorf("checking installed file: %w", err)
+	}
+	if digest != record.Digest["sha256"] {
+		return fmt.Errorf("%w: %s", ErrInstalledFileModified, record.BinPath)
+	}
+	return nil
+}
+
+// VerifyInstalled verifies an installed app again against its policies.
+// Online, the policies and attestations are fetched fresh. Offline, those
+// stored when the app was installed (or last refreshed) are used.
+func (dropper *Dropper) VerifyInstalled(record *inventory.Record, offline bool) (bool, *papi.ResultSet, error) {
+	digest, err := recordDigest(record)
+	if err != nil {
+		return false, nil, err
+	}
+	if err := checkInstalledFile(record); err != nil {
+		return false, nil, err
+	}
+
+	opts := dropper.Options
+	if opts.Listener == nil {
+		opts.Listener = &NoopListener{}
+	}
+	asset := recordAsset(record)
+	subject := &intoto.ResourceDescriptor{Name: record.Asset, Digest: record.Digest}
+
+	if !offline {
+		policies, _, err := dropper.impl.FetchPolicies(&opts, dropper.client, asset)
+		if err != nil {
+			return false, nil, fmt.Errorf("fetching policies: %w", err)
+		}
+		if len(policies) == 0 {
+			return false, nil, ErrNoPolicyAvailable
+		}
+		return dropper.impl.VerifySubject(&opts, policies, asset, subject, nil)
+	}
+
+	store, err := evidence.Open()
+	if err != nil {
+		return false, nil, err
+	}
+	entry, err := store.Lookup(digest)
+	if err != nil {
+		if errors.Is(err, evidence.ErrNotFound) {
+			return false, nil, fmt.Errorf("%w (run \"drop refresh\" while online)", err)
+		}
+		return false, nil, err
+	}
+	policies, err := loadPolicyFiles(entry.PolicyFiles)
+	if err != nil {
+		return false, nil, fmt.Errorf("loading stored policies: %w", err)
+	}
+
+	// A non-nil list keeps the verifier from collecting from the release
+	files := entry.AttestationFiles
+	if files == nil {
+		files = []string{}
+	}
+	return dropper.impl.VerifySubject(&opts, policies, asset, subject, files)
+}
+
+// RefreshEvidence replaces the stored policies and attestations of an
+// installed app with fresh copies.
+func (dropper *Dropper) RefreshEvidence(record *inventory.Record) error {
+	digest, err := recordDigest(record)
+	if err != nil {
+		return err
+	}
+
+	opts := defaultGetOptions
+	opts.Options = dropper.Options
+	opts.Listener = &NoopListener{}
+
+	asset := recordAsset(record)
+	policies, _, err := dropper.impl.FetchPolicies(&opts.Options, dropper.client, asset)
+	if err != nil {
+		return fmt.Errorf("fetching policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return ErrNoPolicyAvailable
+	}
+	bundle, err := dropper.collectEvidence(&opts, asset, policies)
+	if err != nil {
+		return err
+	}
+	store, err := evidence.Open()
+	if err != nil {
+		return err
+	}
+	return store.Save(digest, bundle)
+}
+
+// PruneEvidence removes the stored evidence, SBOMs and VEX documents of
+// artifacts no longer in the inventory, returning the number of entries
+// removed.
+func (dropper *Dropper) PruneEvidence() (int, error) {
+	records, err := inventory.Installed("")
+	if err != nil {
+		return 0, fmt.Errorf("opening install inventory: %w", err)
+	}
+	keep := []string{}
+	for _, record := range records {
+		if d := record.Digest["sha256"]; d != "" {
+			keep = append(keep, d)
+		}
+	}
+	store, err := evidence.Open()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := store.Prune(keep)
+	if err != nil {
+		return removed, err
+	}
+	sboms, err := sbom.Open()
+	if err != nil {
+		return removed, err
+	}
+	n, err := sboms.Prune(keep)
+	removed += n
+	if err != nil {
+		return removed, err
+	}
+	vex, err := vuln.OpenVEXStore()
+	if err != nil {
+		return removed, err
+	}
+	n, err = vex.Prune(keep)
+	return removed + n, err
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+func TestIsAttestationFile(t *testing.T) {
+	t.Parallel()
+	for name, expect := range map[string]bool{
+		"drop-linux-amd64.intoto.jsonl": true,
+		"multiple.intoto.JSONL":         true,
+		"drop.sigstore.json":            true,
+		"drop-linux-amd64.bundle":       true,
+		"attestations.jsonl":            true,
+		"drop-linux-amd64":              false,
+		"drop.spdx.json":                false,
+		"drop-linux-amd64.sig":          false,
+	} {
+		require.Equal(t, expect, isAttestationFile(name), name)
+	}
+}
+
+func TestCheckInstalledFile(t *testing.T) {
+	t.Parallel()
+	bin := filepath.Join(t.TempDir(), testAppName)
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/true"), 0o600))
+	digest, err := fileDigest(bin)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		record *inventory.Record
+		expect error
+	}{
+		{
+			name:   "binary-matches",
+			record: &inventory.Record{Kind: string(ArtifactBinary), BinPath: bin, Digest: map[string]string{"sha256": digest}},
+		},
+		{
+			name:   "binary-modified",
+			record: &inventory.Record{Kind: string(ArtifactBinary), BinPath: bin, Digest: map[string]string{"sha256": "0000"}},
+			expect: ErrInstalledFileModified,
+		},
+		{
+			name:   "package-not-checked",
+			record: &inventory.Record{Kind: string(ArtifactPackage), Digest: map[string]string{"sha256": "0000"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkInstalledFile(tc.record)
+			if tc.expect != nil {
+				require.ErrorIs(t, err, tc.expect)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
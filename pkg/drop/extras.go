@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/pkgmeta"
+)
+
+// Shells drop installs completions for
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+	shellFish = "fish"
+)
+
+// extraShells lists the shells in the order completions are generated
+var extraShells = []string{shellBash, shellZsh, shellFish}
+
+// maxExtraSize caps the size of the completion scripts and man pages read
+// from archives or generated by the installed binary.
+const maxExtraSize = 8 << 20
+
+// manPageRegex matches man page filenames (drop.1, drop-get.1.gz, drop.3p)
+// and manDirRegex the directories holding them (man, manpages, man1).
+var (
+	manPageRegex = regexp.MustCompile(`\.([1-9])[a-z]*(\.gz)?$`)
+	manDirRegex  = regexp.MustCompile(`^man(pages|[1-9])?$`)
+)
+
+// extraFile is a completion script or man page found in a release archive
+type extraFile struct {
+	// Kind is the shell name for completions or "man"
+	Kind string
+
+	// Section is the man page section
+	Section string
+
+	// Name is the base name of the file in the archive
+	Name string
+}
+
+// classifyExtraFile checks if a file from a release archive is a shell
+// completion script (in a completion(s) directory) or a man page (in a man,
+// manpages or manN directory).
+func classifyExtraFile(name string) *extraFile {
+	dirs := strings.Split(path.Dir(name), "/")
+	base := path.Base(name)
+	inDir := func(names ...string) bool {
+		return slices.ContainsFunc(dirs, func(d string) bool {
+			return slices.Contains(names, strings.ToLower(d))
+		})
+	}
+	inManDir := slices.ContainsFunc(dirs, func(d string) bool {
+		return manDirRegex.MatchString(strings.ToLower(d))
+	})
+
+	switch {
+	case inDir("completion", "completions"):
+		lower := strings.ToLower(base)
+		switch {
+		case strings.HasSuffix(lower, ".bash") || inDir(shellBash):
+			return &extraFile{Kind: shellBash, Name: base}
+		case strings.HasSuffix(lower, ".zsh") || strings.HasPrefix(base, "_") || inDir(shellZsh):
+			return &extraFile{Kind: shellZsh, Name: base}
+		case strings.HasSuffix(lower, ".fish") || inDir(shellFish):
+			return &extraFile{Kind: shellFish, Name: base}
+		}
+	case inManDir:
+		if m := manPageRegex.FindStringSubmatch(base); m != nil {
+			return &extraFile{Kind: "man", Section: m[1], Name: base}
+		}
+	}
+	return nil
+}
+
+// extrasShareDir returns the data directory where completions and man pages
+// are installed: the prefix share directory, /usr/local/share when running
+// as root or the user's XDG data directory.
+func extrasShareDir(opts *GetOptions) (string, error) {
+	switch {
+	case opts.Prefix != "":
+		return filepath.Join(opts.Prefix, "share"), nil
+	case os.Geteuid() == 0:
+		return "/usr/local/share", nil
+	default:
+		return xdgDataHome()
+	}
+}
+
+// extraDestination returns where a completion or man page of the app is
+// installed, following the lookup paths of each shell and of man.
+func extraDestination(share, app string, ef *extraFile) string {
+	switch ef.Kind {
+	case shellBash:
+		return filepath.Join(share, "bash-completion", "completions", app)
+	case shellZsh:
+		return filepath.Join(share, "zsh", "site-functions", "_"+app)
+	case shellFish:
+		return filepath.Join(share, "fish", "vendor_completions.d", app+".fish")
+	default:
+		return filepath.Join(share, "man", "man"+ef.Section, ef.Name)
+	}
+}
+
+// InstallExtras installs the shell completions and man pages of an installed
+// binary. They are copied from the release archive (when one was downloaded)
+// and completions missing from it are generated by running the binary's
+// completion subcommand when it has one. The written files are added to the
+// artifact to record them in the inventory. Existing files are only replaced
+// when they were recorded for the app, so the man pages and completions of
+// other packages are kept.
+func (di *defaultImplementation) InstallExtras(opts *GetOptions, artifact *InstallArtifact, archivePath string) error {
+	share, err := extrasShareDir(opts)
+	if err != nil {
+		return err
+	}
+	app := recordName(artifact)
+	written := []string{}
+	covered := map[string]bool{}
+
+	var owned []string
+	if artifact.Asset != nil {
+		previous := di.findRecord(artifact.Asset, app, func(*inventory.Record) bool { return true })
+		if previous != nil {
+			owned = previous.Files
+		}
+	}
+	canWrite := func(dest string) bool {
+		if _, err := os.Lstat(dest); errors.Is(err, fs.ErrNotExist) || slices.Contains(owned, dest) {
+			return true
+		}
+		logrus.Warnf("not installing %s, the file exists and was not installed with %s", dest, app)
+		return false
+	}
+
+	if archivePath != "" {
+		err := pkgmeta.WalkArchiveFile(archivePath, func(f *pkgmeta.PayloadFile, r io.Reader) error {
+			if f.Type != pkgmeta.FileRegular {
+				return nil
+			}
+			ef := classifyExtraFile(f.Name)
+			if ef == nil || (ef.Kind != "man" && covered[ef.Kind]) {
+				return nil
+			}
+			dest := extraDestination(share, app, ef)
+			covered[ef.Kind] = true
+			if !canWrite(dest) {
+				return nil
+			}
+			if err := writeExtraFile(dest, io.LimitReader(r, maxExtraSize)); err != nil {
+				return err
+			}
+			written = append(written, dest)
+			owned = append(owned, dest)
+			return nil
+		})
+		if err != nil {
+			logrus.Warnf("reading completions from %s: %v", filepath.Base(archivePath), err)
+		}
+	}
+
+	for _, shell := range di.generateCompletions(opts.BinDir, artifact.InstallName, covered) {
+		dest := extraDestination(share, app, &extraFile{Kind: shell.name})
+		if !canWrite(dest) {
+			continue
+		}
+		if err := writeExtraFile(dest, bytes.NewReader(shell.script)); err != nil {
+			return err
+		}
+		written = append(written, dest)
+	}
+
+	artifact.Files = append(artifact.Files, written...)
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectExtras, Verb: EventVerbDone,
+		Data: map[string]string{
+			dataKeyName: app,
+			"count":     strconv.Itoa(len(written)),
+			"path":      share,
+		},
+	})
+	return nil
+}
+
+type generatedCompletion struct {
+	name   string
+	script []byte
+}
+
+// generateCompletions runs `<bin> completion <shell>` for the shells not
+// covered by the archive. The binary must mention the completion command in
+// its help, to avoid running unrelated commands with those arguments.
+func (di *defaultImplementation) generateCompletions(binDir, binName string, covered map[string]bool) []generatedCompletion {
+	bin := filepath.Join(binDir, binName)
+	help, err := di.runner.Output([]string{bin, "--help"})
+	if err != nil || !bytes.Contains(help, []byte("completion")) {
+		return nil
+	}
+
+	ret := []generatedCompletion{}
+	for _, shell := range extraShells {
+		if covered[shell] {
+			continue
+		}
+		script, err := di.runner.Output([]string{bin, "completion", shell})
+		if err != nil || len(bytes.TrimSpace(script)) == 0 || len(script) > maxExtraSize {
+			logrus.Debugf("%s does not generate %s completions", binName, shell)
+			continue
+		}
+		ret = append(ret, generatedCompletion{name: shell, script: script})
+	}
+	return ret
+}
+
+// writeExtraFile writes a completion script or man page, replacing any
+// existing file.
+func writeExtraFile(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(dest), err)
+	}
+	if err := os.Remove(dest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("replacing %s: %w", dest, err)
+	}
+	return writePayloadFile(dest, 0o644, r)
+}
+
+// installExtras fetches and verifies the release archive holding the
+// completions and man pages (if the release has one) and hands it to the
+// implementation to install them. The archive is verified like any other
+// artifact; if it fails, completions are only generated from the binary.
+func (dropper *Dropper) installExtras(opts *GetOptions, artifact *InstallArtifact) error {
+	archivePath := ""
+	if artifact.Extras != nil {
+		path, err := dropper.fetchVerified(opts, artifact.Extras)
+		if err != nil {
+			logrus.Warnf("not using %s for completions: %v", artifact.Extras.GetName(), err)
+		} else {
+			defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+			archivePath = path
+		}
+	}
+	return dropper.impl.InstallExtras(opts, artifact, archivePath)
+}
+
+// fetchVerified downloads a release asset to a temporary directory and
+// verifies it like the installed artifact: against its policies or, when
+// there are none, its checksums and signatures at the required level.
+// Nothing is verified when verification is disabled.
+func (dropper *Dropper) fetchVerified(opts *GetOptions, asset *github.Asset) (string, error) {
+	policies, _, err := dropper.impl.FetchPolicies(&opts.Options, dropper.client, asset)
+	if err != nil {
+		return "", fmt.Errorf("finding asset policies: %w", err)
+	}
+
+	path, err := dropper.impl.DownloadAssetToTmp(opts, asset)
+	if err != nil {
+		return "", fmt.Errorf("downloading asset: %w", err)
+	}
+	if opts.SkipVerification {
+		return path, nil
+	}
+
+	if _, err := dropper.verifyDownload(opts, asset, policies, path); err != nil {
+		os.RemoveAll(filepath.Dir(path)) //nolint:errcheck,gosec
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+func TestClassifyExtraFile(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		expect *extraFile
+	}{
+		{"drop-1.0.0/completions/drop.bash", &extraFile{Kind: shellBash, Name: "drop.bash"}},
+		{"completions/_drop", &extraFile{Kind: shellZsh, Name: "_drop"}},
+		{"completion/zsh/drop", &extraFile{Kind: shellZsh, Name: "drop"}},
+		{"completions/drop.fish", &extraFile{Kind: shellFish, Name: "drop.fish"}},
+		{"manpages/drop.1.gz", &extraFile{Kind: "man", Section: "1", Name: "drop.1.gz"}},
+		{"docs/man/man5/drop.conf.5", &extraFile{Kind: "man", Section: "5", Name: "drop.conf.5"}},
+		{"man/README.md", nil},
+		{"completions/README.md", nil},
+		{"drop", nil},
+		{"docs/drop.1", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, classifyExtraFile(tc.name))
+		})
+	}
+}
+
+func TestInstallExtras(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, f := range [][2]string{
+		{"drop/drop", "binary"},
+		{"drop/completions/drop.bash", "bash completion"},
+		{"drop/man/drop.1", "man page"},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0o644, Size: int64(len(f[1])), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+	archive := filepath.Join(t.TempDir(), "drop-linux-amd64.tar.gz")
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o600))
+
+	for _, tc := range []struct {
+		name        string
+		archive     string
+		help        string
+		expectFiles []string
+	}{
+		{
+			name: "archive-and-generated", archive: archive, help: "use drop completion to generate scripts",
+			expectFiles: []string{
+				"share/bash-completion/completions/drop", "share/man/man1/drop.1",
+				"share/zsh/site-functions/_drop", "share/fish/vendor_completions.d/drop.fish",
+			},
+		},
+		{
+			name: "archive-only", archive: archive, help: "usage: drop [flags]",
+			expectFiles: []string{"share/bash-completion/completions/drop", "share/man/man1/drop.1"},
+		},
+		{
+			name: "generated-only", help: "completion",
+			expectFiles: []string{
+				"share/bash-completion/completions/drop", "share/zsh/site-functions/_drop",
+				"share/fish/vendor_completions.d/drop.fish",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			prefix := t.TempDir()
+			di := &defaultImplementation{runner: &fakeRunner{output: []byte(tc.help)}}
+			opts := &GetOptions{Prefix: prefix, BinDir: filepath.Join(prefix, "bin")}
+			opts.Listener = &NoopListener{}
+			artifact := &InstallArtifact{Kind: ArtifactBinary, InstallName: testAppName}
+
+			require.NoError(t, di.InstallExtras(opts, artifact, tc.archive))
+			expect := make([]string, 0, len(tc.expectFiles))
+			for _, f := range tc.expectFiles {
+				expect = append(expect, filepath.Join(prefix, filepath.FromSlash(f)))
+			}
+			require.Equal(t, expect, artifact.Files)
+			for _, f := range expect {
+				require.FileExists(t, f)
+			}
+		})
+	}
+
+	t.Run("existing-files", func(t *testing.T) {
+		t.Parallel()
+		// The man page of another package is kept, the completion recorded
+		// for the app is replaced.
+		prefix := t.TempDir()
+		manPage := filepath.Join(prefix, "share", "man", "man1", "drop.1")
+		bashCompletion := filepath.Join(prefix, "share", "bash-completion", "completions", testAppName)
+		for _, f := range []string{manPage, bashCompletion} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(f), 0o755))
+			require.NoError(t, os.WriteFile(f, []byte("existing"), 0o600))
+		}
+
+		asset := &github.Asset{Host: "github.com", Org: "carabiner-dev", Repo: testAppName}
+		invPath := filepath.Join(t.TempDir(), inventory.FileName)
+		inv, err := inventory.OpenFile(invPath)
+		require.NoError(t, err)
+		inv.Add(&inventory.Record{
+			Host: asset.Host, Org: asset.Org, Repo: asset.Repo, Name: testAppName,
+			Kind: string(ArtifactBinary), Files: []string{bashCompletion},
+		})
+		require.NoError(t, inv.Save())
+
+		di := &defaultImplementation{runner: &fakeRunner{}, inventoryPath: invPath}
+		opts := &GetOptions{Prefix: prefix, BinDir: filepath.Join(prefix, "bin")}
+		opts.Listener = &NoopListener{}
+		artifact := &InstallArtifact{Kind: ArtifactBinary, Asset: asset, InstallName: testAppName}
+
+		require.NoError(t, di.InstallExtras(opts, artifact, archive))
+		require.Equal(t, []string{bashCompletion}, artifact.Files)
+		for f, content := range map[string]string{manPage: "existing", bashCompletion: "bash completion"} {
+			data, err := os.ReadFile(f) //nolint:gosec // test-controlled path
+			require.NoError(t, err)
+			require.Equal(t, content, string(data))
+		}
+
+		// Uninstalling removes the app's completion, not the foreign man page
+		require.NoError(t, di.UninstallApp(&Options{}, &inventory.Record{
+			Host: asset.Host, Org: asset.Org, Repo: asset.Repo, Name: testAppName,
+			Kind: string(ArtifactBinary), Files: artifact.Files,
+		}))
+		require.NoFileExists(t, bashCompletion)
+		require.FileExists(t, manPage)
+	})
+}
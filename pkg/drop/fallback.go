@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// archTarget is an arch the installer looks for when choosing variants
+type archTarget struct {
+	Arch  string
+	Level string
+
+	// Fallback is set when the target is not the requested arch but an
+	// alternative from the platform's fallback chain.
+	Fallback *system.ArchFallback
+}
+
+// archTargets returns the arches to look for, in order: the requested one
+// followed by its fallback chain. Emulated fallbacks are only included when
+// the user opted into them.
+func archTargets(opts *GetOptions) []archTarget {
+	ret := []archTarget{{Arch: opts.Arch, Level: opts.ArchLevel}}
+	if opts.DisableFallbacks {
+		return ret
+	}
+
+	chain := system.GetArchFallbacks(opts.OS, opts.Arch)
+	if opts.FallbackArches != nil {
+		// A custom chain keeps the emulation flags of the known fallbacks
+		custom := make([]system.ArchFallback, 0, len(opts.FallbackArches))
+		for _, arch := range opts.FallbackArches {
+			fb := system.ArchFallback{Arch: arch}
+			if i := slices.IndexFunc(chain, func(f system.ArchFallback) bool { return f.Arch == arch }); i != -1 {
+				fb = chain[i]
+			}
+			custom = append(custom, fb)
+		}
+		chain = custom
+	}
+
+	for i := range chain {
+		if chain[i].Arch == opts.Arch {
+			continue
+		}
+		if chain[i].Emulated && !opts.AllowEmulation {
+			continue
+		}
+		// Fallback arches don't carry the local CPU level, the most
+		// compatible variant is chosen.
+		ret = append(ret, archTarget{Arch: chain[i].Arch, Fallback: &chain[i]})
+	}
+	return ret
+}
+
+// notifyFallback tells the listener that a fallback arch was chosen
+func notifyFallback(opts *GetOptions, target archTarget) {
+	if target.Fallback == nil {
+		return
+	}
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectAsset, Verb: EventVerbFallback,
+		Data: map[string]string{
+			"os":       opts.OS,
+			"wanted":   opts.Arch,
+			"arch":     target.Fallback.Arch,
+			"emulated": strconv.FormatBool(target.Fallback.Emulated),
+		},
+	})
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+func TestArchTargets(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		opts   *GetOptions
+		expect []string
+	}{
+		{
+			name:   "darwin-arm64-native-only",
+			opts:   &GetOptions{OS: system.OSDarwin, Arch: system.ArchArm64},
+			expect: []string{system.ArchArm64, system.ArchUniversal},
+		},
+		{
+			name:   "darwin-arm64-emulated",
+			opts:   &GetOptions{OS: system.OSDarwin, Arch: system.ArchArm64, AllowEmulation: true},
+			expect: []string{system.ArchArm64, system.ArchUniversal, system.ArchX8664},
+		},
+		{
+			name:   "linux-x86_64",
+			opts:   &GetOptions{OS: system.OSLinux, Arch: system.ArchX8664},
+			expect: []string{system.ArchX8664, system.Arch386},
+		},
+		{
+			name:   "disabled",
+			opts:   &GetOptions{OS: system.OSLinux, Arch: system.ArchX8664, DisableFallbacks: true},
+			expect: []string{system.ArchX8664},
+		},
+		{
+			name:   "custom-chain-keeps-emulation-gate",
+			opts:   &GetOptions{OS: system.OSLinux, Arch: system.ArchArm64, FallbackArches: []string{system.ArchX8664, system.ArchArm}},
+			expect: []string{system.ArchArm64, system.ArchArm},
+		},
+		{
+			name:   "no-chain",
+			opts:   &GetOptions{OS: system.OSLinux, Arch: system.ArchRiscV64},
+			expect: []string{system.ArchRiscV64},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := []string{}
+			for i, target := range archTargets(tc.opts) {
+				require.Equal(t, i > 0, target.Fallback != nil)
+				got = append(got, target.Arch)
+			}
+			require.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestClassifyUniversalFallback(t *testing.T) {
+	t.Parallel()
+	inst := &github.Installable{
+		Name: testAppName,
+		Variants: []*github.Asset{
+			{Name: "drop-darwin-universal", Os: system.OSDarwin, Arch: system.ArchUniversal},
+			{Name: testBinFile, Os: system.OSLinux, Arch: system.ArchX8664},
+		},
+	}
+	opts := &GetOptions{OS: system.OSDarwin, Arch: system.ArchArm64}
+	targets := archTargets(opts)
+	require.Len(t, targets, 2)
+
+	require.Nil(t, classifyInstallCandidates(inst, opts.OS, targets[0].Arch, "", "").Binary)
+	cands := classifyInstallCandidates(inst, opts.OS, targets[1].Arch, "", "")
+	require.NotNil(t, cands.Binary)
+	require.Equal(t, "drop-darwin-universal", cands.Binary.Asset.GetName())
+}
@@ -26,6 +26,8 @@ import (
 	"sigs.k8s.io/release-utils/http"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
+	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
 
@@ -42,8 +44,9 @@ type installerImplementation interface {
 	// package) will be installed on the local system.
 	SelectInstallArtifact(*GetOptions, *github.Client, *system.Info, github.AssetDataProvider) (*InstallArtifact, error)
 
-	// Fetch policies uses a provider to look for policies in a structured data source.
-	FetchPolicies(*Options, github.AssetDataProvider) ([]*papi.PolicySet, error)
+	// Fetch policies uses a provider to look for policies in a structured data
+	// source. It also returns the policy repository revision they came from.
+	FetchPolicies(*Options, *github.Client, github.AssetDataProvider) ([]*papi.PolicySet, *policySource, error)
 
 	// Download asset gets a file from a github release and makes it available in a directory
 	DownloadAssetToTmp(*GetOptions, github.AssetDataProvider) (string, error)
@@ -57,20 +60,46 @@ type installerImplementation interface {
 	// VerifyAsset verifies that a file complioes with a set of policies
 	VerifyAsset(*Options, []*papi.PolicySet, github.AssetDataProvider, string) (bool, *papi.ResultSet, error)
 
+	// VerifySubject verifies an artifact by its digests, reading the
+	// attestations from local files or, when nil, from the release.
+	VerifySubject(*Options, []*papi.PolicySet, github.AssetDataProvider, attestation.Subject, []string) (bool, *papi.ResultSet, error)
+
+	// VerifyIntegrity checks an artifact without policies against the
+	// checksums and signatures published in its release.
+	VerifyIntegrity(*GetOptions, *github.Client, github.AssetDataProvider, string) (*integrity.Result, error)
+
 	// InstallAsset invokes the system mechanism to set up the downloaded artifact
 	// in the local machine.
 	InstallAsset(*GetOptions, *system.Info, *InstallArtifact, string) error
 
-	// RecordInstall registers a successful installation in the user's
-	// inventory database so it can later be verified, updated or removed.
-	RecordInstall(*GetOptions, *InstallArtifact, string, bool) error
+	// InstallExtras installs the shell completions and man pages of an
+	// installed binary, from a release archive (optional) or generated by
+	// the binary itself.
+	InstallExtras(*GetOptions, *InstallArtifact, string) error
+
+	// Authenticate prompts for the credentials of the privilege escalation
+	// helper so later privileged commands run without prompting.
+	Authenticate(*Options) error
+
+	// CheckInventory verifies that the inventory recording an install can
+	// be written before the artifact is installed.
+	CheckInventory(*GetOptions, *InstallArtifact) error
+
+	// RecordInstall registers a successful installation in the inventory
+	// database of its scope so it can later be verified, updated or removed.
+	RecordInstall(*GetOptions, *InstallArtifact, string, *verificationResult) error
+
+	// UninstallApp removes an installed app and deletes its record from
+	// the inventory.
+	UninstallApp(*Options, *inventory.Record) error
 }
 
 type defaultImplementation struct {
 	runner commandRunner
 
-	// inventoryPath overrides the location of the inventory database,
-	// when empty the default (in the user's config dir) is used.
+	// inventoryPath overrides the location of the inventory databases,
+	// recording the apps of both scopes in one file. When empty, the user
+	// and system inventories are used.
 	inventoryPath string
 }
 
@@ -98,83 +127,37 @@ func (di *defaultImplementation) ChooseAsset(opts *GetOptions, client *github.Cl
 	}
 
 	if asset := findInstallable(assets, spec); asset != nil {
-		// Found. Now check if it has variants for the local OS
+		// Found. Now check if it has variants for the local OS, falling
+		// back to the compatible arches when there is no native one.
 		if installable, ok := asset.(*github.Installable); ok {
-			var wantedVariant github.AssetDataProvider
-			var binaryVariant *github.Asset
-			sysPackageFormat := system.GetPreferredPackage(system.GetSystemOSFamily())
-
-			for _, variant := range installable.Variants {
-				// If the os or arch is not what we want, ignore it.
-				if variant.Os != opts.OS || variant.Arch != opts.Arch {
-					continue
-				}
-
-				// Signatures, SBOMs and other metadata files published
-				// along the artifacts are never chosen automatically.
-				if isMetadataFile(variant.GetName()) {
-					continue
-				}
-
-				// Check to see if its a package or archive
-				packageType := system.PackageExtensions.GetTypeFromFile(variant.GetName())
-				archiveType := system.ArchiveExtensions.GetTypeFromFile(variant.GetName())
-
-				// If we want a binary and this is a package or archive, ignore
-				if opts.DownloadType != "" && opts.DownloadType == "b" && (archiveType != "" || packageType != "") {
-					continue
-				}
-
-				// If we want a package and this is not one, ignore
-				if opts.DownloadType != "" && opts.DownloadType == "p" && packageType == "" {
-					continue
-				}
-
-				// Same for archive, skip if it is something else
-				if opts.DownloadType != "" && opts.DownloadType == "a" && archiveType == "" {
-					continue
-				}
-
-				// Binaries win over packages and archives, but keep
-				// looking in case the release ships several flavors for
-				// the platform: the canonical one (shortest name) wins.
-				if packageType == "" && archiveType == "" {
-					if binaryVariant == nil || len(variant.GetName()) < len(binaryVariant.GetName()) {
-						binaryVariant = variant
-					}
-					continue
-				}
-
-				// If we are looking for a package, check if the asset matches
-				// the system format:
-				if opts.DownloadType == "p" && packageType == sysPackageFormat {
-					opts.computedFilename = variant.GetName()
-					return variant, nil
-				}
-
-				// Otherwise capture the asset but prefer archives
-				if wantedVariant == nil {
-					wantedVariant = variant
-				} else if archiveType != "" {
-					wantedVariant = variant
-				}
+			family := system.GetSystemOSFamily()
+			ranking := rankVariants(installable, &rankOptions{
+				OS:            opts.OS,
+				Targets:       archTargets(opts),
+				PackageFormat: system.GetPreferredPackage(family),
+				DistroTags:    system.GetDistroTags(family, system.GetSystemOSVersion()),
+				DownloadType:  opts.DownloadType,
+			})
+			best := ranking.Best("")
+			if best != nil {
+				ranking.markChosen(best.Asset)
 			}
-
-			// Binaries are downloaded under the installable name
-			if binaryVariant != nil {
-				opts.computedFilename = installable.GetName()
-				if binaryVariant.Os == system.OSWindows {
-					opts.computedFilename += ".exe"
+			opts.explain(ranking)
+
+			if best != nil {
+				notifyFallback(opts, archTarget{Arch: best.Asset.Arch, Fallback: best.Fallback})
+				// Binaries are downloaded under the installable name
+				opts.computedFilename = best.Asset.GetName()
+				if best.Kind == VariantBinary {
+					opts.computedFilename = installable.GetName()
+					if best.Asset.Os == system.OSWindows {
+						opts.computedFilename += exeSuffix
+					}
 				}
-				return binaryVariant, nil
-			}
-
-			if wantedVariant != nil {
-				opts.computedFilename = wantedVariant.GetName()
-				return wantedVariant, nil
+				return best.Asset, nil
 			}
 
-			logrus.Debugf("no variant found for %s/%s", opts.OS, opts.Arch)
+			logrus.Debugf("no variant found for %s/%s (level %q)", opts.OS, opts.Arch, opts.ArchLevel)
 			return nil, ErrNoPlatformVariant
 		}
 
@@ -202,24 +185,88 @@ func (di *defaultImplementation) ChooseAsset(opts *GetOptions, client *github.Cl
 	return nil, fmt.Errorf("no asset found for %s", spec.GetRepo())
 }
 
-// FetchPolicies reads the artifact policies from the specified repo
-func (di *defaultImplementation) FetchPolicies(opts *Options, asset github.AssetDataProvider) ([]*papi.PolicySet, error) {
-	repoBaseUrl := fmt.Sprintf(
-		"https://%s/%s/%s", asset.GetHost(), asset.GetOrg(), defaultPolicyRepo,
-	)
-	if opts.PolicyRepository != "" {
-		repoBaseUrl = opts.PolicyRepository
+// FetchPolicies reads the artifact policies from the local policy files and
+// the policy repository, unless disabled. The policy repository is read at
+// the commit its ref (or default branch) resolves to, which is returned.
+func (di *defaultImplementation) FetchPolicies(
+	opts *Options, client *github.Client, asset github.AssetDataProvider,
+) ([]*papi.PolicySet, *policySource, error) {
+	ret := []*papi.PolicySet{}
+	if len(opts.PolicyFiles) > 0 {
+		opts.Listener.HandleEvent(
+			&Event{
+				Object: EventObjectPolicy, Verb: EventVerbGet,
+				Data: map[string]string{"path": strings.Join(opts.PolicyFiles, ", ")},
+			},
+		)
+		local, err := loadPolicyFiles(opts.PolicyFiles)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading local policies: %w", err)
+		}
+		ret = append(ret, local...)
+	}
+
+	var source *policySource
+	if !opts.DisablePolicyRepo {
+		opts.Listener.HandleEvent(
+			&Event{
+				Object: EventObjectPolicy, Verb: EventVerbGet,
+				Data: map[string]string{"repo": policyRepository(opts, asset)},
+			},
+		)
+		var err error
+		source, err = resolvePolicySource(opts, client, asset)
+		if err != nil {
+			return nil, nil, err
+		}
+		if source == nil {
+			logrus.Debugf("policy repository %s does not exist", policyRepository(opts, asset))
+		} else {
+			remote, err := di.fetchRepoPolicies(opts, source, asset)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(remote) == 0 {
+				source = nil
+			}
+			ret = append(ret, remote...)
+		}
 	}
 
+	data := map[string]string{"count": fmt.Sprintf("%d", len(ret))}
+	if source != nil {
+		data["commit"] = source.Commit
+		data["signer"] = source.Signer
+	}
 	opts.Listener.HandleEvent(
-		&Event{
-			Object: EventObjectPolicy, Verb: EventVerbGet,
-			Data: map[string]string{"repo": repoBaseUrl},
-		},
+		&Event{Object: EventObjectPolicy, Verb: EventVerbDone, Data: data},
 	)
 
+	return ret, source, nil
+}
+
+// policyRepository returns the repository to read the artifact policies
+// from: the one set in the options, the one mapped to the artifact's repo or
+// org or, by default, the .ampel repository of its org.
+func policyRepository(opts *Options, asset github.AssetDataProvider) string {
+	if opts.PolicyRepository != "" {
+		return opts.PolicyRepository
+	}
+	if repo := opts.PolicyMap.Resolve(asset.GetHost(), asset.GetOrg(), asset.GetRepo()); repo != "" {
+		return repo
+	}
+	return fmt.Sprintf(
+		"https://%s/%s/%s", asset.GetHost(), asset.GetOrg(), defaultPolicyRepo,
+	)
+}
+
+// fetchRepoPolicies reads the artifact policies from the policy repository
+// at the resolved commit.
+func (di *defaultImplementation) fetchRepoPolicies(
+	opts *Options, source *policySource, asset github.AssetDataProvider,
+) ([]*papi.PolicySet, error) {
 	locator := fmt.Sprintf(
-		"%s#policy/%s/%s/%s", repoBaseUrl,
+		"%s@%s#policy/%s/%s/%s", source.Repository, source.Commit,
 		asset.GetHost(), asset.GetOrg(), asset.GetRepo(),
 	)
 
@@ -242,7 +289,7 @@ func (di *defaultImplementation) FetchPolicies(opts *Options, asset github.Asset
 
 	// Now, fetch all policy attestations
 	attestations, err := agent.FetchAttestationsByPredicateType(
-		context.Background(), []attestation.PredicateType{"https://carabiner.dev/ampel/policyset/v0.0.1"},
+		context.Background(), []attestation.PredicateType{policySetPredicateType},
 	)
 	// If there were errors fetching attestations, there are two special
 	// cases we want to handle as non-errors:
@@ -285,14 +332,6 @@ func (di *defaultImplementation) FetchPolicies(opts *Options, asset github.Asset
 		}
 		ret = append(ret, pset)
 	}
-
-	opts.Listener.HandleEvent(
-		&Event{
-			Object: EventObjectPolicy, Verb: EventVerbDone,
-			Data: map[string]string{"count": fmt.Sprintf("%d", len(ret))},
-		},
-	)
-
 	return ret, nil
 }
 
@@ -336,40 +375,51 @@ func (di *defaultImplementation) DownloadAssetToTmp(opts *GetOptions, asset gith
 func (di *defaultImplementation) VerifyAsset(
 	opts *Options, policies []*papi.PolicySet, asset github.AssetDataProvider, filePath string,
 ) (bool, *papi.ResultSet, error) {
-	// Create a verifier, for now we will only support attestations
-	// published along the artifact (as GitHub assets):
+	// Generate the subject resource descriptors from the file
+	res, err := hasher.New().HashFiles([]string{filePath})
+	if err != nil {
+		return false, nil, fmt.Errorf("hashing file: %w", err)
+	}
+	if len(*res) != 1 {
+		return false, nil, fmt.Errorf("expected one set of hashes from file, got %d", len(*res))
+	}
+	return di.VerifySubject(opts, policies, asset, res.ToResourceDescriptors()[0], nil)
+}
 
+// VerifySubject evaluates the policies on an artifact subject. Attestations
+// are read from the files when specified or, when nil, collected from the
+// release of the asset.
+func (di *defaultImplementation) VerifySubject(
+	opts *Options, policies []*papi.PolicySet, asset github.AssetDataProvider,
+	subject attestation.Subject, attestationFiles []string,
+) (bool, *papi.ResultSet, error) {
 	opts.Listener.HandleEvent(
 		&Event{Object: EventObjectVerification, Verb: EventVerbRunning},
 	)
 
-	// Create the collector
-	clctr, err := release.New(
-		release.WithRepo(asset.GetRepoURL()),
-		release.WithTag(asset.GetVersion()),
-	)
-	if err != nil {
-		return false, nil, fmt.Errorf("unable to create release attestation collector")
+	verificationOpts := verifier.DefaultVerificationOptions
+	verificationOpts.AttestationFiles = attestationFiles
+
+	// Create the new ampel verifier. Without attestation files, it reads
+	// those published along the artifact (as GitHub assets).
+	vrfr, err := verifier.New()
+	if attestationFiles == nil {
+		clctr, cerr := release.New(
+			release.WithRepo(asset.GetRepoURL()),
+			release.WithTag(asset.GetVersion()),
+		)
+		if cerr != nil {
+			return false, nil, fmt.Errorf("unable to create release attestation collector")
+		}
+		vrfr, err = verifier.New(verifier.WithCollector(clctr))
 	}
-
-	// Create the new ampel verifier
-	vrfr, err := verifier.New(verifier.WithCollector(clctr))
 	if err != nil {
 		return false, nil, fmt.Errorf("creating new AMPEL verifier: %w", err)
 	}
 
-	// Generate the subject resource descriptors from the file
-	res, err := hasher.New().HashFiles([]string{filePath})
-	if err != nil {
-		return false, nil, fmt.Errorf("hashing file: %w", err)
-	}
-	if len(*res) != 1 {
-		return false, nil, fmt.Errorf("expected one set of hashes from file, got %d", len(*res))
-	}
-
 	// Run the artifact verification
 	results, err := vrfr.Verify(
-		context.Background(), &verifier.DefaultVerificationOptions, policies, res.ToResourceDescriptors()[0],
+		context.Background(), &verificationOpts, policies, subject,
 	)
 	if err != nil {
 		return false, nil, fmt.Errorf("error running artifact verification: %w", err)
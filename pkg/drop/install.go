@@ -4,20 +4,26 @@
 package drop
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/carabiner-dev/drop/pkg/github"
 	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/pkgmeta"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
 
@@ -30,25 +36,29 @@ var (
 type ArtifactKind string
 
 const (
-	ArtifactBinary  ArtifactKind = "binary"
-	ArtifactPackage ArtifactKind = "package"
+	ArtifactBinary   ArtifactKind = "binary"
+	ArtifactPackage  ArtifactKind = "package"
+	ArtifactAppImage ArtifactKind = "appimage"
 )
 
 // Command and filename constants used when installing artifacts
 const (
-	cmdSudo     = "sudo"
-	cmdDnf      = "dnf"
-	cmdYum      = "yum"
-	cmdRPM      = "rpm"
-	cmdApt      = "apt"
-	cmdDpkg     = "dpkg"
-	cmdApk      = "apk"
-	verbInstall = "install"
-	exeSuffix   = ".exe"
-
-	dataKeyKind = "kind"
-	dataKeyName = "name"
-	dataKeySudo = "sudo"
+	cmdDnf       = "dnf"
+	cmdYum       = "yum"
+	cmdRPM       = "rpm"
+	cmdZypper    = "zypper"
+	cmdApt       = "apt"
+	cmdDpkg      = "dpkg"
+	cmdDpkgQuery = "dpkg-query"
+	cmdApk       = "apk"
+	cmdPacman    = "pacman"
+	cmdRm        = "rm"
+	verbInstall  = "install"
+	exeSuffix    = ".exe"
+
+	dataKeyKind       = "kind"
+	dataKeyName       = "name"
+	dataKeyEscalation = "escalation"
 )
 
 // InstallArtifact is a concrete release asset chosen for installation.
@@ -56,7 +66,7 @@ type InstallArtifact struct {
 	// Kind is the artifact type, binary or package.
 	Kind ArtifactKind
 
-	// PackageFormat is the package type (rpm, deb, apk) when Kind is package.
+	// PackageFormat is the package type (rpm, deb, apk, pacman) when Kind is package.
 	PackageFormat string
 
 	// Asset is the release asset variant to download.
@@ -64,6 +74,32 @@ type InstallArtifact struct {
 
 	// InstallName is the name the binary gets when installed into the path.
 	InstallName string
+
+	// Fallback is set when the artifact was built for an alternative arch
+	// from the platform's fallback chain.
+	Fallback *system.ArchFallback
+
+	// PackageMeta is the package identity read from the downloaded package
+	// (packages only). It is nil until the package is read at install time.
+	PackageMeta *pkgmeta.Metadata
+
+	// Extras is a release archive of the same platform, downloaded to
+	// look for shell completions and man pages for the binary.
+	Extras *github.Asset
+
+	// Files lists the files written when the package is unpacked into a
+	// prefix instead of being installed by the package manager. For
+	// AppImages it holds the installed AppImage followed by its desktop
+	// entry and icon. For binaries, the completions and man pages.
+	Files []string
+
+	// Dirs lists the directories created in the prefix for the unpacked
+	// files.
+	Dirs []string
+
+	// SBOM is the SBOM published for the artifact, set once it is stored
+	// at install time.
+	SBOM *InstalledSBOM
 }
 
 // ArtifactSelector resolves an ambiguous choice between install candidates.
@@ -75,6 +111,7 @@ type ArtifactSelector func(candidates []*InstallArtifact) (*InstallArtifact, err
 type installCandidates struct {
 	Binary      *InstallArtifact
 	Package     *InstallArtifact
+	AppImage    *InstallArtifact
 	HasArchives bool
 	HasOtherPkg bool
 }
@@ -85,6 +122,7 @@ type installCandidates struct {
 var metadataSuffixes = []string{
 	".json", ".jsonl", ".sig", ".pem", ".cert", ".crt", ".asc", ".pub",
 	".txt", ".md", ".sbom", ".bundle", ".sigstore", ".sha256", ".sha512",
+	".zsync",
 }
 
 // isMetadataFile returns true for release files that hold artifact metadata
@@ -99,60 +137,32 @@ func isMetadataFile(name string) bool {
 	return false
 }
 
-// classifyInstallCandidates inspects an installable's variants for the given
+// classifyInstallCandidates ranks an installable's variants for the given
 // platform and classifies them into a binary candidate and a package candidate
-// matching the system's package format.
-func classifyInstallCandidates(inst *github.Installable, osName, arch, pkgFormat string) *installCandidates {
-	cands := &installCandidates{}
-	for _, variant := range inst.Variants {
-		if variant.Os != osName || variant.Arch != arch {
-			continue
-		}
-
-		packageType := system.PackageExtensions.GetTypeFromFile(variant.GetName())
-		archiveType := system.ArchiveExtensions.GetTypeFromFile(variant.GetName())
-
-		switch {
-		case archiveType != "":
-			cands.HasArchives = true
-		case packageType == "":
-			// Signatures, SBOMs and other metadata files also carry
-			// platform markers in their names but are not installable.
-			if isMetadataFile(variant.GetName()) {
-				continue
-			}
-			// When a release ships more than one binary flavor for the
-			// platform, prefer the canonical one: the shortest filename
-			// (e.g. cosign-linux-amd64 over cosign-linux-pivkey-amd64).
-			if cands.Binary != nil && len(cands.Binary.Asset.GetName()) <= len(variant.GetName()) {
-				continue
-			}
-			name := inst.GetName()
-			if variant.Os == system.OSWindows {
-				name += exeSuffix
-			}
-			cands.Binary = &InstallArtifact{
-				Kind: ArtifactBinary, Asset: variant, InstallName: name,
-			}
-		case pkgFormat != "" && packageType == pkgFormat:
-			cands.Package = &InstallArtifact{
-				Kind: ArtifactPackage, PackageFormat: packageType,
-				Asset: variant, InstallName: inst.GetName(),
-			}
-		default:
-			cands.HasOtherPkg = true
-		}
-	}
-	return cands
+// matching the system's package format. Variants built for a CPU level above
+// the specified one are ignored.
+func classifyInstallCandidates(inst *github.Installable, osName, arch, level, pkgFormat string) *installCandidates {
+	ranking := rankVariants(inst, &rankOptions{
+		OS:            osName,
+		Targets:       []archTarget{{Arch: arch, Level: level}},
+		PackageFormat: pkgFormat,
+		Install:       true,
+	})
+	return ranking.installCandidates(inst, pkgFormat)
 }
 
 // classifySingleAsset builds an install artifact from a single concrete asset,
 // for when the user pinned an exact file instead of an installable.
 func classifySingleAsset(asset *github.Asset, installName, pkgFormat string) (*InstallArtifact, error) {
 	name := asset.GetName()
-	if system.ArchiveExtensions.GetTypeFromFile(name) != "" {
+	if system.IsArchive(name) {
 		return nil, ErrOnlyArchives
 	}
+	if system.IsAppImage(name) {
+		return &InstallArtifact{
+			Kind: ArtifactAppImage, Asset: asset, InstallName: installName,
+		}, nil
+	}
 	if pkgType := system.PackageExtensions.GetTypeFromFile(name); pkgType != "" {
 		if pkgFormat == "" || pkgType != pkgFormat {
 			return nil, ErrNoInstallableArtifact
@@ -173,7 +183,7 @@ func classifySingleAsset(asset *github.Asset, installName, pkgFormat string) (*I
 // decideArtifact applies the install selection algorithm: honor a forced type,
 // use the only candidate available, stay with the package manager when the app
 // is already installed as a package, otherwise ask the selector (or default to
-// the binary when running non-interactively).
+// the binary, then the AppImage, when running non-interactively).
 func decideArtifact(c *installCandidates, opts *GetOptions, pkgInstalled func(name string) bool) (*InstallArtifact, error) {
 	switch opts.DownloadType {
 	case "b":
@@ -186,34 +196,45 @@ func decideArtifact(c *installCandidates, opts *GetOptions, pkgInstalled func(na
 			return nil, fmt.Errorf("no package in the system format available: %w", ErrNoInstallableArtifact)
 		}
 		return c.Package, nil
+	case "i":
+		if c.AppImage == nil {
+			return nil, fmt.Errorf("no AppImage available: %w", ErrNoInstallableArtifact)
+		}
+		return c.AppImage, nil
 	}
 
-	switch {
-	case c.Binary == nil && c.Package == nil:
+	available := []*InstallArtifact{}
+	for _, a := range []*InstallArtifact{c.Binary, c.AppImage, c.Package} {
+		if a != nil {
+			available = append(available, a)
+		}
+	}
+
+	switch len(available) {
+	case 0:
 		if c.HasArchives {
 			return nil, ErrOnlyArchives
 		}
 		return nil, ErrNoInstallableArtifact
-	case c.Package == nil:
-		return c.Binary, nil
-	case c.Binary == nil:
-		return c.Package, nil
+	case 1:
+		return available[0], nil
 	}
 
-	if pkgInstalled != nil && pkgInstalled(c.Package.InstallName) {
+	if c.Package != nil && pkgInstalled != nil && pkgInstalled(c.Package.InstallName) {
 		return c.Package, nil
 	}
 
 	if opts.Selector != nil {
-		return opts.Selector([]*InstallArtifact{c.Binary, c.Package})
+		return opts.Selector(available)
 	}
 
-	return c.Binary, nil
+	return available[0], nil
 }
 
 // buildPackageInstallCmd returns the argv to install a local package file
-// using the system's package manager.
-func buildPackageInstallCmd(format, pkgPath string, sudo bool, lookPath func(string) (string, error)) ([]string, error) {
+// using the system's package manager. The command is returned without the
+// privilege escalation helper, the runner adds it when needed.
+func buildPackageInstallCmd(format, pkgPath string, lookPath func(string) (string, error)) ([]string, error) {
 	has := func(tool string) bool {
 		_, err := lookPath(tool)
 		return err == nil
@@ -227,10 +248,16 @@ func buildPackageInstallCmd(format, pkgPath string, sudo bool, lookPath func(str
 			argv = []string{cmdDnf, verbInstall, "-y", pkgPath}
 		case has(cmdYum):
 			argv = []string{cmdYum, verbInstall, "-y", pkgPath}
+		case has(cmdZypper):
+			// zypper refuses unsigned local rpms unless told otherwise. The
+			// artifact was already verified against its policies, so the
+			// check of the package's own signature is relaxed. Packages
+			// from repositories are still checked.
+			argv = []string{cmdZypper, "--non-interactive", verbInstall, "--allow-unsigned-rpm", pkgPath}
 		case has(cmdRPM):
 			argv = []string{cmdRPM, "-Uvh", pkgPath}
 		default:
-			return nil, errors.New("no rpm package manager (dnf/yum/rpm) found in PATH")
+			return nil, errors.New("no rpm package manager (dnf/yum/zypper/rpm) found in PATH")
 		}
 	case system.PackageDeb:
 		// apt needs a path (not a package name) to install a local file
@@ -253,16 +280,15 @@ func buildPackageInstallCmd(format, pkgPath string, sudo bool, lookPath func(str
 		// Local apk files are not signed by a repository key, the artifact
 		// was already verified against its policies before reaching this.
 		argv = []string{cmdApk, "add", "--allow-untrusted", pkgPath}
+	case system.PackagePacman:
+		if !has(cmdPacman) {
+			return nil, errors.New("pacman not found in PATH")
+		}
+		argv = []string{cmdPacman, "-U", "--noconfirm", pkgPath}
 	default:
 		return nil, fmt.Errorf("unsupported package format %q", format)
 	}
 
-	if sudo {
-		if !has(cmdSudo) {
-			return nil, errors.New("sudo not found in PATH, rerun as root")
-		}
-		argv = append([]string{cmdSudo}, argv...)
-	}
 	return argv, nil
 }
 
@@ -276,10 +302,14 @@ func buildPackageQueryCmd(format, name string, lookPath func(string) (string, er
 
 	switch format {
 	case system.PackageRPM:
-		if !has(cmdRPM) {
+		switch {
+		case has(cmdRPM):
+			return []string{cmdRPM, "-q", name}, nil
+		case has(cmdZypper):
+			return []string{cmdZypper, "--non-interactive", "search", "--installed-only", "--match-exact", name}, nil
+		default:
 			return nil, errors.New("rpm not found in PATH")
 		}
-		return []string{cmdRPM, "-q", name}, nil
 	case system.PackageDeb:
 		if !has(cmdDpkg) {
 			return nil, errors.New("dpkg not found in PATH")
@@ -290,11 +320,127 @@ func buildPackageQueryCmd(format, name string, lookPath func(string) (string, er
 			return nil, errors.New("apk not found in PATH")
 		}
 		return []string{cmdApk, "info", "-e", name}, nil
+	case system.PackagePacman:
+		if !has(cmdPacman) {
+			return nil, errors.New("pacman not found in PATH")
+		}
+		return []string{cmdPacman, "-Q", name}, nil
 	default:
 		return nil, fmt.Errorf("unsupported package format %q", format)
 	}
 }
 
+// buildPackageVersionQueryCmd returns the argv that prints the version of an
+// installed package in the same form the package metadata reports it.
+func buildPackageVersionQueryCmd(format, name string, lookPath func(string) (string, error)) ([]string, error) {
+	has := func(tool string) bool {
+		_, err := lookPath(tool)
+		return err == nil
+	}
+
+	switch format {
+	case system.PackageRPM:
+		if !has(cmdRPM) {
+			return nil, errors.New("rpm not found in PATH")
+		}
+		return []string{cmdRPM, "-q", "--qf", "%{EPOCH}:%{VERSION}-%{RELEASE}", name}, nil
+	case system.PackageDeb:
+		if !has(cmdDpkgQuery) {
+			return nil, errors.New("dpkg-query not found in PATH")
+		}
+		return []string{cmdDpkgQuery, "-W", "-f=${db:Status-Abbrev} ${Version}", name}, nil
+	case system.PackagePacman:
+		if !has(cmdPacman) {
+			return nil, errors.New("pacman not found in PATH")
+		}
+		return []string{cmdPacman, "-Q", name}, nil
+	default:
+		return nil, fmt.Errorf("querying versions of %q packages is not supported", format)
+	}
+}
+
+// parseInstalledVersion extracts the version from the output of the version
+// query command. It returns an empty string when the package is not fully
+// installed.
+func parseInstalledVersion(format string, output []byte) string {
+	out := strings.TrimSpace(string(output))
+	switch format {
+	case system.PackagePacman:
+		// pacman -Q prints "name version"
+		if _, v, ok := strings.Cut(out, " "); ok {
+			return strings.TrimSpace(v)
+		}
+		return ""
+	case system.PackageDeb:
+		// Removed packages keeping their conffiles (rc) still report a
+		// version, only the installed ones (ii) count.
+		status, v, ok := strings.Cut(out, " ")
+		if !ok || status != "ii" {
+			return ""
+		}
+		return strings.TrimSpace(v)
+	case system.PackageRPM:
+		// rpm prints (none) when the package has no epoch, drop it to
+		// match the version read from the package metadata.
+		if epoch, v, ok := strings.Cut(out, ":"); ok && (epoch == "(none)" || epoch == "0") {
+			return v
+		}
+		return out
+	default:
+		return out
+	}
+}
+
+// buildPackageRemoveCmd returns the argv to remove an installed package using
+// the system's package manager. The name must be the real package name as
+// recorded from the package metadata, not the installable name.
+func buildPackageRemoveCmd(format, name string, lookPath func(string) (string, error)) ([]string, error) {
+	has := func(tool string) bool {
+		_, err := lookPath(tool)
+		return err == nil
+	}
+
+	var argv []string
+	switch format {
+	case system.PackageRPM:
+		switch {
+		case has(cmdDnf):
+			argv = []string{cmdDnf, "remove", "-y", name}
+		case has(cmdYum):
+			argv = []string{cmdYum, "remove", "-y", name}
+		case has(cmdZypper):
+			argv = []string{cmdZypper, "--non-interactive", "remove", name}
+		case has(cmdRPM):
+			argv = []string{cmdRPM, "-e", name}
+		default:
+			return nil, errors.New("no rpm package manager (dnf/yum/zypper/rpm) found in PATH")
+		}
+	case system.PackageDeb:
+		switch {
+		case has(cmdApt):
+			argv = []string{cmdApt, "remove", "-y", name}
+		case has(cmdDpkg):
+			argv = []string{cmdDpkg, "-r", name}
+		default:
+			return nil, errors.New("no deb package manager (apt/dpkg) found in PATH")
+		}
+	case system.PackageApk:
+		if !has(cmdApk) {
+			return nil, errors.New("apk not found in PATH")
+		}
+		argv = []string{cmdApk, "del", name}
+	case system.PackagePacman:
+		if !has(cmdPacman) {
+			return nil, errors.New("pacman not found in PATH")
+		}
+		argv = []string{cmdPacman, "-R", "--noconfirm", name}
+	default:
+		return nil, fmt.Errorf("unsupported package format %q", format)
+	}
+
+	return argv, nil
+}
+
 // commandRunner abstracts running external commands so the install logic
 // can be tested without touching the system.
 type commandRunner interface {
@@ -302,9 +448,21 @@ type commandRunner interface {
 	// like sudo can prompt the user).
 	Run(argv []string) error
 
+	// RunPrivileged executes a command with elevated privileges through
+	// an escalation helper (sudo, doas, run0 or pkexec), inheriting the
+	// standard streams so the helper can prompt the user.
+	RunPrivileged(helper string, argv []string) error
+
 	// RunSilent executes a command discarding its output.
 	RunSilent(argv []string) error
 
+	// RunSilentIn executes a command in a working directory discarding
+	// its output.
+	RunSilentIn(dir string, argv []string) error
+
+	// Output executes a command and returns its standard output.
+	Output(argv []string) ([]byte, error)
+
 	// LookPath checks if an executable is available in the system path.
 	LookPath(file string) (string, error)
 }
@@ -319,10 +477,28 @@ func (*execRunner) Run(argv []string) error {
 	return cmd.Run()
 }
 
+func (r *execRunner) RunPrivileged(helper string, argv []string) error {
+	return r.Run(escalatedArgv(helper, argv))
+}
+
 func (*execRunner) RunSilent(argv []string) error {
 	return exec.CommandContext(context.Background(), argv[0], argv[1:]...).Run() //nolint:gosec // argv comes from fixed command tables
 }
 
+func (*execRunner) RunSilentIn(dir string, argv []string) error {
+	cmd := exec.CommandContext(context.Background(), argv[0], argv[1:]...) //nolint:gosec // argv comes from fixed command tables
+	cmd.Dir = dir
+	return cmd.Run()
+}
+
+func (*execRunner) Output(argv []string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(context.Background(), argv[0], argv[1:]...) //nolint:gosec // argv comes from fixed command tables
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	return stdout.Bytes(), err
+}
+
 func (*execRunner) LookPath(file string) (string, error) {
 	return exec.LookPath(file)
 }
@@ -429,7 +605,18 @@ func (di *defaultImplementation) SelectInstallArtifact(
 		return artifact, nil
 	}
 
-	cands := classifyInstallCandidates(inst, opts.OS, opts.Arch, pkgFormat)
+	// Rank the variants of the requested arch and its fallbacks. The
+	// candidates come from the best arch offering the requested type.
+	ranking := rankVariants(inst, &rankOptions{
+		OS:            opts.OS,
+		Targets:       archTargets(opts),
+		PackageFormat: pkgFormat,
+		DistroTags:    system.GetDistroTags(info.Family, info.OSVersion),
+		DownloadType:  opts.DownloadType,
+		Install:       true,
+		Unpack:        opts.Prefix != "",
+	})
+	cands := ranking.installCandidates(inst, pkgFormat)
 
 	if binaryOnly && cands.HasOtherPkg {
 		opts.Listener.HandleEvent(&Event{
@@ -438,20 +625,76 @@ func (di *defaultImplementation) SelectInstallArtifact(
 		})
 	}
 
-	artifact, err := decideArtifact(cands, opts, func(name string) bool {
-		return di.packageInstalled(pkgFormat, name)
-	})
+	// When unpacking into a prefix, the package manager is not involved
+	var pkgInstalled func(string) bool
+	if opts.Prefix == "" {
+		pkgInstalled = func(name string) bool {
+			return di.packageInstalled(pkgFormat, di.recordedPackageName(spec, name))
+		}
+	}
+	artifact, err := decideArtifact(cands, opts, pkgInstalled)
 	if err != nil {
+		opts.explain(ranking)
 		return nil, err
 	}
 
+	ranking.markChosen(artifact.Asset)
+	opts.explain(ranking)
+	if opts.Completions && artifact.Kind == ArtifactBinary {
+		artifact.Extras = ranking.archiveFor(artifact.Asset.Arch)
+	}
+	notifyFallback(opts, archTarget{Arch: artifact.Asset.Arch, Fallback: artifact.Fallback})
+
 	opts.computedFilename = artifact.Asset.GetName()
 	return artifact, nil
 }
 
+// recordedPackageName returns the real package name of an app recorded in
+// the inventory when it was installed as a package before. When there is no
+// record it returns the installable name.
+func (di *defaultImplementation) recordedPackageName(spec github.AssetDataProvider, name string) string {
+	if record := di.recordedPackage(spec, name); record != nil {
+		return record.PackageName
+	}
+	return name
+}
+
+// recordedPackage returns the inventory record of an app installed as a
+// system package, or nil when the app was not installed as a package.
+func (di *defaultImplementation) recordedPackage(spec github.AssetDataProvider, name string) *inventory.Record {
+	return di.findRecord(spec, name, func(r *inventory.Record) bool {
+		return r.PackageName != ""
+	})
+}
+
+// findRecord returns the inventory record of an app matching a condition,
+// looking in the system inventory first. It returns nil when none matches.
+func (di *defaultImplementation) findRecord(
+	spec github.AssetDataProvider, name string, match func(*inventory.Record) bool,
+) *inventory.Record {
+	key := (&inventory.Record{
+		Host: spec.GetHost(), Org: spec.GetOrg(), Repo: spec.GetRepo(), Name: name,
+	}).Key()
+	for _, scope := range []string{ScopeSystem, ScopeUser} {
+		path, err := di.scopeInventoryPath(scope)
+		if err != nil {
+			continue
+		}
+		inv, err := inventory.OpenFile(path)
+		if err != nil {
+			continue
+		}
+		if record := inv.Get(key); record != nil && match(record) {
+			return record
+		}
+	}
+	return nil
+}
+
 // packageInstalled checks (best effort) if a package is already installed in
-// the system. The queried name is the installable name, which may differ from
-// the actual package name; a miss only means the user gets asked.
+// the system. Before the package is downloaded its real name is only known if
+// a previous install recorded it; otherwise the installable name is queried
+// and a miss only means the user gets asked.
 func (di *defaultImplementation) packageInstalled(format, name string) bool {
 	if format == "" || name == "" {
 		return false
@@ -473,40 +716,47 @@ func (di *defaultImplementation) InstallAsset(
 		return di.installBinary(opts, info, artifact, path)
 	case ArtifactPackage:
 		return di.installPackage(opts, artifact, path)
+	case ArtifactAppImage:
+		return di.installAppImage(opts, artifact, path)
 	default:
 		return fmt.Errorf("unknown artifact kind %q", artifact.Kind)
 	}
 }
 
 // installBinary copies the downloaded binary to the configured directory,
-// shelling out to sudo when the directory is not writable by the user.
+// escalating privileges when the directory is not writable by the user.
 func (di *defaultImplementation) installBinary(
 	opts *GetOptions, info *system.Info, artifact *InstallArtifact, path string,
 ) error {
 	target := filepath.Join(opts.BinDir, artifact.InstallName)
-	sudo := !dirWritable(opts.BinDir)
-
-	if sudo {
+	if opts.Prefix != "" || opts.Scope == ScopeUser {
+		if err := os.MkdirAll(opts.BinDir, 0o755); err != nil {
+			return fmt.Errorf("creating binaries directory: %w", err)
+		}
+	}
+	helper := ""
+	if !dirWritable(opts.BinDir) {
 		if info.Os == system.OSWindows {
 			return fmt.Errorf("directory %q is not writable", opts.BinDir)
 		}
-		if _, err := di.runner.LookPath(cmdSudo); err != nil {
-			return fmt.Errorf("%q is not writable and sudo is not available, rerun as root or set another binary directory", opts.BinDir)
+		var err error
+		if helper, err = di.escalationHelper(&opts.Options); err != nil {
+			return fmt.Errorf("%q is not writable: %w, rerun as root or set another binary directory", opts.BinDir, err)
 		}
 	}
 
 	opts.Listener.HandleEvent(&Event{
 		Object: EventObjectInstall, Verb: EventVerbRunning,
 		Data: map[string]string{
-			dataKeyKind: string(ArtifactBinary),
-			dataKeyName: artifact.InstallName,
-			"target":    target,
-			dataKeySudo: strconv.FormatBool(sudo),
+			dataKeyKind:       string(ArtifactBinary),
+			dataKeyName:       artifact.InstallName,
+			"target":          target,
+			dataKeyEscalation: helper,
 		},
 	})
 
-	if sudo {
-		if err := di.runner.Run([]string{cmdSudo, verbInstall, "-m", "0755", path, target}); err != nil {
+	if helper != "" {
+		if err := di.runner.RunPrivileged(helper, []string{verbInstall, "-m", "0755", path, target}); err != nil {
 			return fmt.Errorf("installing binary: %w", err)
 		}
 	} else {
@@ -523,9 +773,29 @@ func (di *defaultImplementation) installBinary(
 			"path":      target,
 		},
 	})
+	checkPath(opts, target, os.Getenv("PATH"))
 	return nil
 }
 
+// checkPath warns when the installed binary cannot be run by name: its
+// directory is not in the PATH or another binary with the same name comes
+// first in it.
+func checkPath(opts *GetOptions, target, pathEnv string) {
+	if !system.DirInPath(filepath.Dir(target), pathEnv) {
+		opts.Listener.HandleEvent(&Event{
+			Object: EventObjectPath, Verb: EventVerbMissing,
+			Data: map[string]string{"dir": filepath.Dir(target)},
+		})
+		return
+	}
+	if other := system.ShadowingBinary(target, pathEnv); other != "" {
+		opts.Listener.HandleEvent(&Event{
+			Object: EventObjectPath, Verb: EventVerbShadowed,
+			Data: map[string]string{"path": target, "by": other},
+		})
+	}
+}
+
 // fileDigest returns the hex-encoded sha256 hash of a file.
 func fileDigest(path string) (string, error) {
 	f, err := os.Open(path) //nolint:gosec
@@ -541,22 +811,148 @@ func fileDigest(path string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-// RecordInstall registers a successful installation in the user's inventory
-// database so it can later be verified, updated or removed.
-func (di *defaultImplementation) RecordInstall(
-	opts *GetOptions, artifact *InstallArtifact, downloadPath string, verified bool,
-) error {
-	var inv *inventory.Inventory
-	var err error
-	if di.inventoryPath == "" {
-		inv, err = inventory.Open()
-	} else {
-		inv, err = inventory.OpenFile(di.inventoryPath)
+// scopeInventoryPath returns the location of the inventory database of a
+// scope, unless overridden.
+func (di *defaultImplementation) scopeInventoryPath(scope string) (string, error) {
+	if di.inventoryPath != "" {
+		return di.inventoryPath, nil
+	}
+	return inventory.ScopePath(scope)
+}
+
+// inventoryScope returns the inventory recording an install. Packages
+// handed to the package manager and apps installed outside the user's home
+// are recorded in the system inventory, where root and other users see
+// them. Apps unpacked into a prefix stay in the user inventory.
+func inventoryScope(opts *GetOptions, artifact *InstallArtifact) string {
+	if opts.Prefix != "" {
+		return ScopeUser
+	}
+	if opts.Scope == ScopeSystem || artifact.Kind == ArtifactPackage {
+		return ScopeSystem
+	}
+
+	target := opts.BinDir
+	if artifact.Kind == ArtifactAppImage {
+		dir, err := appImageDir(opts)
+		if err != nil {
+			return cmp.Or(opts.Scope, ScopeUser)
+		}
+		target = dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || target == "" {
+		return cmp.Or(opts.Scope, ScopeUser)
+	}
+	if rel, err := filepath.Rel(home, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ScopeSystem
+	}
+	return ScopeUser
+}
+
+// Subcommands of drop's inventory command that update the system inventory.
+// Users that cannot write to it run them as root through the escalation
+// helper.
+const (
+	InventoryCmdPut    = "put"
+	InventoryCmdDelete = "delete"
+)
+
+// CheckInventory verifies, before anything is installed, that the inventory
+// recording the install can be written, directly or through the escalation
+// helper for the system inventory.
+func (di *defaultImplementation) CheckInventory(opts *GetOptions, artifact *InstallArtifact) error {
+	scope := inventoryScope(opts, artifact)
+	path, err := di.scopeInventoryPath(scope)
+	if err != nil {
+		return err
+	}
+	_, err = di.inventoryHelper(&opts.Options, scope, path)
+	return err
+}
+
+// inventoryHelper returns the escalation helper to update an inventory
+// through, or an empty string when the current user can write to it. Only
+// the system inventory is updated with privileges.
+func (di *defaultImplementation) inventoryHelper(opts *Options, scope, path string) (string, error) {
+	err := inventory.Writable(path)
+	if err == nil {
+		return "", nil
+	}
+	if scope != ScopeSystem || os.Geteuid() == 0 {
+		return "", fmt.Errorf("the %s inventory is not writable: %w", scope, err)
+	}
+	helper, herr := di.escalationHelper(opts)
+	if herr != nil {
+		return "", fmt.Errorf("the system inventory is not writable: %w, rerun as root", herr)
 	}
+	return helper, nil
+}
+
+// inventoryRequiresPrivileges returns true if the inventory of a record can
+// only be updated through the escalation helper
+func inventoryRequiresPrivileges(record *inventory.Record) bool {
+	return record.Scope == ScopeSystem && os.Geteuid() != 0 && inventory.Writable(inventory.SystemPath()) != nil
+}
+
+// runInventoryCommand updates the system inventory by running drop's
+// inventory command as root through the escalation helper, so the change
+// is made under the inventory lock like any other update.
+func (di *defaultImplementation) runInventoryCommand(helper string, args ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating the drop executable: %w", err)
+	}
+	if err := di.runner.RunPrivileged(helper, append([]string{exe, "inventory"}, args...)); err != nil {
+		return fmt.Errorf("updating the system inventory: %w", err)
+	}
+	return nil
+}
+
+// recordInInventory adds a record to the inventory of its scope
+func (di *defaultImplementation) recordInInventory(opts *Options, record *inventory.Record) error {
+	scope := cmp.Or(record.Scope, ScopeUser)
+	path, err := di.scopeInventoryPath(scope)
 	if err != nil {
-		return fmt.Errorf("opening install inventory: %w", err)
+		return err
+	}
+	helper, err := di.inventoryHelper(opts, scope, path)
+	if err != nil {
+		return err
+	}
+	if helper == "" {
+		return inventory.UpdateFile(path, func(inv *inventory.Inventory) error {
+			inv.Add(record)
+			return nil
+		})
 	}
 
+	// The record is handed to the privileged drop in a temporary file
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+	f, err := os.CreateTemp("", "drop-record-*.json")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close() //nolint:errcheck
+		return fmt.Errorf("writing record: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing record file: %w", err)
+	}
+	return di.runInventoryCommand(helper, InventoryCmdPut, f.Name())
+}
+
+// RecordInstall registers a successful installation in the inventory
+// database of its scope, the user or the system one, so it can later be
+// verified, updated or removed.
+func (di *defaultImplementation) RecordInstall(
+	opts *GetOptions, artifact *InstallArtifact, downloadPath string, verification *verificationResult,
+) error {
 	// Hash the verified artifact. For binaries this is the same content
 	// that landed in the binaries directory.
 	digest, err := fileDigest(downloadPath)
@@ -565,62 +961,267 @@ func (di *defaultImplementation) RecordInstall(
 	}
 
 	record := &inventory.Record{
-		Host:     artifact.Asset.GetHost(),
-		Org:      artifact.Asset.GetOrg(),
-		Repo:     artifact.Asset.GetRepo(),
-		Name:     strings.TrimSuffix(artifact.InstallName, exeSuffix),
-		Version:  artifact.Asset.GetVersion(),
-		Kind:     string(artifact.Kind),
-		Asset:    artifact.Asset.GetName(),
-		Digest:   map[string]string{"sha256": digest},
-		Verified: verified,
+		Host:    artifact.Asset.GetHost(),
+		Org:     artifact.Asset.GetOrg(),
+		Repo:    artifact.Asset.GetRepo(),
+		Name:    recordName(artifact),
+		Version: artifact.Asset.GetVersion(),
+		Kind:    string(artifact.Kind),
+		Asset:   artifact.Asset.GetName(),
+		Digest:  map[string]string{"sha256": digest},
+		Scope:   inventoryScope(opts, artifact),
+		Prefix:  opts.Prefix,
+
+		VerificationLevel: string(verification.Level),
+		Signers:           verification.Signers,
+	}
+
+	if ps := verification.PolicySource; ps != nil {
+		record.PolicyRepository = ps.Repository
+		record.PolicyRef = ps.Ref
+		record.PolicyCommit = ps.Commit
+	}
+
+	// Pinned signers are kept until the user accepts the new identity
+	if len(opts.PinnedSigners) > 0 {
+		record.Signers = opts.PinnedSigners
+	}
+
+	if artifact.SBOM != nil {
+		record.SBOM = artifact.SBOM.Name
+		record.SBOMFormat = artifact.SBOM.Format
+		record.SBOMAttested = artifact.SBOM.Attested
+	}
+
+	if artifact.Fallback != nil {
+		record.Fallback = artifact.Fallback.Arch
+		record.Emulated = artifact.Fallback.Emulated
 	}
 
 	switch artifact.Kind {
 	case ArtifactBinary:
 		record.BinPath = filepath.Join(opts.BinDir, artifact.InstallName)
+		record.Files = artifact.Files
 	case ArtifactPackage:
 		record.PackageFormat = artifact.PackageFormat
+		if artifact.PackageMeta != nil {
+			record.PackageName = artifact.PackageMeta.Name
+			record.PackageVersion = artifact.PackageMeta.FullVersion()
+			record.PackageArch = artifact.PackageMeta.Arch
+		}
+		record.Files = artifact.Files
+		record.Dirs = artifact.Dirs
+	case ArtifactAppImage:
+		if len(artifact.Files) > 0 {
+			record.BinPath = artifact.Files[0]
+		}
+		record.Files = artifact.Files
 	}
 
-	inv.Add(record)
-	if err := inv.Save(); err != nil {
-		return fmt.Errorf("saving install inventory: %w", err)
+	if err := di.recordInInventory(&opts.Options, record); err != nil {
+		return fmt.Errorf("updating install inventory: %w", err)
 	}
 	return nil
 }
 
 // installPackage installs the downloaded package using the system's package
-// manager, through sudo when not running as root.
+// manager, through sudo when not running as root. The real package name and
+// version are read from the package to skip reinstalling a version that is
+// already present. When installing into a prefix, the package is unpacked
+// there instead.
 func (di *defaultImplementation) installPackage(
 	opts *GetOptions, artifact *InstallArtifact, path string,
 ) error {
-	sudo := os.Geteuid() != 0
-	argv, err := buildPackageInstallCmd(artifact.PackageFormat, path, sudo, di.runner.LookPath)
+	name := artifact.InstallName
+	md, err := pkgmeta.ReadFile(artifact.PackageFormat, path)
+	if err != nil {
+		logrus.Debugf("unable to read package metadata: %v", err)
+	} else {
+		artifact.PackageMeta = md
+		name = md.Name
+	}
+
+	if opts.Prefix != "" {
+		return di.installPackagePrefix(opts, artifact, name, path)
+	}
+
+	if md != nil && di.packageUpToDate(artifact, md) {
+		opts.Listener.HandleEvent(&Event{
+			Object: EventObjectInstall, Verb: EventVerbSkipped,
+			Data: map[string]string{
+				"reason": fmt.Sprintf("%s %s is already installed", md.Name, md.FullVersion()),
+			},
+		})
+		return nil
+	}
+
+	argv, err := buildPackageInstallCmd(artifact.PackageFormat, path, di.runner.LookPath)
 	if err != nil {
 		return err
 	}
 
+	helper := ""
+	if os.Geteuid() != 0 {
+		if helper, err = di.escalationHelper(&opts.Options); err != nil {
+			return fmt.Errorf("installing packages requires privileges: %w, rerun as root", err)
+		}
+	}
+
+	data := map[string]string{
+		dataKeyKind:       string(ArtifactPackage),
+		"format":          artifact.PackageFormat,
+		dataKeyName:       name,
+		dataKeyEscalation: helper,
+	}
+	if md != nil {
+		data["version"] = md.Version
+	}
 	opts.Listener.HandleEvent(&Event{
-		Object: EventObjectInstall, Verb: EventVerbRunning,
+		Object: EventObjectInstall, Verb: EventVerbRunning, Data: data,
+	})
+
+	run := di.runner.Run
+	if helper != "" {
+		run = func(argv []string) error { return di.runner.RunPrivileged(helper, argv) }
+	}
+	if err := run(argv); err != nil {
+		return fmt.Errorf("installing %s package: %w", artifact.PackageFormat, err)
+	}
+
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectInstall, Verb: EventVerbDone,
 		Data: map[string]string{
 			dataKeyKind: string(ArtifactPackage),
-			"format":    artifact.PackageFormat,
-			dataKeyName: artifact.InstallName,
-			dataKeySudo: strconv.FormatBool(sudo),
+			dataKeyName: name,
 		},
 	})
+	return nil
+}
 
-	if err := di.runner.Run(argv); err != nil {
-		return fmt.Errorf("installing %s package: %w", artifact.PackageFormat, err)
+// installPackagePrefix unpacks the package payload into the user prefix,
+// recording the written files in the artifact. The files of the previous
+// install of the app can be replaced, those the new package does not ship
+// anymore are removed.
+func (di *defaultImplementation) installPackagePrefix(
+	opts *GetOptions, artifact *InstallArtifact, name, path string,
+) error {
+	data := map[string]string{
+		dataKeyKind: string(ArtifactPackage),
+		"format":    artifact.PackageFormat,
+		dataKeyName: name,
+		"prefix":    opts.Prefix,
+	}
+	if artifact.PackageMeta != nil {
+		data["version"] = artifact.PackageMeta.Version
+	}
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectInstall, Verb: EventVerbRunning, Data: data,
+	})
+
+	var owned, dirs []string
+	previous := di.findRecord(artifact.Asset, recordName(artifact), func(r *inventory.Record) bool {
+		return r.Prefix != ""
+	})
+	if previous != nil {
+		owned = previous.Files
+		dirs = slices.Clone(previous.Dirs)
+	}
+
+	files, created, err := unpackPackage(opts.Prefix, artifact.PackageFormat, path, owned)
+	artifact.Files = files
+	artifact.Dirs = append(dirs, created...)
+	if err != nil {
+		if len(files) > 0 {
+			if rerr := di.recordPartialUnpack(opts, artifact, previous); rerr != nil {
+				logrus.Warnf("recording the files of the failed install: %v", rerr)
+			}
+		}
+		return err
+	}
+
+	if err := removeStaleFiles(owned, files); err != nil {
+		logrus.Warnf("removing files of the previous version: %v", err)
 	}
 
 	opts.Listener.HandleEvent(&Event{
 		Object: EventObjectInstall, Verb: EventVerbDone,
 		Data: map[string]string{
 			dataKeyKind: string(ArtifactPackage),
-			dataKeyName: artifact.InstallName,
+			dataKeyName: name,
+			"path":      opts.Prefix,
 		},
 	})
 	return nil
 }
+
+// packageUpToDate returns true if the version of a package is already
+// installed. Formats whose versions cannot be queried (apk) compare against
+// the package version recorded when drop installed it, as long as the
+// package is still present.
+func (di *defaultImplementation) packageUpToDate(artifact *InstallArtifact, md *pkgmeta.Metadata) bool {
+	if _, err := buildPackageVersionQueryCmd(artifact.PackageFormat, md.Name, di.runner.LookPath); err == nil {
+		installed := di.installedPackageVersion(artifact.PackageFormat, md.Name)
+		return installed != "" && installed == md.FullVersion()
+	}
+	if artifact.Asset == nil {
+		return false
+	}
+	record := di.recordedPackage(artifact.Asset, recordName(artifact))
+	return record != nil && record.PackageFormat == artifact.PackageFormat &&
+		record.PackageName == md.Name && record.PackageVersion == md.FullVersion() &&
+		di.packageInstalled(artifact.PackageFormat, md.Name)
+}
+
+// recordPartialUnpack records the files written by a failed unpack, added to
+// those of the previous install, so retrying can replace them and uninstall
+// can remove them. The record has no version: the app shows as broken and
+// drop update installs it again.
+func (di *defaultImplementation) recordPartialUnpack(
+	opts *GetOptions, artifact *InstallArtifact, previous *inventory.Record,
+) error {
+	record := &inventory.Record{
+		Host:          artifact.Asset.GetHost(),
+		Org:           artifact.Asset.GetOrg(),
+		Repo:          artifact.Asset.GetRepo(),
+		Name:          recordName(artifact),
+		Kind:          string(artifact.Kind),
+		Scope:         inventoryScope(opts, artifact),
+		Prefix:        opts.Prefix,
+		PackageFormat: artifact.PackageFormat,
+	}
+	if previous != nil {
+		prev := *previous
+		record = &prev
+		record.Version = ""
+	}
+	for _, f := range artifact.Files {
+		if !slices.Contains(record.Files, f) {
+			record.Files = append(record.Files, f)
+		}
+	}
+	for _, d := range artifact.Dirs {
+		if !slices.Contains(record.Dirs, d) {
+			record.Dirs = append(record.Dirs, d)
+		}
+	}
+	return di.recordInInventory(&opts.Options, record)
+}
+
+// recordName returns the name an artifact is recorded with in the inventory
+func recordName(artifact *InstallArtifact) string {
+	return strings.TrimSuffix(artifact.InstallName, exeSuffix)
+}
+
+// installedPackageVersion returns the version of an installed package or an
+// empty string if it is not installed or the version cannot be queried.
+func (di *defaultImplementation) installedPackageVersion(format, name string) string {
+	argv, err := buildPackageVersionQueryCmd(format, name, di.runner.LookPath)
+	if err != nil {
+		return ""
+	}
+	out, err := di.runner.Output(argv)
+	if err != nil {
+		return ""
+	}
+	return parseInstalledVersion(format, out)
+}
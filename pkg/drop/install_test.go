@@ -17,7 +17,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/pkgmeta"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
 
@@ -27,6 +29,11 @@ type fakeRunner struct {
 	silent    [][]string
 	runErr    error
 	silentErr error
+	output    []byte
+	outputErr error
+
+	// inDir simulates the effects of commands run in a directory
+	inDir func(dir string, argv []string) error
 }
 
 func (f *fakeRunner) Run(argv []string) error {
@@ -34,11 +41,27 @@ func (f *fakeRunner) Run(argv []string) error {
 	return f.runErr
 }
 
+func (f *fakeRunner) RunPrivileged(helper string, argv []string) error {
+	return f.Run(escalatedArgv(helper, argv))
+}
+
 func (f *fakeRunner) RunSilent(argv []string) error {
 	f.silent = append(f.silent, argv)
 	return f.silentErr
 }
 
+func (f *fakeRunner) RunSilentIn(dir string, argv []string) error {
+	f.silent = append(f.silent, argv)
+	if f.inDir != nil {
+		return f.inDir(dir, argv)
+	}
+	return f.silentErr
+}
+
+func (f *fakeRunner) Output([]string) ([]byte, error) {
+	return f.output, f.outputErr
+}
+
 func (f *fakeRunner) LookPath(file string) (string, error) {
 	if f.paths[file] {
 		return "/usr/bin/" + file, nil
@@ -107,7 +130,7 @@ func TestClassifyInstallCandidates(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			cands := classifyInstallCandidates(testInstallable(), tc.os, tc.arch, tc.pkgFormat)
+			cands := classifyInstallCandidates(testInstallable(), tc.os, tc.arch, "", tc.pkgFormat)
 
 			if tc.binaryName == "" {
 				require.Nil(t, cands.Binary)
@@ -137,6 +160,7 @@ func TestDecideArtifact(t *testing.T) {
 	t.Parallel()
 	binary := &InstallArtifact{Kind: ArtifactBinary, InstallName: testAppName}
 	pkg := &InstallArtifact{Kind: ArtifactPackage, PackageFormat: system.PackageRPM, InstallName: testAppName}
+	appImage := &InstallArtifact{Kind: ArtifactAppImage, InstallName: testAppName}
 
 	pickPackage := func(cands []*InstallArtifact) (*InstallArtifact, error) {
 		require.Len(t, cands, 2)
@@ -163,6 +187,10 @@ func TestDecideArtifact(t *testing.T) {
 		{name: "both-already-installed", cands: &installCandidates{Binary: binary, Package: pkg}, installed: true, expect: pkg},
 		{name: "both-selector", cands: &installCandidates{Binary: binary, Package: pkg}, selector: pickPackage, expect: pkg},
 		{name: "both-no-selector-defaults-binary", cands: &installCandidates{Binary: binary, Package: pkg}, expect: binary},
+		{name: "forced-appimage", cands: &installCandidates{Binary: binary, AppImage: appImage}, downloadType: "i", expect: appImage},
+		{name: "forced-appimage-missing", cands: &installCandidates{Binary: binary}, downloadType: "i", expectErr: ErrNoInstallableArtifact},
+		{name: "only-appimage", cands: &installCandidates{AppImage: appImage}, expect: appImage},
+		{name: "appimage-over-package", cands: &installCandidates{AppImage: appImage, Package: pkg}, expect: appImage},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -191,23 +219,27 @@ func TestBuildPackageInstallCmd(t *testing.T) {
 		name      string
 		format    string
 		path      string
-		sudo      bool
 		paths     map[string]bool
 		expect    []string
 		expectErr bool
 	}{
 		{
-			name: "rpm-dnf-sudo", format: system.PackageRPM, path: testRPMPath, sudo: true,
-			paths:  map[string]bool{cmdDnf: true, cmdYum: true, cmdSudo: true},
-			expect: []string{cmdSudo, cmdDnf, verbInstall, "-y", testRPMPath},
+			name: "rpm-dnf", format: system.PackageRPM, path: testRPMPath,
+			paths:  map[string]bool{cmdDnf: true, cmdYum: true},
+			expect: []string{cmdDnf, verbInstall, "-y", testRPMPath},
 		},
 		{
-			name: "rpm-yum-fallback", format: system.PackageRPM, path: testRPMPath, sudo: false,
+			name: "rpm-yum-fallback", format: system.PackageRPM, path: testRPMPath,
 			paths:  map[string]bool{cmdYum: true},
 			expect: []string{cmdYum, verbInstall, "-y", testRPMPath},
 		},
 		{
-			name: "rpm-rpm-fallback", format: system.PackageRPM, path: testRPMPath, sudo: false,
+			name: "rpm-zypper", format: system.PackageRPM, path: testRPMPath,
+			paths:  map[string]bool{cmdZypper: true, cmdRPM: true},
+			expect: []string{cmdZypper, "--non-interactive", verbInstall, "--allow-unsigned-rpm", testRPMPath},
+		},
+		{
+			name: "rpm-rpm-fallback", format: system.PackageRPM, path: testRPMPath,
 			paths:  map[string]bool{system.PackageRPM: true},
 			expect: []string{system.PackageRPM, "-Uvh", testRPMPath},
 		},
@@ -218,23 +250,24 @@ func TestBuildPackageInstallCmd(t *testing.T) {
 		{
 			// deb paths go through filepath.Abs (apt requires a path to
 			// install local files), absolutize the expectation too
-			name: "deb-apt", format: system.PackageDeb, path: testDebPath, sudo: true,
-			paths:  map[string]bool{"apt": true, cmdSudo: true},
-			expect: []string{cmdSudo, "apt", verbInstall, "-y", mustAbs(t, testDebPath)},
+			name: "deb-apt", format: system.PackageDeb, path: testDebPath,
+			paths:  map[string]bool{"apt": true},
+			expect: []string{"apt", verbInstall, "-y", mustAbs(t, testDebPath)},
 		},
 		{
-			name: "deb-dpkg-fallback", format: system.PackageDeb, path: testDebPath, sudo: false,
+			name: "deb-dpkg-fallback", format: system.PackageDeb, path: testDebPath,
 			paths:  map[string]bool{cmdDpkg: true},
 			expect: []string{cmdDpkg, "-i", mustAbs(t, testDebPath)},
 		},
 		{
-			name: system.PackageApk, format: system.PackageApk, path: "/tmp/d/drop.apk", sudo: false,
+			name: system.PackageApk, format: system.PackageApk, path: "/tmp/d/drop.apk",
 			paths:  map[string]bool{system.PackageApk: true},
 			expect: []string{system.PackageApk, "add", "--allow-untrusted", "/tmp/d/drop.apk"},
 		},
 		{
-			name: "sudo-missing", format: system.PackageRPM, path: testRPMPath, sudo: true,
-			paths: map[string]bool{cmdDnf: true}, expectErr: true,
+			name: system.PackagePacman, format: system.PackagePacman, path: "/tmp/d/drop.pkg.tar.zst",
+			paths:  map[string]bool{cmdPacman: true},
+			expect: []string{cmdPacman, "-U", "--noconfirm", "/tmp/d/drop.pkg.tar.zst"},
 		},
 		{
 			name: "unsupported-format", format: "msi", path: "/tmp/d/drop.msi",
@@ -244,7 +277,7 @@ func TestBuildPackageInstallCmd(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			runner := &fakeRunner{paths: tc.paths}
-			argv, err := buildPackageInstallCmd(tc.format, tc.path, tc.sudo, runner.LookPath)
+			argv, err := buildPackageInstallCmd(tc.format, tc.path, runner.LookPath)
 			if tc.expectErr {
 				require.Error(t, err)
 				return
@@ -257,7 +290,7 @@ func TestBuildPackageInstallCmd(t *testing.T) {
 
 func TestBuildPackageQueryCmd(t *testing.T) {
 	t.Parallel()
-	allTools := map[string]bool{system.PackageRPM: true, cmdDpkg: true, system.PackageApk: true}
+	allTools := map[string]bool{system.PackageRPM: true, cmdDpkg: true, system.PackageApk: true, cmdPacman: true}
 	for _, tc := range []struct {
 		name      string
 		format    string
@@ -268,6 +301,11 @@ func TestBuildPackageQueryCmd(t *testing.T) {
 		{name: system.PackageRPM, format: system.PackageRPM, paths: allTools, expect: []string{system.PackageRPM, "-q", testAppName}},
 		{name: system.PackageDeb, format: system.PackageDeb, paths: allTools, expect: []string{cmdDpkg, "-s", testAppName}},
 		{name: system.PackageApk, format: system.PackageApk, paths: allTools, expect: []string{system.PackageApk, "info", "-e", testAppName}},
+		{name: system.PackagePacman, format: system.PackagePacman, paths: allTools, expect: []string{cmdPacman, "-Q", testAppName}},
+		{
+			name: "rpm-zypper", format: system.PackageRPM, paths: map[string]bool{cmdZypper: true},
+			expect: []string{cmdZypper, "--non-interactive", "search", "--installed-only", "--match-exact", testAppName},
+		},
 		{name: "tool-missing", format: system.PackageRPM, paths: map[string]bool{}, expectErr: true},
 		{name: "unsupported", format: "dmg", paths: allTools, expectErr: true},
 	} {
@@ -285,6 +323,112 @@ func TestBuildPackageQueryCmd(t *testing.T) {
 	}
 }
 
+func TestBuildPackageVersionQueryCmd(t *testing.T) {
+	t.Parallel()
+	allTools := map[string]bool{cmdRPM: true, cmdDpkgQuery: true, cmdPacman: true}
+	for _, tc := range []struct {
+		name      string
+		format    string
+		output    string
+		expect    []string
+		version   string
+		expectErr bool
+	}{
+		{
+			name: system.PackageRPM, format: system.PackageRPM, output: "(none):1.0.0-1.el9",
+			expect: []string{cmdRPM, "-q", "--qf", "%{EPOCH}:%{VERSION}-%{RELEASE}", testAppName}, version: "1.0.0-1.el9",
+		},
+		{
+			name: "rpm-epoch", format: system.PackageRPM, output: "2:1.0.0-1.el9",
+			expect: []string{cmdRPM, "-q", "--qf", "%{EPOCH}:%{VERSION}-%{RELEASE}", testAppName}, version: "2:1.0.0-1.el9",
+		},
+		{
+			name: system.PackageDeb, format: system.PackageDeb, output: "ii  1:1.0.0-1\n",
+			expect: []string{cmdDpkgQuery, "-W", "-f=${db:Status-Abbrev} ${Version}", testAppName}, version: "1:1.0.0-1",
+		},
+		{
+			name: "deb-config-files", format: system.PackageDeb, output: "rc  1:1.0.0-1\n",
+			expect: []string{cmdDpkgQuery, "-W", "-f=${db:Status-Abbrev} ${Version}", testAppName}, version: "",
+		},
+		{
+			name: system.PackagePacman, format: system.PackagePacman, output: "drop 1.0.0-1\n",
+			expect: []string{cmdPacman, "-Q", testAppName}, version: "1.0.0-1",
+		},
+		{name: "unsupported", format: system.PackageApk, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runner := &fakeRunner{paths: allTools}
+			argv, err := buildPackageVersionQueryCmd(tc.format, testAppName, runner.LookPath)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, argv)
+			require.Equal(t, tc.version, parseInstalledVersion(tc.format, []byte(tc.output)))
+		})
+	}
+}
+
+func TestBuildPackageRemoveCmd(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		format    string
+		paths     map[string]bool
+		expect    []string
+		expectErr bool
+	}{
+		{
+			name: "rpm-dnf", format: system.PackageRPM,
+			paths:  map[string]bool{cmdDnf: true, cmdZypper: true},
+			expect: []string{cmdDnf, "remove", "-y", testAppName},
+		},
+		{
+			name: "rpm-zypper", format: system.PackageRPM,
+			paths:  map[string]bool{cmdZypper: true, cmdRPM: true},
+			expect: []string{cmdZypper, "--non-interactive", "remove", testAppName},
+		},
+		{
+			name: "rpm-rpm-fallback", format: system.PackageRPM,
+			paths:  map[string]bool{cmdRPM: true},
+			expect: []string{cmdRPM, "-e", testAppName},
+		},
+		{
+			name: "deb-apt", format: system.PackageDeb,
+			paths:  map[string]bool{cmdApt: true},
+			expect: []string{cmdApt, "remove", "-y", testAppName},
+		},
+		{
+			name: system.PackageApk, format: system.PackageApk,
+			paths:  map[string]bool{cmdApk: true},
+			expect: []string{cmdApk, "del", testAppName},
+		},
+		{
+			name: system.PackagePacman, format: system.PackagePacman,
+			paths:  map[string]bool{cmdPacman: true},
+			expect: []string{cmdPacman, "-R", "--noconfirm", testAppName},
+		},
+		{
+			name: "unsupported-format", format: system.PackageMSI,
+			paths: map[string]bool{}, expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runner := &fakeRunner{paths: tc.paths}
+			argv, err := buildPackageRemoveCmd(tc.format, testAppName, runner.LookPath)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, argv)
+		})
+	}
+}
+
 func TestInstallAssetBinary(t *testing.T) {
 	t.Parallel()
 	writeSource := func(t *testing.T) string {
@@ -299,7 +443,7 @@ func TestInstallAssetBinary(t *testing.T) {
 	t.Run("writable-dir", func(t *testing.T) {
 		t.Parallel()
 		binDir := t.TempDir()
-		runner := &fakeRunner{paths: map[string]bool{cmdSudo: true}}
+		runner := &fakeRunner{paths: map[string]bool{EscalationSudo: true}}
 		di := &defaultImplementation{runner: runner}
 		opts := &GetOptions{BinDir: binDir}
 		opts.Listener = &NoopListener{}
@@ -316,6 +460,17 @@ func TestInstallAssetBinary(t *testing.T) {
 		require.Empty(t, runner.run, "no command should run when the dir is writable")
 	})
 
+	t.Run("user-scope-creates-dir", func(t *testing.T) {
+		t.Parallel()
+		binDir := filepath.Join(t.TempDir(), ".local", "bin")
+		di := &defaultImplementation{runner: &fakeRunner{}}
+		opts := &GetOptions{BinDir: binDir, Scope: ScopeUser}
+		opts.Listener = &NoopListener{}
+
+		require.NoError(t, di.InstallAsset(opts, info, artifact, writeSource(t)))
+		require.FileExists(t, filepath.Join(binDir, testAppName))
+	})
+
 	t.Run("non-writable-dir-uses-sudo", func(t *testing.T) {
 		t.Parallel()
 		if runtime.GOOS == system.OSWindows {
@@ -326,7 +481,7 @@ func TestInstallAssetBinary(t *testing.T) {
 		}
 		binDir := filepath.Join(t.TempDir(), "bin")
 		require.NoError(t, os.Mkdir(binDir, 0o555)) //nolint:gosec // intentionally non-writable
-		runner := &fakeRunner{paths: map[string]bool{cmdSudo: true}}
+		runner := &fakeRunner{paths: map[string]bool{EscalationSudo: true}}
 		di := &defaultImplementation{runner: runner}
 		opts := &GetOptions{BinDir: binDir}
 		opts.Listener = &NoopListener{}
@@ -334,7 +489,30 @@ func TestInstallAssetBinary(t *testing.T) {
 		src := writeSource(t)
 		require.NoError(t, di.InstallAsset(opts, info, artifact, src))
 		require.Equal(t, [][]string{
-			{cmdSudo, verbInstall, "-m", "0755", src, filepath.Join(binDir, testAppName)},
+			{EscalationSudo, verbInstall, "-m", "0755", src, filepath.Join(binDir, testAppName)},
+		}, runner.run)
+	})
+
+	t.Run("non-writable-dir-uses-doas", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == system.OSWindows {
+			t.Skip("directory permissions are not enforced on windows")
+		}
+		if os.Geteuid() == 0 {
+			t.Skip("running as root, no dir is non-writable")
+		}
+		binDir := filepath.Join(t.TempDir(), "bin")
+		require.NoError(t, os.Mkdir(binDir, 0o555)) //nolint:gosec // intentionally non-writable
+		runner := &fakeRunner{paths: map[string]bool{EscalationSudo: true, EscalationDoas: true}}
+		di := &defaultImplementation{runner: runner}
+		opts := &GetOptions{BinDir: binDir}
+		opts.Listener = &NoopListener{}
+		opts.Escalation = EscalationDoas
+
+		src := writeSource(t)
+		require.NoError(t, di.InstallAsset(opts, info, artifact, src))
+		require.Equal(t, [][]string{
+			{EscalationDoas, verbInstall, "-m", "0755", src, filepath.Join(binDir, testAppName)},
 		}, runner.run)
 	})
 
@@ -358,9 +536,55 @@ func TestInstallAssetBinary(t *testing.T) {
 	})
 }
 
+// recordingListener keeps the events it receives
+type recordingListener struct {
+	events []*Event
+}
+
+func (l *recordingListener) HandleEvent(event *Event) {
+	l.events = append(l.events, event)
+}
+
+func TestCheckPath(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == system.OSWindows {
+		t.Skip("executable bits are not used on windows")
+	}
+	early, binDir := t.TempDir(), t.TempDir()
+	target := filepath.Join(binDir, testAppName)
+	require.NoError(t, os.WriteFile(target, []byte("new"), 0o755))                            //nolint:gosec
+	require.NoError(t, os.WriteFile(filepath.Join(early, testAppName), []byte("old"), 0o755)) //nolint:gosec
+	sep := string(os.PathListSeparator)
+
+	for _, tc := range []struct {
+		name       string
+		pathEnv    string
+		expectVerb string
+	}{
+		{"in-path", binDir + sep + early, ""},
+		{"missing", early, EventVerbMissing},
+		{"shadowed", early + sep + binDir, EventVerbShadowed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			lstnr := &recordingListener{}
+			opts := &GetOptions{}
+			opts.Listener = lstnr
+			checkPath(opts, target, tc.pathEnv)
+			if tc.expectVerb == "" {
+				require.Empty(t, lstnr.events)
+				return
+			}
+			require.Len(t, lstnr.events, 1)
+			require.Equal(t, EventObjectPath, lstnr.events[0].Object)
+			require.Equal(t, tc.expectVerb, lstnr.events[0].Verb)
+		})
+	}
+}
+
 func TestInstallAssetPackage(t *testing.T) {
 	t.Parallel()
-	runner := &fakeRunner{paths: map[string]bool{cmdDnf: true, cmdSudo: true}}
+	runner := &fakeRunner{paths: map[string]bool{cmdDnf: true, EscalationSudo: true}}
 	di := &defaultImplementation{runner: runner}
 	opts := &GetOptions{}
 	opts.Listener = &NoopListener{}
@@ -373,7 +597,7 @@ func TestInstallAssetPackage(t *testing.T) {
 	if os.Geteuid() == 0 {
 		require.Equal(t, []string{cmdDnf, verbInstall, "-y", testRPMPath}, runner.run[0])
 	} else {
-		require.Equal(t, []string{cmdSudo, cmdDnf, verbInstall, "-y", testRPMPath}, runner.run[0])
+		require.Equal(t, []string{EscalationSudo, cmdDnf, verbInstall, "-y", testRPMPath}, runner.run[0])
 	}
 }
 
@@ -401,6 +625,50 @@ func TestPackageInstalled(t *testing.T) {
 	}
 }
 
+func TestPackageUpToDate(t *testing.T) {
+	t.Parallel()
+	asset := &github.Asset{Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Name: "drop-1.0.0-r0.apk"}
+	invPath := filepath.Join(t.TempDir(), inventory.FileName)
+	inv, err := inventory.OpenFile(invPath)
+	require.NoError(t, err)
+	inv.Add(&inventory.Record{
+		Host: asset.Host, Org: asset.Org, Repo: asset.Repo, Name: testAppName, Version: "v1.0.0",
+		Kind: string(ArtifactPackage), PackageFormat: system.PackageApk,
+		PackageName: "drop-cli", PackageVersion: "1.0.0-r0",
+	})
+	require.NoError(t, inv.Save())
+
+	for _, tc := range []struct {
+		name      string
+		format    string
+		md        *pkgmeta.Metadata
+		output    string
+		silentErr error
+		expect    bool
+	}{
+		{name: "rpm-same", format: system.PackageRPM, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.0-1", Epoch: "2"}, output: "2:1.0.0-1", expect: true},
+		{name: "rpm-other-epoch", format: system.PackageRPM, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.0-1", Epoch: "2"}, output: "(none):1.0.0-1"},
+		{name: "deb-removed", format: system.PackageDeb, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.0-1"}, output: "rc  1.0.0-1"},
+		{name: "apk-recorded", format: system.PackageApk, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.0-r0"}, expect: true},
+		{name: "apk-newer", format: system.PackageApk, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.1-r0"}},
+		{name: "apk-removed", format: system.PackageApk, md: &pkgmeta.Metadata{Name: "drop-cli", Version: "1.0.0-r0"}, silentErr: errors.New("exit 1")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			runner := &fakeRunner{
+				paths:     map[string]bool{cmdRPM: true, cmdDpkgQuery: true, cmdDpkg: true, cmdApk: true},
+				output:    []byte(tc.output),
+				silentErr: tc.silentErr,
+			}
+			di := &defaultImplementation{runner: runner, inventoryPath: invPath}
+			artifact := &InstallArtifact{
+				Kind: ArtifactPackage, Asset: asset, InstallName: testAppName, PackageFormat: tc.format,
+			}
+			require.Equal(t, tc.expect, di.packageUpToDate(artifact, tc.md))
+		})
+	}
+}
+
 func TestDownloadAssetToTmp(t *testing.T) {
 	t.Parallel()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -444,23 +712,57 @@ func TestRecordInstall(t *testing.T) {
 	}
 
 	for _, tc := range []struct {
-		name     string
-		artifact *InstallArtifact
-		verified bool
-		check    func(t *testing.T, r *inventory.Record)
+		name         string
+		artifact     *InstallArtifact
+		verification *verificationResult
+		pinned       []string
+		check        func(t *testing.T, r *inventory.Record)
 	}{
 		{
 			name: "binary",
 			artifact: &InstallArtifact{
 				Kind: ArtifactBinary, Asset: asset, InstallName: testAppName,
 			},
-			verified: true,
+			verification: &verificationResult{
+				Level: integrity.LevelPolicy, Signers: []string{"minisign::0807060504030201"},
+				PolicySource: &policySource{Repository: "https://github.com/carabiner-dev/.ampel", Ref: "v1", Commit: "4b8c2f1"},
+			},
 			check: func(t *testing.T, r *inventory.Record) {
 				t.Helper()
+				require.Equal(t, "https://github.com/carabiner-dev/.ampel", r.PolicyRepository)
+				require.Equal(t, "v1", r.PolicyRef)
+				require.Equal(t, "4b8c2f1", r.PolicyCommit)
 				require.Equal(t, string(ArtifactBinary), r.Kind)
 				require.Equal(t, filepath.Join("/opt/bin", testAppName), r.BinPath)
 				require.Empty(t, r.PackageFormat)
-				require.True(t, r.Verified)
+				require.Equal(t, "policy", r.VerificationLevel)
+				require.Equal(t, []string{"minisign::0807060504030201"}, r.Signers)
+			},
+		},
+		{
+			name: "binary-pinned-signers",
+			artifact: &InstallArtifact{
+				Kind: ArtifactBinary, Asset: asset, InstallName: testAppName,
+			},
+			verification: &verificationResult{Level: integrity.LevelSignature, Signers: []string{"gpg::ABCDEF"}},
+			pinned:       []string{"minisign::0807060504030201"},
+			check: func(t *testing.T, r *inventory.Record) {
+				t.Helper()
+				require.Equal(t, []string{"minisign::0807060504030201"}, r.Signers)
+			},
+		},
+		{
+			name: "binary-sbom",
+			artifact: &InstallArtifact{
+				Kind: ArtifactBinary, Asset: asset, InstallName: testAppName,
+				SBOM: &InstalledSBOM{Name: testBinFile + ".spdx.json", Format: "spdx", Attested: true},
+			},
+			verification: &verificationResult{Level: integrity.LevelChecksum},
+			check: func(t *testing.T, r *inventory.Record) {
+				t.Helper()
+				require.Equal(t, testBinFile+".spdx.json", r.SBOM)
+				require.Equal(t, "spdx", r.SBOMFormat)
+				require.True(t, r.SBOMAttested)
 			},
 		},
 		{
@@ -469,13 +771,31 @@ func TestRecordInstall(t *testing.T) {
 				Kind: ArtifactPackage, PackageFormat: system.PackageRPM,
 				Asset: asset, InstallName: testAppName,
 			},
-			verified: false,
+			verification: &verificationResult{Level: integrity.LevelNone},
 			check: func(t *testing.T, r *inventory.Record) {
 				t.Helper()
 				require.Equal(t, string(ArtifactPackage), r.Kind)
 				require.Equal(t, system.PackageRPM, r.PackageFormat)
 				require.Empty(t, r.BinPath)
-				require.False(t, r.Verified)
+				require.Equal(t, "none", r.VerificationLevel)
+				require.Empty(t, r.Signers)
+			},
+		},
+		{
+			name: "package-metadata",
+			artifact: &InstallArtifact{
+				Kind: ArtifactPackage, PackageFormat: system.PackageRPM,
+				Asset: asset, InstallName: testAppName,
+				PackageMeta: &pkgmeta.Metadata{
+					Format: system.PackageRPM, Name: "drop-cli", Version: "1.0.0-1", Epoch: "2", Arch: "x86_64",
+				},
+			},
+			verification: &verificationResult{Level: integrity.LevelChecksum},
+			check: func(t *testing.T, r *inventory.Record) {
+				t.Helper()
+				require.Equal(t, "drop-cli", r.PackageName)
+				require.Equal(t, "2:1.0.0-1", r.PackageVersion)
+				require.Equal(t, "x86_64", r.PackageArch)
 			},
 		},
 	} {
@@ -486,9 +806,9 @@ func TestRecordInstall(t *testing.T) {
 
 			invPath := filepath.Join(t.TempDir(), "installed.json")
 			di := &defaultImplementation{inventoryPath: invPath}
-			opts := &GetOptions{BinDir: "/opt/bin"}
+			opts := &GetOptions{BinDir: "/opt/bin", PinnedSigners: tc.pinned}
 
-			require.NoError(t, di.RecordInstall(opts, tc.artifact, downloaded, tc.verified))
+			require.NoError(t, di.RecordInstall(opts, tc.artifact, downloaded, tc.verification))
 
 			inv, err := inventory.OpenFile(invPath)
 			require.NoError(t, err)
@@ -504,6 +824,61 @@ func TestRecordInstall(t *testing.T) {
 	}
 }
 
+func TestInventoryScope(t *testing.T) {
+	t.Parallel()
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	userBin := filepath.Join(home, ".local", "bin")
+
+	for _, tc := range []struct {
+		name     string
+		opts     *GetOptions
+		artifact *InstallArtifact
+		expect   string
+	}{
+		{"user-binary", &GetOptions{Scope: ScopeUser, BinDir: userBin}, &InstallArtifact{Kind: ArtifactBinary}, ScopeUser},
+		{"system-binary", &GetOptions{Scope: ScopeSystem, BinDir: system.SystemBinDir}, &InstallArtifact{Kind: ArtifactBinary}, ScopeSystem},
+		{"user-scope-system-dir", &GetOptions{Scope: ScopeUser, BinDir: "/opt/bin"}, &InstallArtifact{Kind: ArtifactBinary}, ScopeSystem},
+		{"package", &GetOptions{Scope: ScopeUser, BinDir: userBin}, &InstallArtifact{Kind: ArtifactPackage}, ScopeSystem},
+		{"prefix-package", &GetOptions{Scope: ScopeUser, Prefix: filepath.Join(home, "apps")}, &InstallArtifact{Kind: ArtifactPackage}, ScopeUser},
+		{
+			"user-appimage", &GetOptions{Scope: ScopeUser, BinDir: "/opt/bin", AppsDir: filepath.Join(home, "Applications")},
+			&InstallArtifact{Kind: ArtifactAppImage}, ScopeUser,
+		},
+		{"system-appimage", &GetOptions{Scope: ScopeUser, AppsDir: "/opt/apps"}, &InstallArtifact{Kind: ArtifactAppImage}, ScopeSystem},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, inventoryScope(tc.opts, tc.artifact))
+		})
+	}
+}
+
+func TestRecordInInventoryEscalated(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("running as root, no dir is non-writable")
+	}
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	invDir := filepath.Join(t.TempDir(), "inventory")
+	require.NoError(t, os.Mkdir(invDir, 0o555)) //nolint:gosec // intentionally non-writable
+
+	runner := &fakeRunner{paths: map[string]bool{EscalationSudo: true}}
+	di := &defaultImplementation{runner: runner, inventoryPath: filepath.Join(invDir, inventory.FileName)}
+	record := &inventory.Record{Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Name: testAppName, Scope: ScopeSystem}
+	require.NoError(t, di.recordInInventory(&Options{}, record))
+
+	// The record is passed to drop running as root in a temporary file
+	require.Len(t, runner.run, 1)
+	require.Equal(t, []string{EscalationSudo, exe, "inventory", InventoryCmdPut}, runner.run[0][:4])
+	require.NoFileExists(t, runner.run[0][4])
+
+	// User inventories are never written with privileges
+	record.Scope = ScopeUser
+	require.Error(t, di.recordInInventory(&Options{}, record))
+}
+
 // TestClassifyCosignStyleRelease locks in that releases shipping multiple
 // binary flavors plus metadata files (sigs, SBOMs, certs) select the
 // canonical binary and install it under the computed installable name.
@@ -522,7 +897,7 @@ func TestClassifyCosignStyleRelease(t *testing.T) {
 		},
 	}
 
-	cands := classifyInstallCandidates(inst, system.OSLinux, system.ArchAMD64, system.PackageRPM)
+	cands := classifyInstallCandidates(inst, system.OSLinux, system.ArchAMD64, "", system.PackageRPM)
 
 	require.NotNil(t, cands.Binary)
 	require.Equal(t, "cosign-linux-amd64", cands.Binary.Asset.GetName(),
@@ -550,3 +925,33 @@ func TestIsMetadataFile(t *testing.T) {
 		require.Equal(t, expect, isMetadataFile(file), file)
 	}
 }
+
+func TestClassifyLeveledVariants(t *testing.T) {
+	t.Parallel()
+	inst := &github.Installable{
+		Name: testAppName,
+		Variants: []*github.Asset{
+			{Name: testBinFile, Os: system.OSLinux, Arch: system.ArchX8664},
+			{Name: "drop-linux-amd64v2", Os: system.OSLinux, Arch: system.ArchX8664, ArchLevel: system.ArchLevelX8664V2},
+			{Name: "drop-linux-amd64v3", Os: system.OSLinux, Arch: system.ArchX8664, ArchLevel: system.ArchLevelX8664V3},
+		},
+	}
+	for _, tc := range []struct {
+		name   string
+		level  string
+		expect string
+	}{
+		{"v4-host-picks-best", system.ArchLevelX8664V4, "drop-linux-amd64v3"},
+		{"v3-host", system.ArchLevelX8664V3, "drop-linux-amd64v3"},
+		{"v2-host", system.ArchLevelX8664V2, "drop-linux-amd64v2"},
+		{"v1-host-baseline", system.ArchLevelX8664V1, testBinFile},
+		{"unknown-level-most-compatible", "", testBinFile},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cands := classifyInstallCandidates(inst, system.OSLinux, system.ArchX8664, tc.level, "")
+			require.NotNil(t, cands.Binary)
+			require.Equal(t, tc.expect, cands.Binary.Asset.GetName())
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
+)
+
+// maxIntegrityFileSize caps the size of the checksum and signature files
+// read from a release.
+const maxIntegrityFileSize = 8 << 20
+
+var ErrVerificationLevel = errors.New("artifact verification is weaker than required")
+
+// verificationResult is the outcome of verifying a downloaded artifact
+type verificationResult struct {
+	Level integrity.Level
+
+	// Signers are the identities that signed the artifact or its
+	// attestations, normalized to be compared across releases.
+	Signers []string
+
+	// PolicySource is the policy repository revision the policies were
+	// read from, when verified against policies from a repository.
+	PolicySource *policySource
+}
+
+// releaseFiles serves the assets of a release to the integrity verifier,
+// downloading them as they are read.
+type releaseFiles struct {
+	di     *defaultImplementation
+	opts   *GetOptions
+	assets map[string]github.AssetDataProvider
+	cache  map[string][]byte
+}
+
+// newReleaseFiles lists the assets in the release of an asset
+func newReleaseFiles(
+	di *defaultImplementation, opts *GetOptions, client *github.Client, asset github.AssetDataProvider,
+) (*releaseFiles, error) {
+	assets, err := client.ListReleaseAssets(asset)
+	if err != nil {
+		return nil, fmt.Errorf("listing release assets: %w", err)
+	}
+	r := &releaseFiles{
+		di: di, opts: opts,
+		assets: map[string]github.AssetDataProvider{},
+		cache:  map[string][]byte{},
+	}
+	for _, a := range assets {
+		r.assets[a.GetName()] = a
+	}
+	return r, nil
+}
+
+// Files returns the names of the release assets
+func (r *releaseFiles) Files() []string {
+	ret := make([]string, 0, len(r.assets))
+	for name := range r.assets {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// ReadFile downloads a release asset, once
+func (r *releaseFiles) ReadFile(name string) ([]byte, error) {
+	if data, ok := r.cache[name]; ok {
+		return data, nil
+	}
+	asset, ok := r.assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release has no asset %s", name)
+	}
+	if asset.GetSize() > maxIntegrityFileSize {
+		return nil, fmt.Errorf("%s is too large to be a checksum or signature file", name)
+	}
+	var buf bytes.Buffer
+	if err := r.di.DownloadAssetToWriter(r.opts, &buf, asset); err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", name, err)
+	}
+	r.cache[name] = buf.Bytes()
+	return r.cache[name], nil
+}
+
+// VerifyIntegrity checks an artifact with the checksums and signatures
+// published in its release, for projects without policies.
+func (di *defaultImplementation) VerifyIntegrity(
+	opts *GetOptions, client *github.Client, asset github.AssetDataProvider, path string,
+) (*integrity.Result, error) {
+	opts.Listener.HandleEvent(
+		&Event{Object: EventObjectVerification, Verb: EventVerbRunning},
+	)
+
+	release, err := newReleaseFiles(di, opts, client, asset)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := integrity.New(
+		integrity.WithRepository(asset.GetRepoURL()),
+		integrity.WithMinisignKeys(opts.MinisignKeys...),
+		integrity.WithGPGKeys(opts.GPGKeys...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating integrity verifier: %w", err)
+	}
+
+	res, err := verifier.Verify(release, asset.GetName(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Listener.HandleEvent(
+		&Event{
+			Object: EventObjectVerification, Verb: EventVerbDone,
+			Data: map[string]string{"level": string(res.Level), "details": res.String()},
+		},
+	)
+	return res, nil
+}
+
+// verifyDownload verifies a downloaded asset against its policies or, when
+// it has none, the checksums and signatures in its release. It returns the
+// verification level achieved and the identities that signed the asset.
+func (dropper *Dropper) verifyDownload(
+	opts *GetOptions, asset github.AssetDataProvider, policies []*papi.PolicySet, path string,
+) (*verificationResult, error) {
+	if opts.SkipVerification {
+		opts.Listener.HandleEvent(
+			&Event{Object: EventObjectVerification, Verb: EventVerbSkipped},
+		)
+		return &verificationResult{Level: integrity.LevelNone}, nil
+	}
+
+	if len(policies) > 0 {
+		ok, results, err := dropper.impl.VerifyAsset(&opts.Options, policies, asset, path)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying asset: %w", err)
+		}
+
+		// Report the results, failed verifications included
+		if err := opts.report(asset.GetName(), results); err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrVerificationFailed
+		}
+		return &verificationResult{Level: integrity.LevelPolicy, Signers: resultSigners(results)}, nil
+	}
+
+	res, err := dropper.impl.VerifyIntegrity(opts, dropper.client, asset, path)
+	if err != nil {
+		if errors.Is(err, integrity.ErrNoMaterial) {
+			return nil, fmt.Errorf("%w and %w", ErrNoPolicyAvailable, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	if !res.Level.AtLeast(opts.MinLevel) {
+		return nil, fmt.Errorf(
+			"%w: verified at level %q (%s) but %q is required",
+			ErrVerificationLevel, res.Level, res, opts.MinLevel,
+		)
+	}
+	return &verificationResult{Level: res.Level, Signers: appendSigner(nil, res.Identity())}, nil
+}
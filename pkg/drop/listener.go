@@ -4,16 +4,25 @@
 package drop
 
 const (
-	EventObjectAsset        = "asset"
-	EventObjectInstall      = "install"
-	EventObjectPolicy       = "policy"
-	EventObjectVerification = "verification"
-
-	EventVerbDone    = "done"
-	EventVerbGet     = "get"
-	EventVerbRunning = "running"
-	EventVerbSaved   = "saved"
-	EventVerbSkipped = "skipped"
+	EventObjectAsset           = "asset"
+	EventObjectExtras          = "extras"
+	EventObjectInstall         = "install"
+	EventObjectPath            = "path"
+	EventObjectPolicy          = "policy"
+	EventObjectSBOM            = "sbom"
+	EventObjectSigners         = "signers"
+	EventObjectVerification    = "verification"
+	EventObjectVulnerabilities = "vulnerabilities"
+
+	EventVerbChanged  = "changed"
+	EventVerbDone     = "done"
+	EventVerbFallback = "fallback"
+	EventVerbGet      = "get"
+	EventVerbMissing  = "missing"
+	EventVerbRunning  = "running"
+	EventVerbSaved    = "saved"
+	EventVerbShadowed = "shadowed"
+	EventVerbSkipped  = "skipped"
 )
 
 type Event struct {
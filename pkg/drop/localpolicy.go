@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/carabiner-dev/policy"
+	papi "github.com/carabiner-dev/policy/api/v1"
+)
+
+// policySetPredicateType is the predicate type of policy set attestations
+const policySetPredicateType = "https://carabiner.dev/ampel/policyset/v0.0.1"
+
+// policyFileExtensions are the files loaded when reading a policy directory
+var policyFileExtensions = []string{".json", ".jsonl", ".hjson"}
+
+// collectPolicyFiles expands the policy paths into the list of files to load.
+// Directories are read without recursing, picking the files with a policy
+// extension in name order.
+func collectPolicyFiles(paths []string) ([]string, error) {
+	ret := []string{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy path: %w", err)
+		}
+		if !info.IsDir() {
+			ret = append(ret, p)
+			continue
+		}
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy directory: %w", err)
+		}
+		found := false
+		for _, e := range entries {
+			if e.IsDir() || !slices.Contains(policyFileExtensions, strings.ToLower(filepath.Ext(e.Name()))) {
+				continue
+			}
+			ret = append(ret, filepath.Join(p, e.Name()))
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no policy files found in %s", p)
+		}
+	}
+	return ret, nil
+}
+
+// loadPolicyFiles parses the policy sets in local files. Files can hold a
+// plain PolicySet, a policy set attestation (bare, in a DSSE envelope or in a
+// sigstore bundle) or one attestation per line in .jsonl files.
+func loadPolicyFiles(paths []string) ([]*papi.PolicySet, error) {
+	files, err := collectPolicyFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := policy.NewParser()
+	ret := []*papi.PolicySet{}
+	for _, path := range files {
+		data, err := os.ReadFile(path) //nolint:gosec // the user points to the policies
+		if err != nil {
+			return nil, fmt.Errorf("reading policy file: %w", err)
+		}
+
+		docs := [][]byte{data}
+		if strings.EqualFold(filepath.Ext(path), ".jsonl") {
+			docs = splitLines(data)
+		}
+		for _, doc := range docs {
+			psData, err := unwrapPolicyData(doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			pset, err := parser.ParsePolicySet(psData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing policy set from %s: %w", path, err)
+			}
+			ret = append(ret, pset)
+		}
+	}
+	return ret, nil
+}
+
+// splitLines returns the non-empty lines of a jsonl file
+func splitLines(data []byte) [][]byte {
+	ret := [][]byte{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			ret = append(ret, slices.Clone(line))
+		}
+	}
+	return ret
+}
+
+// policyDocument captures the fields that tell apart the wrappers a policy
+// set can come in.
+type policyDocument struct {
+	// Sigstore bundle
+	DSSEEnvelope json.RawMessage `json:"dsseEnvelope"`
+
+	// DSSE envelope, the payload is base64 encoded
+	PayloadType string `json:"payloadType"`
+	Payload     []byte `json:"payload"`
+
+	// in-toto statement
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// unwrapPolicyData extracts the policy set from the attestation wrappers.
+// Data that is not a wrapper (a plain PolicySet, or HJSON) is returned as is
+// for the parser to read. Signatures are not checked, local files are
+// trusted as supplied by the user.
+func unwrapPolicyData(data []byte) ([]byte, error) {
+	for range 3 {
+		var doc policyDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return data, nil
+		}
+		switch {
+		case len(doc.DSSEEnvelope) > 0:
+			data = doc.DSSEEnvelope
+		case doc.PayloadType != "":
+			data = doc.Payload
+		case doc.Type != "":
+			if doc.PredicateType != policySetPredicateType {
+				return nil, fmt.Errorf("attestation is not a policy set (predicate type %q)", doc.PredicateType)
+			}
+			return doc.Predicate, nil
+		default:
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("too many nested attestation wrappers")
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicySet = `{"id":"drop-policies","policies":[]}`
+
+func TestCollectPolicyFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.hjson", "bundle.jsonl", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(testPolicySet), 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.json"), 0o700))
+	single := filepath.Join(t.TempDir(), "policy.txt")
+	require.NoError(t, os.WriteFile(single, []byte(testPolicySet), 0o600))
+
+	t.Run("directory-and-file", func(t *testing.T) {
+		t.Parallel()
+		files, err := collectPolicyFiles([]string{dir, single})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dir, "a.hjson"), filepath.Join(dir, "b.json"),
+			filepath.Join(dir, "bundle.jsonl"), single,
+		}, files)
+	})
+	t.Run("empty-directory", func(t *testing.T) {
+		t.Parallel()
+		_, err := collectPolicyFiles([]string{t.TempDir()})
+		require.Error(t, err)
+	})
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+		_, err := collectPolicyFiles([]string{filepath.Join(dir, "missing.json")})
+		require.Error(t, err)
+	})
+}
+
+func TestUnwrapPolicyData(t *testing.T) {
+	t.Parallel()
+	statement := `{"_type":"https://in-toto.io/Statement/v1","predicateType":"` + policySetPredicateType +
+		`","subject":[],"predicate":` + testPolicySet + `}`
+	envelope := `{"payloadType":"application/vnd.in-toto+json","payload":"` +
+		base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[{"sig":"c2ln"}]}`
+
+	for _, tc := range []struct {
+		name      string
+		data      string
+		expect    string
+		raw       bool // compare as text, not JSON
+		expectErr bool
+	}{
+		{name: "plain", data: testPolicySet, expect: testPolicySet},
+		{name: "hjson", data: "{\n  id: drop-policies\n}", expect: "{\n  id: drop-policies\n}", raw: true},
+		{name: "statement", data: statement, expect: testPolicySet},
+		{name: "dsse", data: envelope, expect: testPolicySet},
+		{name: "bundle", data: `{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","dsseEnvelope":` + envelope + `}`, expect: testPolicySet},
+		{
+			name:      "other-predicate",
+			data:      `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`,
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := unwrapPolicyData([]byte(tc.data))
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.raw {
+				require.Equal(t, tc.expect, string(data))
+				return
+			}
+			require.JSONEq(t, tc.expect, string(data))
+		})
+	}
+}
@@ -6,28 +6,104 @@ package drop
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/carabiner-dev/drop/pkg/config"
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
+	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/system"
+	"github.com/carabiner-dev/drop/pkg/vuln"
 )
 
 var defaultOptions = Options{}
 
+// Install scopes: user installs go to the user's home without privileges,
+// system installs go to system directories.
+const (
+	ScopeUser   = inventory.ScopeUser
+	ScopeSystem = inventory.ScopeSystem
+)
+
 // The default platform is normalized to the canonical OS/arch labels so it
 // matches the values parsed from the release asset filenames.
 var defaultGetOptions = GetOptions{
 	DownloadPath:    ".",
 	OS:              system.GetOS(runtime.GOOS),
 	Arch:            system.GetArch(runtime.GOARCH),
+	ArchLevel:       system.GetArchLevel(),
 	TransferTimeOut: 900,
-	BinDir:          "/usr/local/bin",
+	Scope:           DefaultScope(),
+	BinDir:          scopeBinDir(DefaultScope()),
+	MinLevel:        integrity.LevelSignature,
+	MaxSeverity:     vuln.SeverityMedium,
+}
+
+// DefaultScope returns the system scope when running as root and the user
+// scope otherwise.
+func DefaultScope() string {
+	if os.Geteuid() == 0 {
+		return ScopeSystem
+	}
+	return ScopeUser
+}
+
+// scopeBinDir returns the binaries directory of a scope. If the user
+// directory cannot be determined, binaries go to the system directory.
+func scopeBinDir(scope string) string {
+	if scope == ScopeUser {
+		if dir, err := system.UserBinDir(); err == nil {
+			return dir
+		}
+	}
+	return system.SystemBinDir
 }
 
 type Options struct {
 	PolicyRepository string
 	Listener         ProgressListener
+
+	// PolicyMap chooses the policy repository of each artifact when no
+	// PolicyRepository is set. Without a match, the policies are read from
+	// the .ampel repository of the artifact's org.
+	PolicyMap *config.PolicyMap
+
+	// PolicyRef is the branch, tag or commit to read the policy repository
+	// at when the repository setting does not pin one, usually the ref
+	// recorded when the app was installed.
+	PolicyRef string
+
+	// PolicyKeys are the GPG keys trusted to sign the policy repository.
+	// When set, policies are only read from commits signed by them.
+	PolicyKeys openpgp.EntityList
+
+	// PolicyFiles are local policy set files (or directories holding
+	// them) to verify artifacts against.
+	PolicyFiles []string
+
+	// DisablePolicyRepo skips fetching policies from the policy
+	// repository, verifying only against the local policy files.
+	DisablePolicyRepo bool
+
+	// Escalation is the helper used to run commands that need elevated
+	// privileges (sudo, doas, run0 or pkexec). When empty, the first one
+	// found in the system is used.
+	Escalation string
+
+	// MinisignKeys and GPGKeys are the public keys trusted to sign the
+	// releases of projects without policies.
+	MinisignKeys []string
+	GPGKeys      [][]byte
+
+	// VulnDatabase is the local copy of the OSV database to scan apps
+	// with. When empty, the database in drop's data directory is used if
+	// it exists.
+	VulnDatabase string
 }
 
 type GetOptions struct {
@@ -41,6 +117,21 @@ type GetOptions struct {
 	OS   string
 	Arch string
 
+	// ArchLevel is the highest CPU feature level the target machine can
+	// run. When empty, the most compatible variant is preferred.
+	ArchLevel string
+
+	// DisableFallbacks turns off looking for variants of alternative
+	// arches when the platform has no native one.
+	DisableFallbacks bool
+
+	// AllowEmulation enables fallbacks to arches that only run through
+	// an emulation layer (eg amd64 binaries on arm64 macs).
+	AllowEmulation bool
+
+	// FallbackArches overrides the platform's default fallback chain.
+	FallbackArches []string
+
 	// Filename to store the downloaded asset
 	FileName string
 
@@ -57,16 +148,70 @@ type GetOptions struct {
 	// thing for repos.
 	SkipVerification bool
 
-	// DownloadType is "a","b" or "p" and determines which download we do
+	// MinLevel is the weakest verification accepted for artifacts without
+	// policies, verified with the checksums and signatures in their
+	// release instead. Checksums alone are only accepted when set to
+	// integrity.LevelChecksum explicitly.
+	MinLevel integrity.Level
+
+	// PinnedSigners are the identities trusted to sign the artifact, the
+	// ones recorded when the app was first installed. When set, artifacts
+	// signed by other identities are refused.
+	PinnedSigners []string
+
+	// WarnSignerChange installs artifacts signed by identities other than
+	// the pinned ones, warning about the change instead of refusing them.
+	WarnSignerChange bool
+
+	// MaxSeverity is the most serious vulnerability severity accepted in
+	// installed apps and their SBOM components.
+	MaxSeverity vuln.Severity
+
+	// IgnoreVulnerabilities installs apps with vulnerabilities above the
+	// maximum severity, only reporting them.
+	IgnoreVulnerabilities bool
+
+	// DownloadType is "a","b","p" or "i" (AppImage) and determines which
+	// download we do
 	DownloadType string
 
+	// Scope is the install scope, user or system. It determines the
+	// default binaries directory.
+	Scope string
+
 	// BinDir is the directory where binaries are installed by the install
 	// subcommand.
 	BinDir string
 
+	// Prefix is a user directory where packages are unpacked instead of
+	// being installed with the package manager. Binaries go to its bin
+	// subdirectory.
+	Prefix string
+
+	// AppsDir is the directory where AppImages are installed. When empty
+	// they go to ~/Applications, or SystemAppsDir in the system scope.
+	AppsDir string
+
+	// Completions installs the shell completions and man pages of binaries
+	// from the release archive or generated by the binary.
+	Completions bool
+
+	// DesktopIntegration extracts the .desktop file and icon of installed
+	// AppImages into the XDG data directories.
+	DesktopIntegration bool
+
 	// Selector resolves the choice between a binary and a package when a
 	// release offers both for the local system.
 	Selector ArtifactSelector
+
+	// Explainer receives the scored variants when choosing an asset
+	Explainer SelectionExplainer
+
+	// Reporter receives the summary of the artifact verification
+	Reporter VerificationReporter
+
+	// ReportPath is a file to save the full verification results to
+	ReportPath string
 }
 
 type (
@@ -81,15 +226,73 @@ func WithPolicyRepository(repoURL string) FuncOption {
 			d.Options.PolicyRepository = ""
 			return nil
 		}
-		str, err := github.RepoURLFromString(repoURL)
+		str, ref, err := github.RepoRefFromString(repoURL)
 		if err != nil {
 			return err
 		}
+		if ref != "" {
+			str += "@" + ref
+		}
 		d.Options.PolicyRepository = str
 		return nil
 	}
 }
 
+// WithPolicyMap sets the mapping of orgs and repositories to the policy
+// repositories to fetch their policies from.
+func WithPolicyMap(pm *config.PolicyMap) FuncOption {
+	return func(d *Dropper) error {
+		d.Options.PolicyMap = pm
+		return nil
+	}
+}
+
+// WithPolicySigningKeys reads GPG public key files trusted to sign the
+// commits of the policy repositories. Once set, policies are only read from
+// signed commits.
+func WithPolicySigningKeys(paths ...string) FuncOption {
+	return func(d *Dropper) error {
+		for _, p := range paths {
+			data, err := readKeyFile(p)
+			if err != nil {
+				return err
+			}
+			keys, err := integrity.ReadKeyRing(data)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", p, err)
+			}
+			d.Options.PolicyKeys = append(d.Options.PolicyKeys, keys...)
+		}
+		return nil
+	}
+}
+
+// WithPolicyFiles sets local policy files or directories to load policy
+// sets from.
+func WithPolicyFiles(paths ...string) FuncOption {
+	return func(d *Dropper) error {
+		files := make([]string, 0, len(paths))
+		for _, p := range paths {
+			abs, err := expandPath(p)
+			if err != nil {
+				return fmt.Errorf("resolving policy path: %w", err)
+			}
+			files = append(files, abs)
+		}
+		d.Options.PolicyFiles = files
+		return nil
+	}
+}
+
+// WithRepoPolicies enables or disables fetching the policies from the
+// policy repository.
+func WithRepoPolicies(enabled bool) FuncOption {
+	return func(d *Dropper) error {
+		d.Options.DisablePolicyRepo = !enabled
+		return nil
+	}
+}
+
 func WithListener(listener ProgressListener) FuncOption {
 	return func(d *Dropper) error {
 		d.Options.Listener = listener
@@ -97,19 +300,127 @@ func WithListener(listener ProgressListener) FuncOption {
 	}
 }
 
+// WithEscalation sets the privilege escalation helper, an empty string
+// detects it automatically.
+func WithEscalation(helper string) FuncOption {
+	return func(d *Dropper) error {
+		if helper != "" && !validEscalationHelper(helper) {
+			return fmt.Errorf("unsupported privilege escalation helper %q, valid helpers are %v", helper, EscalationHelpers)
+		}
+		d.Options.Escalation = helper
+		return nil
+	}
+}
+
+// WithMinisignKeys reads minisign public key files to trust when verifying
+// the signatures of releases without policies.
+func WithMinisignKeys(paths ...string) FuncOption {
+	return func(d *Dropper) error {
+		for _, p := range paths {
+			data, err := readKeyFile(p)
+			if err != nil {
+				return err
+			}
+			d.Options.MinisignKeys = append(d.Options.MinisignKeys, string(data))
+		}
+		return nil
+	}
+}
+
+// WithGPGKeys reads GPG public key files to trust when verifying the
+// signatures of releases without policies.
+func WithGPGKeys(paths ...string) FuncOption {
+	return func(d *Dropper) error {
+		for _, p := range paths {
+			data, err := readKeyFile(p)
+			if err != nil {
+				return err
+			}
+			d.Options.GPGKeys = append(d.Options.GPGKeys, data)
+		}
+		return nil
+	}
+}
+
+// WithVulnDatabase sets the local OSV database to scan apps with, a
+// directory of OSV records or zip archives, or a single archive.
+func WithVulnDatabase(path string) FuncOption {
+	return func(d *Dropper) error {
+		if path == "" {
+			d.Options.VulnDatabase = ""
+			return nil
+		}
+		abs, err := expandPath(path)
+		if err != nil {
+			return fmt.Errorf("resolving vulnerability database path: %w", err)
+		}
+		d.Options.VulnDatabase = abs
+		return nil
+	}
+}
+
+// readKeyFile reads a public key file
+func readKeyFile(path string) ([]byte, error) {
+	abs, err := expandPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving key path: %w", err)
+	}
+	data, err := os.ReadFile(abs) //nolint:gosec // the user points to the keys
+	if err != nil {
+		return nil, fmt.Errorf("reading public key: %w", err)
+	}
+	return data, nil
+}
+
 // GetOptions
+
+// WithPlatform sets the platform to download from an os/arch[/level] slug.
+// The CPU feature level can also be glued to the arch (linux/amd64v3,
+// linux/armv6). When the slug has no level and targets another arch, the
+// local level no longer applies and the most compatible variant is chosen.
 func WithPlatform(slug string) FuncGetOption {
 	return func(o *GetOptions) error {
-		os, arch, _ := strings.Cut(slug, "/")
+		os, rest, _ := strings.Cut(slug, "/")
+		archLabel, level, hasLevel := strings.Cut(rest, "/")
 		if os = system.GetOS(os); os == "" {
 			return errors.New("invalid OS in platform slug")
 		}
-		if arch = system.GetArch(arch); arch == "" {
+
+		arch := system.GetArch(archLabel)
+		if arch == "" {
+			// The level may be glued to the arch label (amd64v3)
+			for _, a := range []string{system.ArchX8664, system.ArchArm, system.ArchArm64} {
+				if l := system.GetArchLevelFromFilename(a, archLabel); l != "" {
+					arch, level, hasLevel = a, l, true
+					break
+				}
+			}
+		}
+		if arch == "" {
 			return errors.New("invalid arch in platform slug")
 		}
 
+		// Labels like armv6 imply their level
+		if !hasLevel {
+			if l := system.GetArchLevelFromFilename(arch, archLabel); l != "" {
+				level, hasLevel = l, true
+			}
+		}
+
+		switch {
+		case hasLevel:
+			if level = system.NormalizeArchLevel(arch, level); level == "" {
+				return errors.New("invalid CPU level in platform slug")
+			}
+		case arch == o.Arch:
+			level = o.ArchLevel
+		default:
+			level = ""
+		}
+
 		o.OS = os
 		o.Arch = arch
+		o.ArchLevel = level
 		return nil
 	}
 }
@@ -138,6 +449,123 @@ func WithVerifyDownloads(verify bool) FuncGetOption {
 	}
 }
 
+// WithMinVerificationLevel sets the weakest verification accepted for
+// artifacts without policies (checksum, signature, sigstore or policy).
+func WithMinVerificationLevel(level string) FuncGetOption {
+	return func(o *GetOptions) error {
+		l, err := integrity.ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		if l == integrity.LevelNone {
+			return errors.New("a minimum verification level of none disables verification, use WithVerifyDownloads")
+		}
+		o.MinLevel = l
+		return nil
+	}
+}
+
+// WithPolicyRef pins the policy repository to a branch, tag or commit when
+// its setting does not carry a ref.
+func WithPolicyRef(ref string) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.PolicyRef = ref
+		return nil
+	}
+}
+
+// WithPinnedSigners sets the signer identities the artifact must be signed
+// by, usually the ones recorded when the app was installed.
+func WithPinnedSigners(signers ...string) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.PinnedSigners = signers
+		return nil
+	}
+}
+
+// WithSignerChangeWarning only warns when the artifact is signed by
+// identities other than the pinned ones, instead of refusing it.
+func WithSignerChangeWarning(warn bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.WarnSignerChange = warn
+		return nil
+	}
+}
+
+// WithMaxSeverity sets the most serious vulnerability severity accepted in
+// installed apps (low, medium, high or critical).
+func WithMaxSeverity(severity string) FuncGetOption {
+	return func(o *GetOptions) error {
+		sev, err := vuln.ParseSeverity(severity)
+		if err != nil {
+			return err
+		}
+		if sev == vuln.SeverityUnknown {
+			return errors.New("the maximum severity must be low, medium, high or critical")
+		}
+		o.MaxSeverity = sev
+		return nil
+	}
+}
+
+// WithIgnoreVulnerabilities installs apps affected by vulnerabilities above
+// the maximum severity, only reporting them.
+func WithIgnoreVulnerabilities(ignore bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.IgnoreVulnerabilities = ignore
+		return nil
+	}
+}
+
+// WithFallbacks enables or disables choosing variants built for other
+// compatible arches when the platform has no native variant.
+func WithFallbacks(enabled bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.DisableFallbacks = !enabled
+		return nil
+	}
+}
+
+// WithEmulation allows falling back to arches that require emulation
+func WithEmulation(allow bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.AllowEmulation = allow
+		return nil
+	}
+}
+
+// WithFallbackArches sets a custom fallback chain, in order of preference
+func WithFallbackArches(arches ...string) FuncGetOption {
+	return func(o *GetOptions) error {
+		if len(arches) == 0 {
+			o.FallbackArches = nil
+			return nil
+		}
+		chain := make([]string, 0, len(arches))
+		for _, label := range arches {
+			arch := system.GetArch(label)
+			if arch == "" {
+				return fmt.Errorf("invalid fallback arch %q", label)
+			}
+			chain = append(chain, arch)
+		}
+		o.FallbackArches = chain
+		return nil
+	}
+}
+
+// WithScope sets the install scope and the binaries directory to its default
+func WithScope(scope string) FuncGetOption {
+	return func(o *GetOptions) error {
+		if scope != ScopeUser && scope != ScopeSystem {
+			return fmt.Errorf("invalid install scope %q", scope)
+		}
+		o.Scope = scope
+		o.BinDir = scopeBinDir(scope)
+		return nil
+	}
+}
+
 func WithBinDir(dir string) FuncGetOption {
 	return func(o *GetOptions) error {
 		if dir == "" {
@@ -148,6 +576,71 @@ func WithBinDir(dir string) FuncGetOption {
 	}
 }
 
+// WithPrefix sets a directory (such as ~/.local) to unpack packages into
+// without the package manager. It also sets the binaries directory to the
+// bin directory in the prefix.
+func WithPrefix(dir string) FuncGetOption {
+	return func(o *GetOptions) error {
+		if dir == "" {
+			o.Prefix = ""
+			return nil
+		}
+		abs, err := expandPath(dir)
+		if err != nil {
+			return fmt.Errorf("resolving prefix: %w", err)
+		}
+		o.Prefix = abs
+		o.BinDir = filepath.Join(abs, "bin")
+		return nil
+	}
+}
+
+// WithAppsDir sets the directory where AppImages are installed
+func WithAppsDir(dir string) FuncGetOption {
+	return func(o *GetOptions) error {
+		if dir == "" {
+			o.AppsDir = ""
+			return nil
+		}
+		abs, err := expandPath(dir)
+		if err != nil {
+			return fmt.Errorf("resolving applications directory: %w", err)
+		}
+		o.AppsDir = abs
+		return nil
+	}
+}
+
+// WithCompletions enables installing the completions and man pages of
+// installed binaries.
+func WithCompletions(enabled bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.Completions = enabled
+		return nil
+	}
+}
+
+// WithDesktopIntegration enables installing the AppImage desktop entries
+func WithDesktopIntegration(enabled bool) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.DesktopIntegration = enabled
+		return nil
+	}
+}
+
+// expandPath expands a leading ~ to the user's home and makes the path
+// absolute.
+func expandPath(dir string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving home directory: %w", err)
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	return filepath.Abs(dir)
+}
+
 func WithArtifactSelector(fn ArtifactSelector) FuncGetOption {
 	return func(o *GetOptions) error {
 		o.Selector = fn
@@ -155,12 +648,40 @@ func WithArtifactSelector(fn ArtifactSelector) FuncGetOption {
 	}
 }
 
+func WithExplainer(fn SelectionExplainer) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.Explainer = fn
+		return nil
+	}
+}
+
+// WithVerificationReporter sets a function to receive the summary of the
+// artifact verification.
+func WithVerificationReporter(fn VerificationReporter) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.Reporter = fn
+		return nil
+	}
+}
+
+// WithReportPath saves the full verification results as JSON to a file
+func WithReportPath(path string) FuncGetOption {
+	return func(o *GetOptions) error {
+		o.ReportPath = path
+		return nil
+	}
+}
+
 func WithDownloadType(t string) FuncGetOption {
 	return func(o *GetOptions) error {
+		// AppImages take "i" as "a" is for archives
+		if strings.EqualFold(t, VariantAppImage) {
+			t = "i"
+		}
 		if t != "" {
 			t = t[0:1]
 		}
-		if t != "" && t != "a" && t != "b" && t != "p" {
+		if t != "" && t != "a" && t != "b" && t != "p" && t != "i" {
 			return fmt.Errorf("invalid dowload type")
 		}
 		o.DownloadType = t
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/config"
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+func TestWithPlatform(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name        string
+		slug        string
+		expectArch  string
+		expectLevel string
+		mustErr     bool
+	}{
+		{name: "same-arch-keeps-host-level", slug: "linux/amd64", expectArch: system.ArchX8664, expectLevel: system.ArchLevelX8664V3},
+		{name: "slash-level", slug: "linux/amd64/v2", expectArch: system.ArchX8664, expectLevel: system.ArchLevelX8664V2},
+		{name: "glued-level", slug: "linux/amd64v4", expectArch: system.ArchX8664, expectLevel: system.ArchLevelX8664V4},
+		{name: "arm-version-alias", slug: "linux/armv6", expectArch: system.ArchArm, expectLevel: system.ArchLevelArmV6},
+		{name: "arm64-minor", slug: "linux/arm64/v8.2", expectArch: system.ArchArm64, expectLevel: system.ArchLevelArm64V82},
+		{name: "other-arch-drops-level", slug: "linux/arm64", expectArch: system.ArchArm64, expectLevel: ""},
+		{name: "invalid-level", slug: "linux/amd64/v9", mustErr: true},
+		{name: "invalid-arch", slug: "linux/sparc", mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			opts := &GetOptions{OS: system.OSLinux, Arch: system.ArchX8664, ArchLevel: system.ArchLevelX8664V3}
+			err := WithPlatform(tc.slug)(opts)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectArch, opts.Arch)
+			require.Equal(t, tc.expectLevel, opts.ArchLevel)
+		})
+	}
+}
+
+func TestPolicyRepository(t *testing.T) {
+	t.Parallel()
+	asset := &github.Asset{Host: "github.com", Org: "sigstore", Repo: "cosign"}
+	pm := &config.PolicyMap{Repositories: map[string]string{"github.com/sigstore": "https://github.com/example/sigstore"}}
+	for _, tc := range []struct {
+		name   string
+		opts   *Options
+		expect string
+	}{
+		{name: "default", opts: &Options{}, expect: "https://github.com/sigstore/.ampel"},
+		{name: "mapped", opts: &Options{PolicyMap: pm}, expect: "https://github.com/example/sigstore"},
+		{
+			name:   "pinned",
+			opts:   &Options{PolicyRepository: "https://github.com/example/other@v1.0.0"},
+			expect: "https://github.com/example/other@v1.0.0",
+		},
+		{
+			name:   "override",
+			opts:   &Options{PolicyMap: pm, PolicyRepository: "https://github.com/example/other"},
+			expect: "https://github.com/example/other",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, policyRepository(tc.opts, asset))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+)
+
+var ErrPolicyCommitUnsigned = errors.New("policy repository commit is not signed by a trusted key")
+
+// policySource is the policy repository revision the policies of an
+// artifact were read from.
+type policySource struct {
+	// Repository is the URL of the policy repository
+	Repository string
+
+	// Ref is the branch, tag or commit the repository was pinned to, empty
+	// when following the default branch.
+	Ref string
+
+	// Commit is the commit the ref resolved to
+	Commit string
+
+	// Signer is the fingerprint of the key that signed the commit, when
+	// the options require signed policies.
+	Signer string
+}
+
+// String returns the repository and the commit policies were read from
+func (ps *policySource) String() string {
+	return ps.Repository + "@" + ps.Commit
+}
+
+// resolvePolicySource resolves the policy repository of an artifact to the
+// commit to read its policies from. It returns nil when the repository does
+// not exist.
+func resolvePolicySource(opts *Options, client *github.Client, asset github.AssetDataProvider) (*policySource, error) {
+	repoURL, ref, err := github.RepoRefFromString(policyRepository(opts, asset))
+	if err != nil {
+		return nil, fmt.Errorf("parsing policy repository: %w", err)
+	}
+	if ref == "" {
+		ref = opts.PolicyRef
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing policy repository: %w", err)
+	}
+	org, repo, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+
+	commit, err := client.GetCommit(&github.Repository{Host: u.Hostname(), Org: org, Repo: repo}, ref)
+	if err != nil {
+		// A missing ref in an existing repository is an error, a missing
+		// repository means the org publishes no policies.
+		if errors.Is(err, github.ErrNotFound) && ref == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("resolving policy repository revision: %w", err)
+	}
+
+	source := &policySource{Repository: repoURL, Ref: ref, Commit: commit.SHA}
+	if len(opts.PolicyKeys) > 0 {
+		source.Signer, err = checkCommitSignature(opts.PolicyKeys, commit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return source, nil
+}
+
+// checkCommitSignature verifies that a commit is signed by one of the
+// trusted GPG keys, returning the fingerprint of the signing key.
+func checkCommitSignature(keys openpgp.EntityList, commit *github.Commit) (string, error) {
+	if !commit.Signed() {
+		return "", fmt.Errorf("%w: commit %s has no signature", ErrPolicyCommitUnsigned, commit.SHA)
+	}
+	if err := commit.CheckPayload(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPolicyCommitUnsigned, err)
+	}
+	signer, err := openpgp.CheckArmoredDetachedSignature(
+		keys, strings.NewReader(commit.Payload), bytes.NewReader([]byte(commit.Signature)), nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: commit %s: %w", ErrPolicyCommitUnsigned, commit.SHA, err)
+	}
+	return fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint), nil
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // git commit ids are sha1 hashes
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+)
+
+// signedCommit returns a commit signed by an entity, its id computed the
+// way git hashes signed commits.
+func signedCommit(t *testing.T, entity *openpgp.Entity, payload string) *github.Commit {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&buf, entity, strings.NewReader(payload), nil))
+	sig := buf.String()
+
+	headers, message, _ := strings.Cut(payload, "\n\n")
+	object := headers + "\ngpgsig " + strings.ReplaceAll(strings.TrimSuffix(sig, "\n"), "\n", "\n ") + "\n\n" + message
+	h := sha1.New() //nolint:gosec // git commit ids are sha1 hashes
+	fmt.Fprintf(h, "commit %d\x00%s", len(object), object)
+	return &github.Commit{SHA: hex.EncodeToString(h.Sum(nil)), Payload: payload, Signature: sig}
+}
+
+func TestCheckCommitSignature(t *testing.T) {
+	t.Parallel()
+	trusted, err := openpgp.NewEntity("Policy Team", "", "policies@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("Someone", "", "someone@example.com", nil)
+	require.NoError(t, err)
+
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author Policy Team <policies@example.com> 1792358901 +0000\n" +
+		"committer Policy Team <policies@example.com> 1792358901 +0000\n\nUpdate policies\n"
+	signed := signedCommit(t, trusted, payload)
+	tampered := *signed
+	tampered.SHA = strings.Repeat("0", 40)
+
+	for _, tc := range []struct {
+		name    string
+		commit  *github.Commit
+		mustErr bool
+	}{
+		{name: "trusted", commit: signed},
+		{name: "unsigned", commit: &github.Commit{SHA: signed.SHA}, mustErr: true},
+		{name: "untrusted", commit: signedCommit(t, other, payload), mustErr: true},
+		{name: "payload-mismatch", commit: &tampered, mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			signer, err := checkCommitSignature(openpgp.EntityList{trusted}, tc.commit)
+			if tc.mustErr {
+				require.ErrorIs(t, err, ErrPolicyCommitUnsigned)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("%X", trusted.PrimaryKey.Fingerprint), signer)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/drop/pkg/pkgmeta"
+)
+
+// prefixPath maps a path from a package payload to its location in a user
+// prefix: /usr/bin/app and /usr/local/bin/app land in prefix/bin/app, sbin
+// directories are merged into bin and other top directories (/etc, /opt)
+// keep their names under the prefix. Paths cannot escape the prefix.
+func prefixPath(prefix, name string) string {
+	rel := strings.TrimPrefix(path.Clean("/"+name), "/")
+	for _, dir := range []string{"usr/local", "usr"} {
+		if rel == dir {
+			rel = ""
+			break
+		}
+		if strings.HasPrefix(rel, dir+"/") {
+			rel = strings.TrimPrefix(rel, dir+"/")
+			break
+		}
+	}
+	if rel == "sbin" || strings.HasPrefix(rel, "sbin/") {
+		rel = "bin" + strings.TrimPrefix(rel, "sbin")
+	}
+	return filepath.Join(prefix, filepath.FromSlash(rel))
+}
+
+// unpackPackage extracts the payload of a package file into a prefix and
+// returns the list of files it wrote and of the directories it created.
+// Directories that already existed are not listed as they are usually
+// shared with other apps. Existing files are only replaced when they are
+// listed in owned, the files of the app's previous install. When unpacking
+// fails, the files and directories created until then are returned with
+// the error.
+func unpackPackage(prefix, format, pkgPath string, owned []string) (files, dirs []string, err error) {
+	if err := os.MkdirAll(prefix, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("creating prefix: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving prefix: %w", err)
+	}
+
+	mine := map[string]bool{}
+	for _, f := range owned {
+		mine[f] = true
+	}
+
+	files = []string{}
+	err = pkgmeta.WalkPayloadFile(format, pkgPath, func(f *pkgmeta.PayloadFile, r io.Reader) error {
+		dest := prefixPath(prefix, f.Name)
+		if dest == prefix {
+			return nil
+		}
+		if f.Type == pkgmeta.FileDir {
+			created, err := mkdirInPrefix(root, prefix, dest)
+			dirs = append(dirs, created...)
+			return err
+		}
+
+		// Symlinks created by the package (or already in the prefix) could
+		// point the parent directory outside of the prefix.
+		created, err := mkdirInPrefix(root, prefix, filepath.Dir(dest))
+		dirs = append(dirs, created...)
+		if err != nil {
+			return err
+		}
+
+		// Replace existing files instead of writing through them, in case
+		// they are links into other files. Files of other apps are kept.
+		if _, err := os.Lstat(dest); err == nil && !mine[dest] {
+			return fmt.Errorf("%s already exists and was not installed by this app", dest)
+		}
+		if err := os.Remove(dest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("replacing %s: %w", dest, err)
+		}
+
+		switch f.Type {
+		case pkgmeta.FileRegular:
+			if err := writePayloadFile(dest, f.Mode, r); err != nil {
+				return err
+			}
+		case pkgmeta.FileSymlink:
+			target := f.Linkname
+			if path.IsAbs(target) {
+				target = prefixPath(prefix, target)
+			}
+			if err := os.Symlink(target, dest); err != nil {
+				return fmt.Errorf("creating symlink: %w", err)
+			}
+		case pkgmeta.FileHardlink:
+			if err := os.Link(prefixPath(prefix, f.Linkname), dest); err != nil {
+				return fmt.Errorf("creating hardlink: %w", err)
+			}
+		default:
+			return nil
+		}
+		files = append(files, dest)
+		mine[dest] = true
+		return nil
+	})
+	if err != nil {
+		return files, dirs, fmt.Errorf("unpacking %s package: %w", format, err)
+	}
+	return files, dirs, nil
+}
+
+// mkdirInPrefix creates a directory of the prefix one component at a time
+// and returns the directories it created. Each existing component is
+// resolved and checked to be inside the prefix root before anything is
+// created under it, so symlinks cannot make drop create directories
+// elsewhere.
+func mkdirInPrefix(root, prefix, dir string) ([]string, error) {
+	rel, err := filepath.Rel(prefix, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s points outside of the prefix", dir)
+	}
+	if rel == "." {
+		return nil, nil
+	}
+
+	created := []string{}
+	cur := prefix
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		cur = filepath.Join(cur, part)
+		err := os.Mkdir(cur, 0o755)
+		if err == nil {
+			created = append(created, cur)
+			continue
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return created, fmt.Errorf("creating directory: %w", err)
+		}
+		if err := checkInPrefix(root, cur); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+// removeEmptyDirs deletes the directories drop created that are left empty,
+// the deepest first. Directories still holding files are kept.
+func removeEmptyDirs(dirs []string) {
+	dirs = slices.Clone(dirs)
+	slices.SortFunc(dirs, func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+	for _, d := range dirs {
+		if err := os.Remove(d); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logrus.Debugf("keeping directory %s: %v", d, err)
+		}
+	}
+}
+
+// removeStaleFiles deletes the files of a previous install that the new
+// version of the package no longer ships.
+func removeStaleFiles(previous, current []string) error {
+	errs := []error{}
+	for _, f := range previous {
+		if slices.Contains(current, f) {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("removing %s: %w", f, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writePayloadFile writes the contents of a file from the payload
+func writePayloadFile(dest string, mode os.FileMode, r io.Reader) error {
+	if mode == 0 {
+		mode = 0o644
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode) //nolint:gosec // path is mapped into the prefix
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", dest, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close() //nolint:errcheck,gosec
+		return fmt.Errorf("writing %s: %w", dest, err)
+	}
+	return f.Close()
+}
+
+// checkInPrefix verifies that a directory resolves to a location inside the
+// (resolved) prefix root.
+func checkInPrefix(root, dir string) error {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s points outside of the prefix", dir)
+	}
+	return nil
+}
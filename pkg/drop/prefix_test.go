@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+func TestPrefixPath(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		expect string
+	}{
+		{"usr/bin/drop", "/p/bin/drop"},
+		{"usr/local/bin/drop", "/p/bin/drop"},
+		{"usr/sbin/dropd", "/p/bin/dropd"},
+		{"sbin/dropd", "/p/bin/dropd"},
+		{"usr/share/man/man1/drop.1", "/p/share/man/man1/drop.1"},
+		{"usr/lib64/libdrop.so", "/p/lib64/libdrop.so"},
+		{"etc/drop.conf", "/p/etc/drop.conf"},
+		{"opt/drop/bin/drop", "/p/opt/drop/bin/drop"},
+		{"/usr/bin/../../../etc/passwd", "/p/etc/passwd"},
+		{"usr", "/p"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, filepath.FromSlash(tc.expect), prefixPath(filepath.FromSlash("/p"), tc.name))
+		})
+	}
+}
+
+// writeApk writes a minimal apk with a .PKGINFO and the specified entries
+func writeApk(t *testing.T, entries ...*tar.Header) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	info := "pkgname = drop\npkgver = 1.0.0-r0\n"
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: ".PKGINFO", Mode: 0o644, Size: int64(len(info))}))
+	_, err := tw.Write([]byte(info))
+	require.NoError(t, err)
+	for _, hdr := range entries {
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write(bytes.Repeat([]byte("x"), int(hdr.Size)))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	path := filepath.Join(t.TempDir(), "drop.apk")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	return path
+}
+
+func TestUnpackPackage(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		prefix := filepath.Join(t.TempDir(), "local")
+		apk := writeApk(t,
+			&tar.Header{Name: "usr/", Typeflag: tar.TypeDir, Mode: 0o755},
+			&tar.Header{Name: "usr/bin/drop", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+			&tar.Header{Name: "usr/bin/d", Typeflag: tar.TypeSymlink, Linkname: "/usr/bin/drop"},
+			&tar.Header{Name: "usr/share/doc/drop/README", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+		)
+
+		files, dirs, err := unpackPackage(prefix, system.PackageApk, apk, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(prefix, "bin", "drop"),
+			filepath.Join(prefix, "bin", "d"),
+			filepath.Join(prefix, "share", "doc", "drop", "README"),
+		}, files)
+		require.Equal(t, []string{
+			filepath.Join(prefix, "bin"),
+			filepath.Join(prefix, "share"),
+			filepath.Join(prefix, "share", "doc"),
+			filepath.Join(prefix, "share", "doc", "drop"),
+		}, dirs)
+
+		st, err := os.Stat(filepath.Join(prefix, "bin", "drop"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o755), st.Mode().Perm())
+
+		target, err := os.Readlink(filepath.Join(prefix, "bin", "d"))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(prefix, "bin", "drop"), target)
+
+		// Unpacking again replaces the files of the previous install
+		_, dirs, err = unpackPackage(prefix, system.PackageApk, apk, files)
+		require.NoError(t, err)
+		require.Empty(t, dirs)
+	})
+
+	t.Run("foreign-file", func(t *testing.T) {
+		t.Parallel()
+		prefix := filepath.Join(t.TempDir(), "local")
+		require.NoError(t, os.MkdirAll(filepath.Join(prefix, "bin"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(prefix, "bin", "other"), []byte("mine"), 0o600))
+		apk := writeApk(t,
+			&tar.Header{Name: "usr/bin/drop", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+			&tar.Header{Name: "usr/bin/other", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+		)
+
+		// The files written before the failure are returned
+		files, _, err := unpackPackage(prefix, system.PackageApk, apk, nil)
+		require.ErrorContains(t, err, "not installed by this app")
+		require.Equal(t, []string{filepath.Join(prefix, "bin", "drop")}, files)
+		data, err := os.ReadFile(filepath.Join(prefix, "bin", "other"))
+		require.NoError(t, err)
+		require.Equal(t, "mine", string(data))
+	})
+
+	t.Run("symlink-escape", func(t *testing.T) {
+		t.Parallel()
+		// Absolute targets are mapped into the prefix, relative ones can
+		// still point outside of it.
+		outside := t.TempDir()
+		prefix := filepath.Join(t.TempDir(), "local")
+		link, err := filepath.Rel(prefix, outside)
+		require.NoError(t, err)
+		apk := writeApk(t,
+			&tar.Header{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: link},
+			&tar.Header{Name: "etc/evil", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1},
+		)
+		_, _, err = unpackPackage(prefix, system.PackageApk, apk, nil)
+		require.ErrorContains(t, err, "outside of the prefix")
+		require.NoFileExists(t, filepath.Join(outside, "evil"))
+	})
+
+	t.Run("symlinked-parent", func(t *testing.T) {
+		t.Parallel()
+		// No directories are created through a link in the prefix
+		outside := t.TempDir()
+		prefix := filepath.Join(t.TempDir(), "local")
+		require.NoError(t, os.MkdirAll(prefix, 0o755))
+		require.NoError(t, os.Symlink(outside, filepath.Join(prefix, "share")))
+		apk := writeApk(t,
+			&tar.Header{Name: "usr/share/doc/drop/README", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+		)
+		_, _, err := unpackPackage(prefix, system.PackageApk, apk, nil)
+		require.ErrorContains(t, err, "outside of the prefix")
+		require.NoDirExists(t, filepath.Join(outside, "doc"))
+	})
+}
+
+func TestInstallPackagePrefix(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "local")
+	invPath := filepath.Join(dir, inventory.FileName)
+	di := &defaultImplementation{runner: &fakeRunner{}, inventoryPath: invPath}
+	opts := &GetOptions{Prefix: prefix}
+	opts.Listener = &NoopListener{}
+	newArtifact := func() *InstallArtifact {
+		return &InstallArtifact{
+			Kind: ArtifactPackage, PackageFormat: system.PackageApk, InstallName: testAppName,
+			Asset: &github.Asset{Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Version: "v1.0.0"},
+		}
+	}
+	install := func(apk string) (*InstallArtifact, error) {
+		artifact := newArtifact()
+		if err := di.installPackagePrefix(opts, artifact, testAppName, apk); err != nil {
+			return artifact, err
+		}
+		return artifact, di.RecordInstall(opts, artifact, apk, &verificationResult{Level: integrity.LevelNone})
+	}
+	record := func() *inventory.Record {
+		inv, err := inventory.OpenFile(invPath)
+		require.NoError(t, err)
+		return inv.Get((&inventory.Record{Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Name: testAppName}).Key())
+	}
+	bin := filepath.Join(prefix, "bin", "drop")
+	readme := filepath.Join(prefix, "share", "doc", "drop", "README")
+
+	_, err := install(writeApk(t,
+		&tar.Header{Name: "usr/bin/drop", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+		&tar.Header{Name: "usr/share/doc/drop/README", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+	))
+	require.NoError(t, err)
+	require.FileExists(t, readme)
+
+	// The update replaces the binary and removes the file it stopped shipping
+	_, err = install(writeApk(t,
+		&tar.Header{Name: "usr/bin/drop", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+	))
+	require.NoError(t, err)
+	require.FileExists(t, bin)
+	require.NoFileExists(t, readme)
+	require.Equal(t, []string{bin}, record().Files)
+	require.Equal(t, []string{
+		filepath.Join(prefix, "bin"),
+		filepath.Join(prefix, "share"),
+		filepath.Join(prefix, "share", "doc"),
+		filepath.Join(prefix, "share", "doc", "drop"),
+	}, record().Dirs)
+
+	// A failed update keeps the files it wrote in the record
+	other := filepath.Join(prefix, "bin", "other")
+	require.NoError(t, os.WriteFile(other, []byte("mine"), 0o600))
+	_, err = install(writeApk(t,
+		&tar.Header{Name: "usr/bin/drop", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+		&tar.Header{Name: "usr/bin/dropd", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+		&tar.Header{Name: "usr/bin/other", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4},
+	))
+	require.Error(t, err)
+	r := record()
+	require.Empty(t, r.Version)
+	require.Equal(t, []string{bin, filepath.Join(prefix, "bin", "dropd")}, r.Files)
+	require.FileExists(t, other)
+
+	// Uninstalling removes the files and the directories left empty
+	require.NoError(t, di.UninstallApp(&Options{}, r))
+	require.NoFileExists(t, bin)
+	require.NoDirExists(t, filepath.Join(prefix, "share"))
+	require.FileExists(t, other)
+	require.DirExists(t, prefix)
+	require.Nil(t, record())
+}
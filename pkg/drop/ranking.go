@@ -0,0 +1,373 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/pkgmeta"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// Variant kinds as classified by the ranking engine
+const (
+	VariantBinary   = "binary"
+	VariantPackage  = "package"
+	VariantArchive  = "archive"
+	VariantAppImage = "appimage"
+	VariantMetadata = "metadata"
+)
+
+// Score weights. The tiers do not overlap: CPU levels weigh up to 99 points
+// (v9.9) and the distribution version bonus 40, less than the 200 points
+// between two kinds of file. The kinds plus those bonuses stay below an arch
+// step, so an earlier arch in the platform chain always beats a better kind
+// of file and the kind always beats the CPU level and distro version.
+const (
+	scoreArchStep       = 10000
+	scoreBinary         = 1000
+	scoreAppImage       = 800
+	scoreArchive        = 600
+	scoreSystemPackage  = 400
+	scorePackage        = 200
+	scoreDistroVersion  = 40
+	scoreLevelPerWeight = 1
+)
+
+// ScoreReason is a named factor that contributed points to a variant score
+type ScoreReason struct {
+	Name   string
+	Points int
+}
+
+// ScoredVariant is a release variant ranked by the selection engine
+type ScoredVariant struct {
+	Asset *github.Asset
+	Kind  string
+	Score int
+
+	// Reasons lists the factors that add up to the score
+	Reasons []ScoreReason
+
+	// Rejected explains why the variant cannot be chosen, empty when it
+	// is eligible.
+	Rejected string
+
+	// Chosen marks the variant picked by the installer
+	Chosen bool
+
+	// Fallback is set when the variant was built for an arch from the
+	// platform's fallback chain.
+	Fallback *system.ArchFallback
+
+	// packageType is the package format when the variant is a package
+	packageType string
+
+	// platformMatch is true when the variant runs on the target platform,
+	// regardless of whether its kind can be used.
+	platformMatch bool
+}
+
+// Eligible returns true if the variant can be chosen
+func (sv *ScoredVariant) Eligible() bool {
+	return sv.Rejected == ""
+}
+
+// Ranking is the outcome of scoring all the variants of an installable
+type Ranking struct {
+	Installable string
+	Platform    string
+	Variants    []*ScoredVariant
+}
+
+// SelectionExplainer receives the ranking computed when choosing an asset
+// so the CLI can show why a file was chosen.
+type SelectionExplainer func(*Ranking)
+
+// rankOptions control how the engine scores the variants
+type rankOptions struct {
+	OS      string
+	Targets []archTarget
+
+	// PackageFormat is the system package format. When installing it is
+	// the only format allowed, when downloading it is preferred.
+	PackageFormat string
+
+	// DistroTags are the tags of packages built for the local distribution
+	// version (el9, fc40...), packages carrying them are preferred.
+	DistroTags []string
+
+	// DownloadType restricts the variants to a kind (b, p, i or a)
+	DownloadType string
+
+	// Install rejects the variants that cannot be installed (archives
+	// and packages in foreign formats).
+	Install bool
+
+	// Unpack accepts packages in any format drop can unpack when
+	// installing, as their contents are extracted into a prefix instead
+	// of going through the package manager.
+	Unpack bool
+}
+
+// variantKind classifies a release file by its name
+func variantKind(name string) (kind, packageType string) {
+	// Packages go first, some (pacman) are compressed tarballs
+	if t := system.PackageExtensions.GetTypeFromFile(name); t != "" {
+		return VariantPackage, t
+	}
+	if system.ArchiveExtensions.GetTypeFromFile(name) != "" {
+		return VariantArchive, ""
+	}
+	if system.IsAppImage(name) {
+		return VariantAppImage, ""
+	}
+	if isMetadataFile(name) {
+		return VariantMetadata, ""
+	}
+	return VariantBinary, ""
+}
+
+// rankVariants scores all the variants of an installable and returns them
+// sorted from best to worst. Rejected variants sort last.
+func rankVariants(inst *github.Installable, ro *rankOptions) *Ranking {
+	ranking := &Ranking{
+		Installable: inst.GetName(),
+		Variants:    make([]*ScoredVariant, 0, len(inst.Variants)),
+	}
+	if len(ro.Targets) > 0 {
+		ranking.Platform = ro.OS + "/" + ro.Targets[0].Arch
+		if ro.Targets[0].Level != "" {
+			ranking.Platform += "/" + ro.Targets[0].Level
+		}
+	}
+
+	for _, v := range inst.Variants {
+		ranking.Variants = append(ranking.Variants, scoreVariant(v, ro))
+	}
+
+	slices.SortStableFunc(ranking.Variants, func(a, b *ScoredVariant) int {
+		if a.Eligible() != b.Eligible() {
+			if a.Eligible() {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		// Ties go to the canonical (shortest) filename, eg cosign-linux-amd64
+		// over cosign-linux-pivkey-amd64, then to the first name so the
+		// choice does not depend on the order of the release assets.
+		return cmp.Or(
+			cmp.Compare(len(a.Asset.GetName()), len(b.Asset.GetName())),
+			strings.Compare(a.Asset.GetName(), b.Asset.GetName()),
+		)
+	})
+
+	// Record the tie breaks so they show up when explaining the choice
+	for i := 1; i < len(ranking.Variants); i++ {
+		prev, sv := ranking.Variants[i-1], ranking.Variants[i]
+		if !sv.Eligible() || prev.Score != sv.Score {
+			continue
+		}
+		if len(prev.Asset.GetName()) < len(sv.Asset.GetName()) {
+			sv.addReason("tied, "+prev.Asset.GetName()+" has a shorter name", 0)
+		} else {
+			sv.addReason("tied, "+prev.Asset.GetName()+" sorts first by name", 0)
+		}
+	}
+	return ranking
+}
+
+// scoreVariant computes the score of a single variant
+func scoreVariant(v *github.Asset, ro *rankOptions) *ScoredVariant {
+	kind, packageType := variantKind(v.GetName())
+	sv := &ScoredVariant{Asset: v, Kind: kind, packageType: packageType}
+
+	if v.Os != ro.OS {
+		sv.Rejected = fmt.Sprintf("built for another OS (%s)", v.Os)
+		return sv
+	}
+
+	ti := slices.IndexFunc(ro.Targets, func(t archTarget) bool { return t.Arch == v.Arch })
+	if ti == -1 {
+		sv.Rejected = fmt.Sprintf("arch %q does not run on this platform", v.Arch)
+		return sv
+	}
+	target := ro.Targets[ti]
+	sv.Fallback = target.Fallback
+
+	if !system.ArchLevelSupported(v.ArchLevel, target.Level) {
+		sv.Rejected = fmt.Sprintf("requires CPU level %s, machine supports %s", v.ArchLevel, target.Level)
+		return sv
+	}
+	sv.platformMatch = true
+
+	switch {
+	case kind == VariantMetadata:
+		sv.Rejected = "signature, SBOM or other metadata file"
+	case ro.DownloadType == "b" && kind != VariantBinary:
+		sv.Rejected = "not a binary (type forced)"
+	case ro.DownloadType == "p" && kind != VariantPackage:
+		sv.Rejected = "not a package (type forced)"
+	case ro.DownloadType == "a" && kind != VariantArchive:
+		sv.Rejected = "not an archive (type forced)"
+	case ro.DownloadType == "i" && kind != VariantAppImage:
+		sv.Rejected = "not an AppImage (type forced)"
+	case ro.Install && kind == VariantArchive:
+		sv.Rejected = "archives cannot be installed"
+	case ro.Install && kind == VariantPackage && ro.Unpack && !slices.Contains(pkgmeta.Formats, packageType):
+		sv.Rejected = fmt.Sprintf("%s packages cannot be unpacked", packageType)
+	case ro.Install && kind == VariantPackage && !ro.Unpack && (ro.PackageFormat == "" || packageType != ro.PackageFormat):
+		sv.Rejected = fmt.Sprintf("%s packages are not supported on this system", packageType)
+	}
+	if sv.Rejected != "" {
+		return sv
+	}
+
+	// Arch: the requested arch, then the fallbacks in chain order
+	archReason := "native arch " + v.Arch
+	if target.Fallback != nil {
+		archReason = "fallback arch " + v.Arch
+		if target.Fallback.Emulated {
+			archReason = "emulated arch " + v.Arch
+		}
+	}
+	sv.addReason(archReason, (len(ro.Targets)-ti)*scoreArchStep)
+
+	// Kind: binaries, then AppImages, archives and packages
+	switch kind {
+	case VariantBinary:
+		sv.addReason("binary", scoreBinary)
+	case VariantAppImage:
+		sv.addReason("AppImage", scoreAppImage)
+	case VariantArchive:
+		sv.addReason("archive", scoreArchive)
+	case VariantPackage:
+		if ro.PackageFormat != "" && packageType == ro.PackageFormat {
+			sv.addReason("system package format ("+packageType+")", scoreSystemPackage)
+		} else {
+			sv.addReason(packageType+" package", scorePackage)
+		}
+		if tag := system.GetDistroTagFromFilename(v.GetName()); tag != "" && slices.Contains(ro.DistroTags, tag) {
+			sv.addReason("built for the system version ("+tag+")", scoreDistroVersion)
+		}
+	}
+
+	// CPU level: the best level the machine can run or, when the machine
+	// level is unknown, the most compatible one.
+	if weight := system.ArchLevelWeight(v.ArchLevel); weight > 0 {
+		if target.Level == "" {
+			sv.addReason("CPU level "+v.ArchLevel+" (machine level unknown)", -weight*scoreLevelPerWeight)
+		} else {
+			sv.addReason("CPU level "+v.ArchLevel, weight*scoreLevelPerWeight)
+		}
+	}
+	return sv
+}
+
+func (sv *ScoredVariant) addReason(name string, points int) {
+	sv.Reasons = append(sv.Reasons, ScoreReason{Name: name, Points: points})
+	sv.Score += points
+}
+
+// Best returns the top ranked eligible variant of a kind (any kind when
+// empty) or nil if there is none.
+func (r *Ranking) Best(kind string) *ScoredVariant {
+	for _, sv := range r.Variants {
+		if !sv.Eligible() {
+			break
+		}
+		if kind == "" || sv.Kind == kind {
+			return sv
+		}
+	}
+	return nil
+}
+
+// markChosen flags the variant of an asset as the chosen one
+func (r *Ranking) markChosen(asset *github.Asset) {
+	for _, sv := range r.Variants {
+		sv.Chosen = sv.Asset == asset
+	}
+}
+
+// hasPlatformKind returns true if any variant running on the platform is of
+// the specified kind, even if it was rejected.
+func (r *Ranking) hasPlatformKind(kind string) bool {
+	return slices.ContainsFunc(r.Variants, func(sv *ScoredVariant) bool {
+		return sv.platformMatch && sv.Kind == kind
+	})
+}
+
+// installCandidates builds the binary and package install candidates from
+// the ranking. Both come from the same arch: the one of the best variant.
+func (r *Ranking) installCandidates(inst *github.Installable, pkgFormat string) *installCandidates {
+	cands := &installCandidates{
+		HasArchives: r.hasPlatformKind(VariantArchive),
+		HasOtherPkg: slices.ContainsFunc(r.Variants, func(sv *ScoredVariant) bool {
+			return sv.platformMatch && sv.Kind == VariantPackage && (pkgFormat == "" || sv.packageType != pkgFormat)
+		}),
+	}
+
+	top := r.Best("")
+	if top == nil {
+		return cands
+	}
+
+	for _, sv := range r.Variants {
+		if !sv.Eligible() {
+			break
+		}
+		if sv.Asset.Arch != top.Asset.Arch {
+			continue
+		}
+		switch {
+		case sv.Kind == VariantBinary && cands.Binary == nil:
+			name := inst.GetName()
+			if sv.Asset.Os == system.OSWindows {
+				name += exeSuffix
+			}
+			cands.Binary = &InstallArtifact{
+				Kind: ArtifactBinary, Asset: sv.Asset, InstallName: name,
+				Fallback: sv.Fallback,
+			}
+		case sv.Kind == VariantAppImage && cands.AppImage == nil:
+			cands.AppImage = &InstallArtifact{
+				Kind: ArtifactAppImage, Asset: sv.Asset, InstallName: inst.GetName(),
+				Fallback: sv.Fallback,
+			}
+		case sv.Kind == VariantPackage && cands.Package == nil:
+			cands.Package = &InstallArtifact{
+				Kind: ArtifactPackage, PackageFormat: sv.packageType,
+				Asset: sv.Asset, InstallName: inst.GetName(),
+				Fallback: sv.Fallback,
+			}
+		}
+	}
+	return cands
+}
+
+// archiveFor returns the best archive built for an arch, used to look for the
+// files (completions, man pages) that accompany a binary.
+func (r *Ranking) archiveFor(arch string) *github.Asset {
+	for _, sv := range r.Variants {
+		if sv.platformMatch && sv.Kind == VariantArchive && sv.Asset.Arch == arch {
+			return sv.Asset
+		}
+	}
+	return nil
+}
+
+// explain hands the ranking to the configured explainer, if any
+func (o *GetOptions) explain(r *Ranking) {
+	if o.Explainer != nil {
+		o.Explainer(r)
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+func TestRankVariants(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		opts   *rankOptions
+		inst   *github.Installable
+		expect string // expected top variant, "" = none eligible
+	}{
+		{
+			name: "get-prefers-binary",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+				PackageFormat: system.PackageRPM,
+			},
+			inst: testInstallable(), expect: testBinFile,
+		},
+		{
+			name: "get-forced-archive",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+				PackageFormat: system.PackageRPM, DownloadType: "a",
+			},
+			inst: testInstallable(), expect: "drop-linux-amd64.tar.gz",
+		},
+		{
+			name: "get-forced-package-system-format",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+				PackageFormat: system.PackageDeb, DownloadType: "p",
+			},
+			inst: testInstallable(), expect: "drop_1.0.0_amd64.deb",
+		},
+		{
+			name: "get-archive-over-package",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+				PackageFormat: system.PackageRPM,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: testRPMFile, Os: system.OSLinux, Arch: system.ArchAMD64},
+				{Name: "drop-linux-amd64.tar.gz", Os: system.OSLinux, Arch: system.ArchAMD64},
+			}},
+			expect: "drop-linux-amd64.tar.gz",
+		},
+		{
+			name: "install-rejects-archives",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+				Install: true,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: "drop-linux-amd64.tar.gz", Os: system.OSLinux, Arch: system.ArchAMD64},
+				{Name: "drop-linux-amd64.sig", Os: system.OSLinux, Arch: system.ArchAMD64},
+			}},
+		},
+		{
+			name: "package-for-distro-version",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchX8664}},
+				PackageFormat: system.PackageRPM, DistroTags: []string{"el9"}, Install: true,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: testRPMFile, Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: "drop-1.0.0-1.el8.x86_64.rpm", Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: "drop-1.0.0-1.el9.x86_64.rpm", Os: system.OSLinux, Arch: system.ArchX8664},
+			}},
+			expect: "drop-1.0.0-1.el9.x86_64.rpm",
+		},
+		{
+			name: "pacman-package-not-archive",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchX8664}},
+				PackageFormat: system.PackagePacman, Install: true,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: "drop-linux-x86_64.tar.xz", Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: "drop-1.0.0-1-x86_64.pkg.tar.zst", Os: system.OSLinux, Arch: system.ArchX8664},
+			}},
+			expect: "drop-1.0.0-1-x86_64.pkg.tar.zst",
+		},
+		{
+			name: "native-package-over-fallback-binary",
+			opts: &rankOptions{
+				OS: system.OSLinux, PackageFormat: system.PackageRPM, Install: true,
+				Targets: []archTarget{
+					{Arch: system.ArchX8664},
+					{Arch: system.Arch386, Fallback: &system.ArchFallback{Arch: system.Arch386}},
+				},
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: "drop-linux-386", Os: system.OSLinux, Arch: system.Arch386},
+				{Name: testRPMFile, Os: system.OSLinux, Arch: system.ArchX8664},
+			}},
+			expect: testRPMFile,
+		},
+		{
+			name: "appimage-over-package",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchX8664}},
+				PackageFormat: system.PackageRPM, Install: true,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: testRPMFile, Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: "Drop-1.0.0-x86_64.AppImage", Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: "Drop-1.0.0-x86_64.AppImage.zsync", Os: system.OSLinux, Arch: system.ArchX8664},
+			}},
+			expect: "Drop-1.0.0-x86_64.AppImage",
+		},
+		{
+			name: "get-archive-over-leveled-package",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchArm64, Level: "v9.0"}},
+				PackageFormat: system.PackageDeb, DistroTags: []string{"el9"},
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: "tool-arm64-v8.2.deb", Os: system.OSLinux, Arch: system.ArchArm64, ArchLevel: "v8.2"},
+				{Name: "tool-arm64-v8.2.el9.rpm", Os: system.OSLinux, Arch: system.ArchArm64, ArchLevel: "v8.2"},
+				{Name: "tool-arm64.tar.gz", Os: system.OSLinux, Arch: system.ArchArm64},
+			}},
+			expect: "tool-arm64.tar.gz",
+		},
+		{
+			name: "unpack-foreign-package",
+			opts: &rankOptions{
+				OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchX8664}},
+				PackageFormat: system.PackageDeb, Install: true, Unpack: true,
+			},
+			inst: &github.Installable{Name: testAppName, Variants: []*github.Asset{
+				{Name: "drop-linux-x86_64.tar.gz", Os: system.OSLinux, Arch: system.ArchX8664},
+				{Name: testRPMFile, Os: system.OSLinux, Arch: system.ArchX8664},
+			}},
+			expect: testRPMFile,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ranking := rankVariants(tc.inst, tc.opts)
+			require.Len(t, ranking.Variants, len(tc.inst.Variants))
+			best := ranking.Best("")
+			if tc.expect == "" {
+				require.Nil(t, best)
+				for _, sv := range ranking.Variants {
+					require.NotEmpty(t, sv.Rejected)
+				}
+				return
+			}
+			require.NotNil(t, best)
+			require.Equal(t, tc.expect, best.Asset.GetName())
+			require.NotEmpty(t, best.Reasons)
+		})
+	}
+}
+
+func TestRankVariantsExplains(t *testing.T) {
+	t.Parallel()
+	inst := &github.Installable{
+		Name: "cosign",
+		Variants: []*github.Asset{
+			{Name: "cosign-linux-pivkey-amd64", Os: system.OSLinux, Arch: system.ArchAMD64},
+			{Name: "cosign-linux-amd64", Os: system.OSLinux, Arch: system.ArchAMD64},
+			{Name: "cosign-linux-arm64", Os: system.OSLinux, Arch: system.ArchArm64},
+		},
+	}
+	ranking := rankVariants(inst, &rankOptions{
+		OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+	})
+
+	require.Equal(t, "cosign-linux-amd64", ranking.Variants[0].Asset.GetName())
+	require.Equal(t, "cosign-linux-pivkey-amd64", ranking.Variants[1].Asset.GetName())
+	require.Equal(t, ranking.Variants[0].Score, ranking.Variants[1].Score)
+	require.Contains(t, ranking.Variants[1].Reasons[len(ranking.Variants[1].Reasons)-1].Name, "shorter name")
+
+	// The arm64 build sorts last with the reason it was discarded
+	require.False(t, ranking.Variants[2].Eligible())
+	require.Contains(t, ranking.Variants[2].Rejected, system.ArchArm64)
+
+	ranking.markChosen(ranking.Variants[0].Asset)
+	require.True(t, ranking.Variants[0].Chosen)
+	require.False(t, ranking.Variants[1].Chosen)
+}
+
+func TestRankVariantsEqualNames(t *testing.T) {
+	t.Parallel()
+	// Names of the same length are ordered by name, whatever the order of
+	// the release assets
+	for _, order := range [][]string{{"tool-linux-amd64", "tool_linux_amd64"}, {"tool_linux_amd64", "tool-linux-amd64"}} {
+		inst := &github.Installable{Name: "tool"}
+		for _, name := range order {
+			inst.Variants = append(inst.Variants, &github.Asset{Name: name, Os: system.OSLinux, Arch: system.ArchAMD64})
+		}
+		ranking := rankVariants(inst, &rankOptions{
+			OS: system.OSLinux, Targets: []archTarget{{Arch: system.ArchAMD64}},
+		})
+		require.Equal(t, "tool-linux-amd64", ranking.Variants[0].Asset.GetName())
+		require.Equal(t, ranking.Variants[0].Score, ranking.Variants[1].Score)
+		reasons := ranking.Variants[1].Reasons
+		require.Contains(t, reasons[len(reasons)-1].Name, "sorts first by name")
+	}
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// VerificationReport summarizes the results of verifying an artifact
+// against its policies.
+type VerificationReport struct {
+	// Asset is the name of the verified file
+	Asset string
+
+	// Passed is true when all the policies passed
+	Passed bool
+
+	Policies []*PolicyReport
+}
+
+// PolicyReport is the outcome of evaluating a single policy
+type PolicyReport struct {
+	ID     string
+	Status string
+	Tenets []*TenetReport
+}
+
+// TenetReport is the outcome of evaluating a policy tenet
+type TenetReport struct {
+	ID     string
+	Status string
+
+	// Error and Guidance explain why the tenet failed
+	Error    string
+	Guidance string
+
+	// Attestations lists the attestations the tenet was evaluated on
+	Attestations []string
+
+	// Signers lists the identities that signed those attestations
+	Signers []string
+}
+
+// VerificationReporter receives the report of each verification so the CLI
+// can show what was checked.
+type VerificationReporter func(*VerificationReport)
+
+// NewVerificationReport builds the report of a verification result set
+func NewVerificationReport(asset string, rs *papi.ResultSet) *VerificationReport {
+	report := &VerificationReport{Asset: asset, Passed: true}
+	for _, result := range rs.GetResults() {
+		if result.GetStatus() != papi.StatusPASS {
+			report.Passed = false
+		}
+		pr := &PolicyReport{
+			ID:     result.GetPolicy().GetId(),
+			Status: result.GetStatus(),
+		}
+		for _, er := range result.GetEvalResults() {
+			tr := &TenetReport{
+				ID:       er.GetId(),
+				Status:   er.GetStatus(),
+				Error:    er.GetError().GetMessage(),
+				Guidance: er.GetError().GetGuidance(),
+			}
+			for _, st := range er.GetStatements() {
+				if label := attestationLabel(st); label != "" && !slices.Contains(tr.Attestations, label) {
+					tr.Attestations = append(tr.Attestations, label)
+				}
+				for _, id := range st.GetIdentities() {
+					if slug := id.Slug(); slug != "" && !slices.Contains(tr.Signers, slug) {
+						tr.Signers = append(tr.Signers, slug)
+					}
+				}
+			}
+			pr.Tenets = append(pr.Tenets, tr)
+		}
+		report.Policies = append(report.Policies, pr)
+	}
+	return report
+}
+
+// attestationLabel names an attestation by its predicate type and, when
+// known, its file name or first digest.
+func attestationLabel(st *papi.StatementRef) string {
+	ref := descriptorLabel(st.GetAttestation())
+	switch {
+	case st.GetType() == "":
+		return ref
+	case ref == "":
+		return st.GetType()
+	default:
+		return st.GetType() + " (" + ref + ")"
+	}
+}
+
+// descriptorLabel returns the name, URI or first digest of a descriptor
+func descriptorLabel(rd *intoto.ResourceDescriptor) string {
+	switch {
+	case rd.GetName() != "":
+		return rd.GetName()
+	case rd.GetUri() != "":
+		return rd.GetUri()
+	}
+	digests := rd.GetDigest()
+	if algos := slices.Sorted(maps.Keys(digests)); len(algos) > 0 {
+		return algos[0] + ":" + digests[algos[0]]
+	}
+	return ""
+}
+
+// writeResultSet saves the full verification results as JSON
+func writeResultSet(path string, rs *papi.ResultSet) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(rs)
+	if err != nil {
+		return fmt.Errorf("marshaling verification results: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { //nolint:gosec // reports are meant to be shared
+		return fmt.Errorf("writing verification report: %w", err)
+	}
+	return nil
+}
+
+// report hands the verification results to the reporter and saves them to
+// the report file, when configured.
+func (o *GetOptions) report(asset string, rs *papi.ResultSet) error {
+	if rs == nil {
+		return nil
+	}
+	if o.Reporter != nil {
+		o.Reporter(NewVerificationReport(asset, rs))
+	}
+	if o.ReportPath != "" {
+		return writeResultSet(o.ReportPath, rs)
+	}
+	return nil
+}
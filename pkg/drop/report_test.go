@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"testing"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVerificationReport(t *testing.T) {
+	t.Parallel()
+	signer := &papi.Identity{Sigstore: &papi.IdentitySigstore{
+		Issuer:   "https://token.actions.githubusercontent.com",
+		Identity: "https://github.com/org/repo/.github/workflows/release.yaml@refs/tags/v1.0.0",
+	}}
+	provenance := &papi.StatementRef{
+		Type:        "https://slsa.dev/provenance/v1",
+		Attestation: &intoto.ResourceDescriptor{Name: "drop.intoto.jsonl"},
+		Identities:  []*papi.Identity{signer},
+	}
+	sbom := &papi.StatementRef{
+		Type:        "https://spdx.dev/Document",
+		Attestation: &intoto.ResourceDescriptor{Digest: map[string]string{"sha256": "abc", "sha1": "def"}},
+		Identities:  []*papi.Identity{signer},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		rs     *papi.ResultSet
+		passed bool
+		check  func(*testing.T, *VerificationReport)
+	}{
+		{
+			name: "passed",
+			rs: &papi.ResultSet{Results: []*papi.Result{{
+				Status: papi.StatusPASS,
+				Policy: &papi.PolicyRef{Id: "slsa-build"},
+				EvalResults: []*papi.EvalResult{
+					{Id: "provenance", Status: papi.StatusPASS, Statements: []*papi.StatementRef{provenance}},
+					{Id: "sbom", Status: papi.StatusPASS, Statements: []*papi.StatementRef{sbom, sbom}},
+				},
+			}}},
+			passed: true,
+			check: func(t *testing.T, r *VerificationReport) {
+				t.Helper()
+				require.Len(t, r.Policies, 1)
+				require.Equal(t, "slsa-build", r.Policies[0].ID)
+				require.Len(t, r.Policies[0].Tenets, 2)
+				tenet := r.Policies[0].Tenets[0]
+				require.Equal(t, []string{"https://slsa.dev/provenance/v1 (drop.intoto.jsonl)"}, tenet.Attestations)
+				require.Equal(t, []string{signer.Slug()}, tenet.Signers)
+
+				// Repeated statements are listed once, digests by algorithm
+				require.Equal(t, []string{"https://spdx.dev/Document (sha1:def)"}, r.Policies[0].Tenets[1].Attestations)
+			},
+		},
+		{
+			name: "failed-tenet",
+			rs: &papi.ResultSet{Results: []*papi.Result{
+				{Status: papi.StatusPASS, Policy: &papi.PolicyRef{Id: "provenance"}},
+				{
+					Status: papi.StatusFAIL,
+					Policy: &papi.PolicyRef{Id: "vulns"},
+					EvalResults: []*papi.EvalResult{{
+						Id: "no-critical", Status: papi.StatusFAIL,
+						Error: &papi.Error{Message: "critical vulnerability found", Guidance: "update the dependency"},
+					}},
+				},
+			}},
+			passed: false,
+			check: func(t *testing.T, r *VerificationReport) {
+				t.Helper()
+				require.Len(t, r.Policies, 2)
+				tenet := r.Policies[1].Tenets[0]
+				require.Equal(t, papi.StatusFAIL, tenet.Status)
+				require.Equal(t, "critical vulnerability found", tenet.Error)
+				require.Equal(t, "update the dependency", tenet.Guidance)
+				require.Empty(t, tenet.Attestations)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			report := NewVerificationReport(testBinFile, tc.rs)
+			require.Equal(t, testBinFile, report.Asset)
+			require.Equal(t, tc.passed, report.Passed)
+			tc.check(t, report)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/sbom"
+)
+
+// maxSBOMSize caps the size of the SBOMs downloaded with an artifact
+const maxSBOMSize = 64 << 20
+
+var ErrNoSBOM = errors.New("no SBOM was published with the installed artifact")
+
+// InstalledSBOM describes the SBOM stored for an installed artifact
+type InstalledSBOM struct {
+	// Name is the release file the SBOM was downloaded from
+	Name string
+
+	// Format is the SBOM format, spdx or cyclonedx
+	Format string
+
+	// Attested is true when the SBOM is a subject of an attestation
+	// published in the release.
+	Attested bool
+}
+
+// downloadSmallAsset reads a release asset into memory, refusing assets
+// larger than the limit.
+func (dropper *Dropper) downloadSmallAsset(opts *GetOptions, asset github.AssetDataProvider, limit int) ([]byte, error) {
+	if asset.GetSize() > limit {
+		return nil, fmt.Errorf("%s is too large (%d bytes)", asset.GetName(), asset.GetSize())
+	}
+	var buf bytes.Buffer
+	if err := dropper.impl.DownloadAssetToWriter(opts, &buf, asset); err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", asset.GetName(), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fetchSBOM downloads the SBOM published for an artifact among the assets
+// of its release. When the release has attestations named after the SBOM,
+// the SBOM must be one of their subjects. It returns nil when the release
+// has no SBOM for the artifact.
+func (dropper *Dropper) fetchSBOM(
+	opts *GetOptions, asset github.AssetDataProvider, assets []github.AssetDataProvider,
+) (*InstalledSBOM, []byte, error) {
+	names := make([]string, 0, len(assets))
+	for _, a := range assets {
+		names = append(names, a.GetName())
+	}
+	name := sbom.Find(names, asset.GetName())
+	if name == "" {
+		return nil, nil, nil
+	}
+
+	info := &InstalledSBOM{Name: name}
+	var data []byte
+	var err error
+	var attestations []github.AssetDataProvider
+	for _, a := range assets {
+		switch {
+		case a.GetName() == name:
+			if data, err = dropper.downloadSmallAsset(opts, a, maxSBOMSize); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(a.GetName(), name) && isAttestationFile(a.GetName()):
+			attestations = append(attestations, a)
+		}
+	}
+	if info.Format, err = sbom.Detect(data); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	for _, a := range attestations {
+		att, err := dropper.downloadSmallAsset(opts, a, maxAttestationSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := sbom.CheckAttestation(att, digest); err != nil {
+			return nil, nil, fmt.Errorf("checking %s against %s: %w", name, a.GetName(), err)
+		}
+		info.Attested = true
+	}
+	return info, data, nil
+}
+
+// storeReleaseDocuments saves the SBOM and the VEX documents of an
+// artifact keyed by the artifact's digest, recording the SBOM in the
+// artifact.
+func (dropper *Dropper) storeReleaseDocuments(opts *GetOptions, artifact *InstallArtifact, path string, docs *releaseDocuments) error {
+	if docs.SBOM == nil && len(docs.VEX) == 0 {
+		return nil
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	if err := storeVEX(digest, docs.VEX); err != nil {
+		return err
+	}
+	if docs.SBOM == nil {
+		return nil
+	}
+
+	store, err := sbom.Open()
+	if err != nil {
+		return err
+	}
+	if err := store.Save(digest, docs.SBOM.Name, docs.SBOMData); err != nil {
+		return err
+	}
+	artifact.SBOM = docs.SBOM
+
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectSBOM, Verb: EventVerbSaved,
+		Data: map[string]string{
+			"name": docs.SBOM.Name, "format": docs.SBOM.Format, "attested": strconv.FormatBool(docs.SBOM.Attested),
+		},
+	})
+	return nil
+}
+
+// ReadSBOM returns the SBOM stored when an app was installed
+func ReadSBOM(record *inventory.Record) ([]byte, error) {
+	if record.SBOM == "" {
+		return nil, ErrNoSBOM
+	}
+	digest, err := recordDigest(record)
+	if err != nil {
+		return nil, err
+	}
+	store, err := sbom.Open()
+	if err != nil {
+		return nil, err
+	}
+	path, err := store.Lookup(digest, record.SBOM)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // the path is in the SBOM store
+	if err != nil {
+		return nil, fmt.Errorf("reading SBOM: %w", err)
+	}
+	return data, nil
+}
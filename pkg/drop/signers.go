@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+var ErrSignerChanged = errors.New("the identity signing the app changed since it was installed")
+
+// resultSigners collects the identities that signed the attestations
+// evaluated by the policies.
+func resultSigners(rs *papi.ResultSet) []string {
+	ret := []string{}
+	for _, result := range rs.GetResults() {
+		for _, er := range result.GetEvalResults() {
+			for _, st := range er.GetStatements() {
+				for _, id := range st.GetIdentities() {
+					ret = appendSigner(ret, id.Slug())
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// appendSigner adds a normalized signer identity to a sorted list, once
+func appendSigner(signers []string, id string) []string {
+	id = normalizeSigner(id)
+	if id == "" || slices.Contains(signers, id) {
+		return signers
+	}
+	signers = append(signers, id)
+	slices.Sort(signers)
+	return signers
+}
+
+// normalizeSigner drops the git ref from workflow identities. Certificates
+// issued to a workflow name the tag being built, which changes with every
+// release while the workflow stays the same.
+func normalizeSigner(id string) string {
+	if i := strings.LastIndex(id, "@refs/"); i != -1 {
+		return id[:i]
+	}
+	return id
+}
+
+// signerChange returns the signers of a new release that were not pinned
+// when the app was installed. A release signed by nobody when the app had
+// pinned signers is a change too.
+func signerChange(pinned, signers []string) []string {
+	if len(pinned) == 0 {
+		return nil
+	}
+	if len(signers) == 0 {
+		return []string{"(unsigned)"}
+	}
+	ret := []string{}
+	for _, s := range signers {
+		if !slices.Contains(pinned, s) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// checkSigners compares the signers of a verified artifact with the ones
+// pinned when the app was installed. A change blocks the install unless
+// the options only ask for a warning.
+func checkSigners(opts *GetOptions, signers []string) error {
+	changed := signerChange(opts.PinnedSigners, signers)
+	if len(changed) == 0 {
+		return nil
+	}
+	if !opts.WarnSignerChange {
+		return fmt.Errorf(
+			"%w: now signed by %s, expected %s (run \"drop trust reset\" to accept it)",
+			ErrSignerChanged, strings.Join(changed, ", "), strings.Join(opts.PinnedSigners, ", "),
+		)
+	}
+	opts.Listener.HandleEvent(
+		&Event{
+			Object: EventObjectSigners, Verb: EventVerbChanged,
+			Data: map[string]string{
+				"pinned":  strings.Join(opts.PinnedSigners, ", "),
+				"signers": strings.Join(changed, ", "),
+			},
+		},
+	)
+	return nil
+}
+
+// ResetTrust forgets the signer identities pinned for an installed app. The
+// next update pins the identities signing the new release. It returns the
+// identities that were pinned.
+func (dropper *Dropper) ResetTrust(record *inventory.Record) ([]string, error) {
+	path := record.InventoryPath()
+	if path == "" {
+		var err error
+		if path, err = inventory.ScopePath(cmp.Or(record.Scope, ScopeUser)); err != nil {
+			return nil, err
+		}
+	}
+
+	var pinned []string
+	err := inventory.UpdateFile(path, func(inv *inventory.Inventory) error {
+		stored := inv.Get(record.Key())
+		if stored == nil {
+			return fmt.Errorf("app %q is not installed with drop", record.Name)
+		}
+		pinned = stored.Signers
+		stored.Signers = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pinned, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"testing"
+
+	papi "github.com/carabiner-dev/policy/api/v1"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkflowSigner = "sigstore::https://token.actions.githubusercontent.com::https://github.com/org/repo/.github/workflows/release.yaml"
+
+func TestResultSigners(t *testing.T) {
+	t.Parallel()
+	identity := func(ref string) *papi.Identity {
+		return &papi.Identity{Sigstore: &papi.IdentitySigstore{
+			Issuer:   "https://token.actions.githubusercontent.com",
+			Identity: "https://github.com/org/repo/.github/workflows/release.yaml@" + ref,
+		}}
+	}
+	rs := &papi.ResultSet{Results: []*papi.Result{{
+		EvalResults: []*papi.EvalResult{
+			{Statements: []*papi.StatementRef{{Identities: []*papi.Identity{identity("refs/tags/v1.0.0")}}}},
+			{Statements: []*papi.StatementRef{
+				{Identities: []*papi.Identity{identity("refs/heads/main"), {Id: "key-1"}}},
+			}},
+		},
+	}}}
+	require.Equal(t, []string{"key-1", testWorkflowSigner}, resultSigners(rs))
+}
+
+func TestSignerChange(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		pinned  []string
+		signers []string
+		expect  []string
+	}{
+		{name: "nothing-pinned", signers: []string{"gpg::ABC"}},
+		{name: "same", pinned: []string{testWorkflowSigner}, signers: []string{testWorkflowSigner}},
+		{name: "subset", pinned: []string{"gpg::ABC", "minisign::01"}, signers: []string{"gpg::ABC"}},
+		{
+			name: "new-signer", pinned: []string{testWorkflowSigner},
+			signers: []string{testWorkflowSigner, "gpg::ABC"}, expect: []string{"gpg::ABC"},
+		},
+		{name: "unsigned", pinned: []string{"gpg::ABC"}, expect: []string{"(unsigned)"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			changed := signerChange(tc.pinned, tc.signers)
+			if len(tc.expect) == 0 {
+				require.Empty(t, changed)
+				return
+			}
+			require.Equal(t, tc.expect, changed)
+		})
+	}
+}
+
+func TestCheckSigners(t *testing.T) {
+	t.Parallel()
+	opts := &GetOptions{PinnedSigners: []string{"gpg::ABC"}}
+	opts.Listener = &NoopListener{}
+	require.NoError(t, checkSigners(opts, []string{"gpg::ABC"}))
+	require.ErrorIs(t, checkSigners(opts, []string{"gpg::DEF"}), ErrSignerChanged)
+
+	opts.WarnSignerChange = true
+	require.NoError(t, checkSigners(opts, []string{"gpg::DEF"}))
+}
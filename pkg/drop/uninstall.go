@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// Uninstall removes an app installed with drop and deletes its inventory
+// record. The stored evidence, SBOMs and VEX documents are left for
+// PruneEvidence.
+func (dropper *Dropper) Uninstall(record *inventory.Record) error {
+	return dropper.impl.UninstallApp(&dropper.Options, record)
+}
+
+// UninstallApp removes an installed app: the system package through the
+// package manager or the binary, AppImage and the files recorded with them
+// (completions, man pages, desktop entries, package files unpacked into a
+// prefix). Once removed, the record is deleted from its inventory. Nothing
+// is removed when the inventory cannot be written, directly or through the
+// escalation helper, the record would be left pointing to deleted files.
+func (di *defaultImplementation) UninstallApp(opts *Options, record *inventory.Record) error {
+	scope := cmp.Or(record.Scope, ScopeUser)
+	path := record.InventoryPath()
+	if path == "" {
+		var err error
+		if path, err = di.scopeInventoryPath(scope); err != nil {
+			return err
+		}
+	}
+	helper, err := di.inventoryHelper(opts, scope, path)
+	if err != nil {
+		return err
+	}
+
+	if record.Kind == string(ArtifactPackage) && record.Prefix == "" {
+		if err := di.removePackage(opts, record); err != nil {
+			return err
+		}
+	}
+
+	if err := di.removeFiles(opts, installedFiles(record)); err != nil {
+		return err
+	}
+	removeEmptyDirs(record.Dirs)
+
+	if helper != "" {
+		return di.runInventoryCommand(helper, InventoryCmdDelete, record.Key())
+	}
+	return inventory.UpdateFile(path, func(inv *inventory.Inventory) error {
+		if !inv.Remove(record.Key()) {
+			return fmt.Errorf("app %q is not installed with drop", record.Name)
+		}
+		return nil
+	})
+}
+
+// installedFiles returns the files recorded for an app, its binary or
+// AppImage first.
+func installedFiles(record *inventory.Record) []string {
+	files := slices.Clone(record.Files)
+	if record.BinPath != "" && !slices.Contains(files, record.BinPath) {
+		files = append([]string{record.BinPath}, files...)
+	}
+	return files
+}
+
+// UninstallRequiresPrivileges returns true if removing an app needs elevated
+// privileges: system packages, files in directories the user cannot write
+// to and apps recorded in a system inventory only root can write to.
+func UninstallRequiresPrivileges(record *inventory.Record) bool {
+	if record.Kind == string(ArtifactPackage) && record.Prefix == "" || inventoryRequiresPrivileges(record) {
+		return true
+	}
+	return slices.ContainsFunc(installedFiles(record), func(f string) bool {
+		_, err := os.Lstat(f)
+		return err == nil && !dirWritable(filepath.Dir(f))
+	})
+}
+
+// removePackage removes an app installed as a system package. Packages
+// already removed outside of drop are skipped, so their record can still be
+// deleted.
+func (di *defaultImplementation) removePackage(opts *Options, record *inventory.Record) error {
+	name := cmp.Or(record.PackageName, record.Name)
+	if !di.packageInstalled(record.PackageFormat, name) {
+		logrus.Infof("package %s is not installed anymore, forgetting it", name)
+		return nil
+	}
+	if record.PackageVersion != "" {
+		if installed := di.installedPackageVersion(record.PackageFormat, name); installed != "" && installed != record.PackageVersion {
+			logrus.Warnf("%s was changed outside of drop (%s installed, %s recorded)", name, installed, record.PackageVersion)
+		}
+	}
+
+	argv, err := buildPackageRemoveCmd(record.PackageFormat, packageSpec(record), di.runner.LookPath)
+	if err != nil {
+		return err
+	}
+	if os.Geteuid() == 0 {
+		err = di.runner.Run(argv)
+	} else {
+		helper, herr := di.escalationHelper(opts)
+		if herr != nil {
+			return fmt.Errorf("removing packages requires privileges: %w, rerun as root", herr)
+		}
+		err = di.runner.RunPrivileged(helper, argv)
+	}
+	if err != nil {
+		return fmt.Errorf("removing %s package: %w", record.PackageFormat, err)
+	}
+	return nil
+}
+
+// packageSpec returns the name to remove a recorded package by, qualified
+// with its arch when the package manager supports it so only the recorded
+// build of a multiarch package is removed.
+func packageSpec(record *inventory.Record) string {
+	name := cmp.Or(record.PackageName, record.Name)
+	if record.PackageArch == "" {
+		return name
+	}
+	switch record.PackageFormat {
+	case system.PackageDeb:
+		if record.PackageArch != "all" {
+			return name + ":" + record.PackageArch
+		}
+	case system.PackageRPM:
+		return name + "." + record.PackageArch
+	}
+	return name
+}
+
+// removeFiles deletes installed files, through the escalation helper when
+// their directory is not writable. Files already gone are skipped.
+func (di *defaultImplementation) removeFiles(opts *Options, files []string) error {
+	privileged := []string{}
+	errs := []error{}
+	for _, f := range files {
+		if _, err := os.Lstat(f); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if !dirWritable(filepath.Dir(f)) {
+			privileged = append(privileged, f)
+			continue
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("removing %s: %w", f, err))
+		}
+	}
+	if len(privileged) > 0 {
+		helper, err := di.escalationHelper(opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s is not writable: %w, rerun as root", filepath.Dir(privileged[0]), err))
+		} else if err := di.runner.RunPrivileged(helper, append([]string{cmdRm, "-f", "--"}, privileged...)); err != nil {
+			errs = append(errs, fmt.Errorf("removing files: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
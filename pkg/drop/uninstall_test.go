@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+func TestUninstallApp(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		record    func(dir string) *inventory.Record
+		silentErr error
+		expectRun []string
+	}{
+		{
+			name: "binary",
+			record: func(dir string) *inventory.Record {
+				return &inventory.Record{
+					Kind: string(ArtifactBinary), BinPath: filepath.Join(dir, "bin", testAppName),
+					Files: []string{filepath.Join(dir, "share", "bash-completion", "completions", testAppName)},
+				}
+			},
+		},
+		{
+			name: "prefix-package",
+			record: func(dir string) *inventory.Record {
+				return &inventory.Record{
+					Kind: string(ArtifactPackage), PackageFormat: system.PackageApk, Prefix: dir,
+					Files: []string{filepath.Join(dir, "bin", testAppName), filepath.Join(dir, "share", "doc", "README")},
+				}
+			},
+		},
+		{
+			name: "system-package",
+			record: func(string) *inventory.Record {
+				return &inventory.Record{
+					Kind: string(ArtifactPackage), PackageFormat: system.PackageRPM,
+					PackageName: "drop-cli", PackageVersion: "1.0.0-1", PackageArch: "x86_64",
+				}
+			},
+			expectRun: []string{cmdDnf, "remove", "-y", "drop-cli.x86_64"},
+		},
+		{
+			name: "system-package-already-removed",
+			record: func(string) *inventory.Record {
+				return &inventory.Record{
+					Kind: string(ArtifactPackage), PackageFormat: system.PackageRPM, PackageName: "drop-cli",
+				}
+			},
+			silentErr: errors.New("exit 1"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			record := tc.record(dir)
+			record.Host, record.Org, record.Repo, record.Name = "github.com", "carabiner-dev", testAppName, testAppName
+			for _, f := range append(slices.Clone(record.Files), record.BinPath) {
+				if f == "" {
+					continue
+				}
+				require.NoError(t, os.MkdirAll(filepath.Dir(f), 0o755))
+				require.NoError(t, os.WriteFile(f, []byte("data"), 0o600))
+			}
+
+			invPath := filepath.Join(dir, inventory.FileName)
+			inv, err := inventory.OpenFile(invPath)
+			require.NoError(t, err)
+			inv.Add(record)
+			require.NoError(t, inv.Save())
+
+			runner := &fakeRunner{
+				paths:     map[string]bool{cmdDnf: true, cmdRPM: true, EscalationSudo: true},
+				output:    []byte("(none):1.0.0-1"),
+				silentErr: tc.silentErr,
+			}
+			di := &defaultImplementation{runner: runner, inventoryPath: invPath}
+			require.NoError(t, di.UninstallApp(&Options{}, record))
+
+			for _, f := range append(slices.Clone(record.Files), record.BinPath) {
+				if f != "" {
+					require.NoFileExists(t, f)
+				}
+			}
+			inv, err = inventory.OpenFile(invPath)
+			require.NoError(t, err)
+			require.Nil(t, inv.Get(record.Key()))
+
+			switch {
+			case tc.expectRun == nil:
+				require.Empty(t, runner.run)
+			case os.Geteuid() == 0:
+				require.Equal(t, [][]string{tc.expectRun}, runner.run)
+			default:
+				require.Equal(t, [][]string{append([]string{EscalationSudo}, tc.expectRun...)}, runner.run)
+			}
+
+			// The app is not in the inventory anymore
+			require.Error(t, di.UninstallApp(&Options{}, record))
+		})
+	}
+}
+
+func TestUninstallAppInventoryNotWritable(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("running as root, no dir is non-writable")
+	}
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		scope     string
+		paths     map[string]bool
+		expectErr bool
+	}{
+		// The system inventory is updated by drop running as root
+		{name: "system-escalated", scope: ScopeSystem, paths: map[string]bool{EscalationSudo: true}},
+		{name: "system-no-helper", scope: ScopeSystem, expectErr: true},
+		{name: "user", scope: ScopeUser, paths: map[string]bool{EscalationSudo: true}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			bin := filepath.Join(dir, "bin", testAppName)
+			require.NoError(t, os.MkdirAll(filepath.Dir(bin), 0o755))
+			require.NoError(t, os.WriteFile(bin, []byte("data"), 0o600))
+
+			invDir := filepath.Join(dir, "inventory")
+			require.NoError(t, os.Mkdir(invDir, 0o755))
+			invPath := filepath.Join(invDir, inventory.FileName)
+			record := &inventory.Record{
+				Host: "github.com", Org: "carabiner-dev", Repo: testAppName, Name: testAppName,
+				Kind: string(ArtifactBinary), BinPath: bin, Scope: tc.scope,
+			}
+			inv, err := inventory.OpenFile(invPath)
+			require.NoError(t, err)
+			inv.Add(record)
+			require.NoError(t, inv.Save())
+			require.NoError(t, os.Chmod(invDir, 0o555))       //nolint:gosec // intentionally non-writable
+			t.Cleanup(func() { _ = os.Chmod(invDir, 0o755) }) //nolint:errcheck,gosec
+
+			runner := &fakeRunner{paths: tc.paths}
+			di := &defaultImplementation{runner: runner, inventoryPath: invPath}
+			err = di.UninstallApp(&Options{}, record)
+			if tc.expectErr {
+				// Nothing is removed when the record cannot be deleted
+				require.Error(t, err)
+				require.FileExists(t, bin)
+				require.Empty(t, runner.run)
+				return
+			}
+			require.NoError(t, err)
+			require.NoFileExists(t, bin)
+			require.Equal(t, [][]string{{EscalationSudo, exe, "inventory", InventoryCmdDelete, record.Key()}}, runner.run)
+		})
+	}
+}
+
+func TestPackageSpec(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		format string
+		arch   string
+		expect string
+	}{
+		{system.PackageDeb, "amd64", "drop-cli:amd64"},
+		{system.PackageDeb, "all", "drop-cli"},
+		{system.PackageRPM, "noarch", "drop-cli.noarch"},
+		{system.PackagePacman, "x86_64", "drop-cli"},
+		{system.PackageRPM, "", "drop-cli"},
+	} {
+		t.Run(tc.format+"-"+tc.arch, func(t *testing.T) {
+			t.Parallel()
+			record := &inventory.Record{Name: testAppName, PackageFormat: tc.format, PackageName: "drop-cli", PackageArch: tc.arch}
+			require.Equal(t, tc.expect, packageSpec(record))
+		})
+	}
+}
@@ -6,13 +6,14 @@ package drop
 import (
 	"errors"
 	"fmt"
-	"maps"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/integrity"
 	"github.com/carabiner-dev/drop/pkg/inventory"
 )
 
@@ -33,10 +34,11 @@ type UpdateStatus struct {
 	Error error
 }
 
-// CheckUpdates reads the inventory of installed apps and checks the GitHub
-// releases of each of them to see if a newer version is available.
-func (dropper *Dropper) CheckUpdates() ([]*UpdateStatus, error) {
-	inv, err := inventory.Open()
+// CheckUpdates reads the inventories of installed apps and checks the GitHub
+// releases of each of them to see if a newer version is available. Only the
+// apps of a scope are checked when set, those of both scopes otherwise.
+func (dropper *Dropper) CheckUpdates(scope string) ([]*UpdateStatus, error) {
+	records, err := inventory.Installed(scope)
 	if err != nil {
 		return nil, fmt.Errorf("opening install inventory: %w", err)
 	}
@@ -46,9 +48,8 @@ func (dropper *Dropper) CheckUpdates() ([]*UpdateStatus, error) {
 	latestCache := map[string]string{}
 	errCache := map[string]error{}
 
-	ret := make([]*UpdateStatus, 0, len(inv.Installs))
-	for _, key := range slices.Sorted(maps.Keys(inv.Installs)) {
-		record := inv.Installs[key]
+	ret := make([]*UpdateStatus, 0, len(records))
+	for _, record := range records {
 		repoKey := record.Host + "/" + record.Org + "/" + record.Repo
 
 		latest, cached := latestCache[repoKey]
@@ -93,12 +94,54 @@ func (dropper *Dropper) latestReleaseVersion(record *inventory.Record) (string,
 	return releases[0].GetVersion(), nil
 }
 
+// RequiresPrivileges returns true if updating the app needs elevated
+// privileges: system packages, binaries or AppImages in directories the
+// user cannot write to and apps recorded in a system inventory only root
+// can write to.
+func (status *UpdateStatus) RequiresPrivileges() bool {
+	record := status.Record
+	if inventoryRequiresPrivileges(record) {
+		return true
+	}
+	switch record.Kind {
+	case string(ArtifactPackage):
+		return record.Prefix == ""
+	case string(ArtifactBinary), string(ArtifactAppImage):
+		return record.BinPath != "" && !dirWritable(filepath.Dir(record.BinPath))
+	default:
+		return false
+	}
+}
+
 // updateInstallOptions builds the install options to update an app, honoring
 // the choices recorded when it was installed: the artifact kind, the binary
-// location and the verification stance.
+// (or AppImage) location, the verification stance and pinned signers, the
+// policy repository ref, the opt-in to emulated arches and the prefix the
+// app was unpacked into.
 func updateInstallOptions(record *inventory.Record) []FuncGetOption {
+	level := integrity.Level(record.VerificationLevel)
 	options := []FuncGetOption{
-		WithVerifyDownloads(record.Verified),
+		WithVerifyDownloads(level != integrity.LevelNone),
+	}
+	// Updates must verify at least as well as the installed version, a
+	// release that stops publishing signatures is not taken silently.
+	if level.AtLeast(integrity.LevelChecksum) {
+		options = append(options, WithMinVerificationLevel(string(level)))
+	}
+	if len(record.Signers) > 0 {
+		options = append(options, WithPinnedSigners(record.Signers...))
+	}
+	if record.PolicyRef != "" {
+		options = append(options, WithPolicyRef(record.PolicyRef))
+	}
+	if record.Emulated {
+		options = append(options, WithEmulation(true))
+	}
+	if record.Scope != "" {
+		options = append(options, WithScope(record.Scope))
+	}
+	if record.Prefix != "" {
+		options = append(options, WithPrefix(record.Prefix))
 	}
 	switch record.Kind {
 	case string(ArtifactBinary):
@@ -106,8 +149,17 @@ func updateInstallOptions(record *inventory.Record) []FuncGetOption {
 		if record.BinPath != "" {
 			options = append(options, WithBinDir(filepath.Dir(record.BinPath)))
 		}
+		options = append(options, WithCompletions(len(record.Files) > 0))
 	case string(ArtifactPackage):
 		options = append(options, WithDownloadType("p"))
+	case string(ArtifactAppImage):
+		options = append(options, WithDownloadType("i"))
+		if record.BinPath != "" {
+			options = append(options, WithAppsDir(filepath.Dir(record.BinPath)))
+		}
+		options = append(options, WithDesktopIntegration(slices.ContainsFunc(record.Files, func(f string) bool {
+			return strings.HasSuffix(f, ".desktop")
+		})))
 	}
 	return options
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/carabiner-dev/drop/pkg/integrity"
 	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
@@ -45,6 +46,29 @@ func TestVersionIsNewer(t *testing.T) {
 	}
 }
 
+func TestRequiresPrivileges(t *testing.T) {
+	t.Parallel()
+	writable := t.TempDir()
+	missing := filepath.Join(writable, "missing")
+	for _, tc := range []struct {
+		name   string
+		record *inventory.Record
+		expect bool
+	}{
+		{"package", &inventory.Record{Kind: string(ArtifactPackage)}, true},
+		{"prefix-package", &inventory.Record{Kind: string(ArtifactPackage), Prefix: writable}, false},
+		{"binary", &inventory.Record{Kind: string(ArtifactBinary), BinPath: filepath.Join(writable, "drop")}, false},
+		{"binary-unwritable", &inventory.Record{Kind: string(ArtifactBinary), BinPath: filepath.Join(missing, "drop")}, true},
+		{"appimage", &inventory.Record{Kind: string(ArtifactAppImage), BinPath: filepath.Join(writable, "drop.AppImage")}, false},
+		{"appimage-unwritable", &inventory.Record{Kind: string(ArtifactAppImage), BinPath: filepath.Join(missing, "drop.AppImage")}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, (&UpdateStatus{Record: tc.record}).RequiresPrivileges())
+		})
+	}
+}
+
 func TestUpdateInstallOptions(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
@@ -53,27 +77,56 @@ func TestUpdateInstallOptions(t *testing.T) {
 		expectType       string
 		expectBinDir     string
 		expectSkipVerify bool
+		expectMinLevel   integrity.Level
+		expectSigners    []string
+		expectPolicyRef  string
 	}{
 		{
 			name: "verified-binary",
 			record: &inventory.Record{
-				Kind: string(ArtifactBinary), BinPath: "/opt/tools/cosign", Verified: true,
+				Kind: string(ArtifactBinary), BinPath: "/opt/tools/cosign", VerificationLevel: "policy",
+			},
+			expectType: "b", expectBinDir: "/opt/tools", expectSkipVerify: false, expectMinLevel: integrity.LevelPolicy,
+		},
+		{
+			name: "pinned-policy-ref",
+			record: &inventory.Record{
+				Kind: string(ArtifactBinary), BinPath: "/opt/tools/cosign", VerificationLevel: "policy",
+				PolicyRepository: "https://github.com/sigstore/.ampel", PolicyRef: "v1", PolicyCommit: "4b8c2f1",
 			},
-			expectType: "b", expectBinDir: "/opt/tools", expectSkipVerify: false,
+			expectType: "b", expectBinDir: "/opt/tools", expectSkipVerify: false, expectMinLevel: integrity.LevelPolicy,
+			expectPolicyRef: "v1",
 		},
 		{
 			name: "unverified-binary-default-dir",
 			record: &inventory.Record{
-				Kind: string(ArtifactBinary), Verified: false,
+				Kind: string(ArtifactBinary), VerificationLevel: "none",
 			},
 			expectType: "b", expectBinDir: "", expectSkipVerify: true,
 		},
 		{
 			name: "package",
 			record: &inventory.Record{
-				Kind: string(ArtifactPackage), PackageFormat: system.PackageRPM, Verified: true,
+				Kind: string(ArtifactPackage), PackageFormat: system.PackageRPM, VerificationLevel: "checksum",
+			},
+			expectType: "p", expectBinDir: "", expectSkipVerify: false, expectMinLevel: integrity.LevelChecksum,
+		},
+		{
+			name: "appimage",
+			record: &inventory.Record{
+				Kind: string(ArtifactAppImage), BinPath: "/home/user/Applications/drop.AppImage", VerificationLevel: "sigstore",
+				Signers: []string{"sigstore::https://token.actions.githubusercontent.com::https://github.com/org/app/.github/workflows/release.yml"},
+			},
+			expectType: "i", expectBinDir: "", expectSkipVerify: false, expectMinLevel: integrity.LevelSigstore,
+			expectSigners: []string{"sigstore::https://token.actions.githubusercontent.com::https://github.com/org/app/.github/workflows/release.yml"},
+		},
+		{
+			name: "package-in-prefix",
+			record: &inventory.Record{
+				Kind: string(ArtifactPackage), PackageFormat: system.PackageDeb, VerificationLevel: "policy",
+				Prefix: "/home/user/.local",
 			},
-			expectType: "p", expectBinDir: "", expectSkipVerify: false,
+			expectType: "p", expectBinDir: "/home/user/.local/bin", expectSkipVerify: false, expectMinLevel: integrity.LevelPolicy,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -86,6 +139,9 @@ func TestUpdateInstallOptions(t *testing.T) {
 			// filepath.Dir returns OS-native separators on windows
 			require.Equal(t, filepath.FromSlash(tc.expectBinDir), opts.BinDir)
 			require.Equal(t, tc.expectSkipVerify, opts.SkipVerification)
+			require.Equal(t, tc.expectMinLevel, opts.MinLevel)
+			require.Equal(t, tc.expectSigners, opts.PinnedSigners)
+			require.Equal(t, tc.expectPolicyRef, opts.PolicyRef)
 		})
 	}
 }
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drop
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+	"github.com/carabiner-dev/drop/pkg/sbom"
+	"github.com/carabiner-dev/drop/pkg/vuln"
+)
+
+// maxVEXSize caps the size of the OpenVEX documents downloaded
+const maxVEXSize = 8 << 20
+
+var ErrVulnerable = errors.New("app is affected by vulnerabilities above the allowed severity")
+
+// VulnerabilityReport is the result of scanning an app and the components
+// of its SBOM for vulnerabilities.
+type VulnerabilityReport struct {
+	// Findings are the vulnerabilities affecting the app, the most severe
+	// first.
+	Findings []*vuln.Finding
+
+	// Dismissed are the findings the VEX documents of the release state
+	// do not affect the app.
+	Dismissed []*vuln.Finding
+
+	// Components is the number of SBOM components scanned
+	Components int
+}
+
+// Exceeding returns the findings more severe than the maximum
+func (r *VulnerabilityReport) Exceeding(maximum vuln.Severity) []*vuln.Finding {
+	ret := []*vuln.Finding{}
+	for _, f := range r.Findings {
+		if f.Severity.Exceeds(maximum) {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// releaseDocuments are the SBOM and VEX documents published for an
+// artifact in its release.
+type releaseDocuments struct {
+	SBOM     *InstalledSBOM
+	SBOMData []byte
+
+	// VEX are the OpenVEX documents keyed by file name
+	VEX map[string][]byte
+}
+
+// fetchReleaseDocuments downloads the SBOM and the OpenVEX documents of an
+// artifact. They only add information, so failures only warn.
+func (dropper *Dropper) fetchReleaseDocuments(opts *GetOptions, asset github.AssetDataProvider) *releaseDocuments {
+	docs := &releaseDocuments{VEX: map[string][]byte{}}
+	assets, err := dropper.client.ListReleaseAssets(asset)
+	if err != nil {
+		logrus.Warnf("listing the release assets failed, not reading its SBOM and VEX documents: %v", err)
+		return docs
+	}
+	if docs.SBOM, docs.SBOMData, err = dropper.fetchSBOM(opts, asset, assets); err != nil {
+		logrus.Warnf("reading the SBOM of the artifact failed: %v", err)
+	}
+	for _, a := range assets {
+		if !vuln.IsVEXFile(a.GetName()) {
+			continue
+		}
+		data, err := dropper.downloadSmallAsset(opts, a, maxVEXSize)
+		if err != nil {
+			logrus.Warnf("reading VEX document failed: %v", err)
+			continue
+		}
+		docs.VEX[a.GetName()] = data
+	}
+	return docs
+}
+
+// appProducts returns the identifiers VEX statements may use to refer to an
+// app released from a repository.
+func appProducts(host, org, repo, version string) []string {
+	return []string{
+		fmt.Sprintf("pkg:github/%s/%s@%s", org, repo, version),
+		fmt.Sprintf("pkg:golang/%s/%s/%s@%s", host, org, repo, version),
+	}
+}
+
+// appScan is an app to look up in the vulnerability database: the app
+// itself and the components of its SBOM.
+type appScan struct {
+	asset  github.AssetDataProvider
+	pkgs   []*vuln.Package
+	vex    [][]byte
+	report *VulnerabilityReport
+}
+
+// newAppScan lists the packages to scan for an app
+func newAppScan(asset github.AssetDataProvider, sbomData []byte, vexDocs [][]byte) *appScan {
+	scan := &appScan{
+		asset:  asset,
+		pkgs:   vuln.AppPackages(asset.GetHost(), asset.GetOrg(), asset.GetRepo(), asset.GetVersion()),
+		vex:    vexDocs,
+		report: &VulnerabilityReport{},
+	}
+	if sbomData == nil {
+		return scan
+	}
+	purls, err := sbom.Components(sbomData)
+	if err != nil {
+		logrus.Warnf("reading the SBOM components failed, scanning only the app: %v", err)
+	}
+	for _, purl := range purls {
+		p, err := vuln.PackageFromPURL(purl)
+		if err != nil {
+			logrus.Debugf("not scanning %s: %v", purl, err)
+			continue
+		}
+		scan.pkgs = append(scan.pkgs, p)
+		scan.report.Components++
+	}
+	return scan
+}
+
+// scanApps looks up apps in the vulnerability database in a single pass,
+// dismissing the findings covered by the VEX documents of each app.
+func scanApps(db *vuln.Database, scans []*appScan) error {
+	owners := map[*vuln.Package]*appScan{}
+	pkgs := []*vuln.Package{}
+	for _, scan := range scans {
+		for _, p := range scan.pkgs {
+			owners[p] = scan
+		}
+		pkgs = append(pkgs, scan.pkgs...)
+	}
+
+	findings, err := db.Scan(pkgs)
+	if err != nil {
+		return err
+	}
+	appFindings := map[*appScan][]*vuln.Finding{}
+	for _, f := range findings {
+		appFindings[owners[f.Package]] = append(appFindings[owners[f.Package]], f)
+	}
+
+	for _, scan := range scans {
+		docs, err := vuln.ParseVEX(scan.vex...)
+		if err != nil {
+			return err
+		}
+		asset := scan.asset
+		products := appProducts(asset.GetHost(), asset.GetOrg(), asset.GetRepo(), asset.GetVersion())
+		scan.report.Findings, scan.report.Dismissed = vuln.ApplyVEX(docs, products, appFindings[scan])
+		slices.SortFunc(scan.report.Findings, func(a, b *vuln.Finding) int {
+			return cmp.Or(b.Severity.Rank()-a.Severity.Rank(), strings.Compare(a.ID, b.ID))
+		})
+	}
+	return nil
+}
+
+// openVulnDatabase opens the configured database. Without one configured, a
+// missing database in the default location turns the checks off.
+func openVulnDatabase(opts *Options) (*vuln.Database, error) {
+	db, err := vuln.OpenDatabase(opts.VulnDatabase)
+	if err != nil {
+		if errors.Is(err, vuln.ErrNoDatabase) && opts.VulnDatabase == "" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return db, nil
+}
+
+// checkVulnerabilities scans an artifact about to be installed, refusing it
+// when a vulnerability exceeds the allowed severity unless the options
+// ignore them.
+func checkVulnerabilities(opts *GetOptions, asset github.AssetDataProvider, docs *releaseDocuments) error {
+	db, err := openVulnDatabase(&opts.Options)
+	if err != nil {
+		return err
+	}
+	if db == nil {
+		logrus.Debug("no vulnerability database found, not scanning")
+		return nil
+	}
+
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectVulnerabilities, Verb: EventVerbRunning,
+		Data: map[string]string{"database": db.Path()},
+	})
+	vexDocs := make([][]byte, 0, len(docs.VEX))
+	for _, data := range docs.VEX {
+		vexDocs = append(vexDocs, data)
+	}
+	scan := newAppScan(asset, docs.SBOMData, vexDocs)
+	if err := scanApps(db, []*appScan{scan}); err != nil {
+		return fmt.Errorf("scanning for vulnerabilities: %w", err)
+	}
+	report := scan.report
+
+	lines := make([]string, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		lines = append(lines, f.String())
+	}
+	opts.Listener.HandleEvent(&Event{
+		Object: EventObjectVulnerabilities, Verb: EventVerbDone,
+		Data: map[string]string{
+			"count":      strconv.Itoa(len(report.Findings)),
+			"dismissed":  strconv.Itoa(len(report.Dismissed)),
+			"components": strconv.Itoa(report.Components),
+			"findings":   strings.Join(lines, "\n"),
+		},
+	})
+
+	exceeding := report.Exceeding(opts.MaxSeverity)
+	if len(exceeding) == 0 || opts.IgnoreVulnerabilities {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: %d vulnerabilities above %s (use --ignore-vulns to install anyway)",
+		ErrVulnerable, len(exceeding), opts.MaxSeverity,
+	)
+}
+
+// ScanInstalled scans installed apps and the components of the SBOMs
+// stored with them, using the VEX documents of their releases stored at
+// install time. It needs no network access. The reports are returned in the
+// order of the records.
+func (dropper *Dropper) ScanInstalled(records []*inventory.Record) ([]*VulnerabilityReport, error) {
+	db, err := vuln.OpenDatabase(dropper.Options.VulnDatabase)
+	if err != nil {
+		return nil, err
+	}
+	store, err := vuln.OpenVEXStore()
+	if err != nil {
+		return nil, err
+	}
+
+	scans := make([]*appScan, 0, len(records))
+	ret := make([]*VulnerabilityReport, 0, len(records))
+	for _, record := range records {
+		data, err := ReadSBOM(record)
+		if err != nil && !errors.Is(err, ErrNoSBOM) {
+			return nil, fmt.Errorf("reading SBOM of %s: %w", record.Name, err)
+		}
+		var vexDocs [][]byte
+		if digest := record.Digest["sha256"]; digest != "" {
+			if vexDocs, err = store.Load(digest); err != nil {
+				return nil, err
+			}
+		}
+		scan := newAppScan(recordAsset(record), data, vexDocs)
+		scans = append(scans, scan)
+		ret = append(ret, scan.report)
+	}
+	if err := scanApps(db, scans); err != nil {
+		return nil, fmt.Errorf("scanning for vulnerabilities: %w", err)
+	}
+	return ret, nil
+}
+
+// storeVEX saves the VEX documents of an artifact, keyed by its digest
+func storeVEX(digest string, docs map[string][]byte) error {
+	store, err := vuln.OpenVEXStore()
+	if err != nil {
+		return err
+	}
+	return store.Save(digest, docs)
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package evidence keeps copies of the policy sets and attestations used to
+// verify the artifacts drop installs. Entries are keyed by the sha256 digest
+// of the verified artifact and live in drop's data directory, so installed
+// apps can be verified again without network access.
+package evidence
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+const (
+	dirName = "drop"
+
+	// policiesDir and attestationsDir hold the files of an entry
+	policiesDir     = "policies"
+	attestationsDir = "attestations"
+)
+
+var ErrNotFound = errors.New("no verification evidence stored for artifact")
+
+// digestRegex matches the hex sha256 digests keying the entries
+var digestRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// Store is a directory of verification evidence entries
+type Store struct {
+	dir string
+}
+
+// Bundle is the evidence used to verify an artifact
+type Bundle struct {
+	// Policies are the policy sets, as JSON
+	Policies [][]byte
+
+	// Attestations are the attestation files keyed by file name
+	Attestations map[string][]byte
+}
+
+// Entry points to the files of a stored bundle
+type Entry struct {
+	PolicyFiles      []string
+	AttestationFiles []string
+}
+
+// DefaultDir returns the location of the evidence store in the user's data
+// directory ($XDG_DATA_HOME or ~/.local/share).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, dirName, "evidence"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", dirName, "evidence"), nil
+}
+
+// Open returns the store in its default location
+func Open() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(dir), nil
+}
+
+// NewStore returns a store rooted at a directory
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// entryDir returns the directory of the entry of a digest
+func (s *Store) entryDir(digest string) (string, error) {
+	if !digestRegex.MatchString(digest) {
+		return "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return filepath.Join(s.dir, "sha256", digest), nil
+}
+
+// Save stores the evidence of an artifact, replacing any previous copy
+func (s *Store) Save(digest string, bundle *Bundle) error {
+	dir, err := s.entryDir(digest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o750); err != nil {
+		return fmt.Errorf("creating evidence directory: %w", err)
+	}
+
+	// Write the new entry aside and swap it in once complete
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".entry-")
+	if err != nil {
+		return fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck
+
+	for i, data := range bundle.Policies {
+		if err := writeEntryFile(filepath.Join(tmp, policiesDir), fmt.Sprintf("%03d.json", i), data); err != nil {
+			return err
+		}
+	}
+	for name, data := range bundle.Attestations {
+		if err := writeEntryFile(filepath.Join(tmp, attestationsDir), name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("removing previous evidence: %w", err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return fmt.Errorf("storing evidence: %w", err)
+	}
+	return nil
+}
+
+// writeEntryFile writes a file of an entry, the name must be a plain file
+// name as attestation names come from release assets.
+func writeEntryFile(dir, name string, data []byte) error {
+	if name != filepath.Base(name) || name == "." || name == ".." || name == "" {
+		return fmt.Errorf("invalid evidence file name %q", name)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("creating evidence directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("writing evidence file: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the files of the evidence stored for a digest
+func (s *Store) Lookup(digest string) (*Entry, error) {
+	dir, err := s.entryDir(digest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("reading evidence: %w", err)
+	}
+
+	entry := &Entry{}
+	if entry.PolicyFiles, err = listFiles(filepath.Join(dir, policiesDir)); err != nil {
+		return nil, err
+	}
+	if entry.AttestationFiles, err = listFiles(filepath.Join(dir, attestationsDir)); err != nil {
+		return nil, err
+	}
+	if len(entry.PolicyFiles) == 0 {
+		return nil, ErrNotFound
+	}
+	return entry, nil
+}
+
+// listFiles returns the files in a directory in name order, a missing
+// directory has no files.
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading evidence directory: %w", err)
+	}
+	ret := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			ret = append(ret, filepath.Join(dir, e.Name()))
+		}
+	}
+	return ret, nil
+}
+
+// Prune removes the entries of the digests not in the keep list, returning
+// the number of entries removed.
+func (s *Store) Prune(keep []string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "sha256"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading evidence store: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() || !digestRegex.MatchString(e.Name()) || slices.Contains(keep, e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.dir, "sha256", e.Name())); err != nil {
+			return removed, fmt.Errorf("removing evidence: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
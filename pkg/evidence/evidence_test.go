@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDigest  = strings.Repeat("a", 64)
+	otherDigest = strings.Repeat("b", 64)
+)
+
+func TestSaveLookup(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir())
+
+	_, err := store.Lookup(testDigest)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Save(testDigest, &Bundle{
+		Policies:     [][]byte{[]byte(`{"id":"one"}`), []byte(`{"id":"two"}`)},
+		Attestations: map[string][]byte{"drop.intoto.jsonl": []byte(`{}`)},
+	}))
+	entry, err := store.Lookup(testDigest)
+	require.NoError(t, err)
+	require.Len(t, entry.PolicyFiles, 2)
+	require.Len(t, entry.AttestationFiles, 1)
+	require.Equal(t, "drop.intoto.jsonl", filepath.Base(entry.AttestationFiles[0]))
+	data, err := os.ReadFile(entry.PolicyFiles[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"two"}`, string(data))
+
+	// Saving again replaces the entry
+	require.NoError(t, store.Save(testDigest, &Bundle{Policies: [][]byte{[]byte(`{"id":"three"}`)}}))
+	entry, err = store.Lookup(testDigest)
+	require.NoError(t, err)
+	require.Len(t, entry.PolicyFiles, 1)
+	require.Empty(t, entry.AttestationFiles)
+}
+
+func TestSaveInvalid(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir())
+	for _, tc := range []struct {
+		name   string
+		digest string
+		bundle *Bundle
+	}{
+		{name: "bad-digest", digest: "../../etc", bundle: &Bundle{}},
+		{name: "short-digest", digest: "abc", bundle: &Bundle{}},
+		{
+			name: "escaping-name", digest: testDigest,
+			bundle: &Bundle{Attestations: map[string][]byte{"../evil.jsonl": nil}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Error(t, store.Save(tc.digest, tc.bundle))
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir())
+	removed, err := store.Prune(nil)
+	require.NoError(t, err)
+	require.Zero(t, removed)
+
+	for _, d := range []string{testDigest, otherDigest} {
+		require.NoError(t, store.Save(d, &Bundle{Policies: [][]byte{[]byte(`{}`)}}))
+	}
+	removed, err = store.Prune([]string{testDigest})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	_, err = store.Lookup(testDigest)
+	require.NoError(t, err)
+	_, err = store.Lookup(otherDigest)
+	require.ErrorIs(t, err, ErrNotFound)
+}
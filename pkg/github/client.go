@@ -39,6 +39,10 @@ func New() (*Client, error) {
 	}, nil
 }
 
+// ErrNotFound is returned when a repository or ref does not exist (or is
+// not visible with the current credentials).
+var ErrNotFound = errors.New("not found")
+
 // DefaultHost is the hostname of the public GitHub instance
 const DefaultHost = "github.com"
 
@@ -89,6 +93,27 @@ func RepoURLFromString(str string) (string, error) {
 	return fmt.Sprintf("%s://%s/%s/%s", scheme, host, parts[0], parts[1]), nil
 }
 
+// RepoRefFromString parses a repository with an optional @ref suffix naming
+// a branch, tag or commit. It returns the repository URL and the ref.
+func RepoRefFromString(str string) (repoURL, ref string, err error) {
+	rest := str
+	if _, r, ok := strings.Cut(str, "://"); ok {
+		rest = r
+	}
+	if i := strings.Index(rest, "@"); i != -1 {
+		ref = rest[i+1:]
+		str = str[:len(str)-len(rest)+i]
+		if ref == "" {
+			return "", "", errors.New("empty ref after @")
+		}
+	}
+	repoURL, err = RepoURLFromString(str)
+	if err != nil {
+		return "", "", err
+	}
+	return repoURL, ref, nil
+}
+
 func NewAssetFromURLString(urlString string) *Asset {
 	if strings.HasPrefix(urlString, DefaultHost) {
 		urlString = "https://" + urlString
@@ -119,6 +144,30 @@ func NewAssetFromURLString(urlString string) *Asset {
 	}
 }
 
+// GetCommit returns the commit a ref (branch, tag or commit hash) points to
+// in a repository. An empty ref returns the head of the default branch.
+func (c *Client) GetCommit(rdata RepoDataProvider, ref string) (*Commit, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	rc, _, err := c.client.Repositories.GetCommit(
+		context.Background(), rdata.GetOrg(), rdata.GetRepo(), ref, nil,
+	)
+	if err != nil {
+		var gherr *gogithub.ErrorResponse
+		if errors.As(err, &gherr) && gherr.Response != nil && gherr.Response.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s/%s@%s", ErrNotFound, rdata.GetOrg(), rdata.GetRepo(), ref)
+		}
+		return nil, fmt.Errorf("fetching commit: %w", err)
+	}
+	commit := &Commit{SHA: rc.GetSHA()}
+	if v := rc.GetCommit().GetVerification(); v != nil {
+		commit.Signature = v.GetSignature()
+		commit.Payload = v.GetPayload()
+	}
+	return commit, nil
+}
+
 // ListReleases returns a list of the latest releases in a repo
 func (c *Client) ListReleases(rdata RepoDataProvider) ([]ReleaseDataProvider, error) {
 	releases, _, err := c.client.Repositories.ListReleases(
@@ -59,3 +59,33 @@ func TestRepoURLFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoRefFromString(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		input   string
+		repo    string
+		ref     string
+		mustErr bool
+	}{
+		{name: "slug", input: "github.com/org/policies", repo: "https://github.com/org/policies"},
+		{name: "tag", input: "github.com/org/policies@v1.2.0", repo: "https://github.com/org/policies", ref: "v1.2.0"},
+		{name: "branch-with-slash", input: "https://github.com/org/policies@release/v1", repo: "https://github.com/org/policies", ref: "release/v1"},
+		{name: "locator", input: "git+https://github.com/org/policies@4b8c2f1", repo: "https://github.com/org/policies", ref: "4b8c2f1"},
+		{name: "empty-ref", input: "github.com/org/policies@", mustErr: true},
+		{name: "no-repo", input: "github.com/org@v1", mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, ref, err := RepoRefFromString(tc.input)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.repo, repo)
+			require.Equal(t, tc.ref, ref)
+		})
+	}
+}
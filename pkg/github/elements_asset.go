@@ -7,6 +7,8 @@ import (
 	"time"
 
 	gogithub "github.com/google/go-github/v60/github"
+
+	"github.com/carabiner-dev/drop/pkg/system"
 )
 
 func newAssetFromGitHubAsset(src ReleaseDataProvider, asset *gogithub.ReleaseAsset) *Asset {
@@ -25,6 +27,7 @@ func newAssetFromGitHubAsset(src ReleaseDataProvider, asset *gogithub.ReleaseAss
 		Label:       asset.GetLabel(),
 		Os:          os,
 		Arch:        arch,
+		ArchLevel:   system.GetArchLevelFromFilename(arch, asset.GetName()),
 	}
 }
 
@@ -49,6 +52,10 @@ type Asset struct {
 	UpdatedAt   time.Time
 	Arch        string
 	Os          string
+
+	// ArchLevel is the CPU feature level the asset was built for, empty
+	// when the filename does not specify one.
+	ArchLevel string
 }
 
 func (a *Asset) GetHost() string {
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package github
+
+import (
+	"crypto/sha1" //nolint:gosec // git commit ids are sha1 hashes
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Commit is a git commit and, when signed, its signature
+type Commit struct {
+	SHA string
+
+	// Signature is the armored signature of the commit, Payload the commit
+	// object it signs (the commit without its signature header).
+	Signature string
+	Payload   string
+}
+
+// Signed returns true if the commit carries a signature
+func (c *Commit) Signed() bool {
+	return c.Signature != "" && c.Payload != ""
+}
+
+// CheckPayload checks the signed payload is the commit: adding the
+// signature header back to the payload must hash to the commit id.
+func (c *Commit) CheckPayload() error {
+	headers, message, ok := strings.Cut(c.Payload, "\n\n")
+	if !ok {
+		return fmt.Errorf("commit %s payload has no message", c.SHA)
+	}
+	sig := strings.ReplaceAll(strings.TrimSuffix(c.Signature, "\n"), "\n", "\n ")
+	object := headers + "\ngpgsig " + sig + "\n\n" + message
+
+	h := sha1.New() //nolint:gosec // git commit ids are sha1 hashes
+	fmt.Fprintf(h, "commit %d\x00%s", len(object), object)
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != c.SHA {
+		return fmt.Errorf("signed payload of commit %s hashes to %s", c.SHA, sum)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitCheckPayload(t *testing.T) {
+	t.Parallel()
+	// The id of the commit object as hashed by git
+	commit := &Commit{
+		SHA: "be395a23adbbe18351968b25d020aee6a7168515",
+		Payload: "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+			"author A <a@b> 1792358901 +0000\n" +
+			"committer A <a@b> 1792358901 +0000\n\ninit\n",
+		Signature: "-----BEGIN PGP SIGNATURE-----\n\niQEzBAABCAAdFiEE\n=abcd\n-----END PGP SIGNATURE-----\n",
+	}
+	require.True(t, commit.Signed())
+	require.NoError(t, commit.CheckPayload())
+
+	tampered := *commit
+	tampered.Payload = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author B <a@b> 1792358901 +0000\n" +
+		"committer A <a@b> 1792358901 +0000\n\ninit\n"
+	require.Error(t, tampered.CheckPayload())
+
+	require.False(t, (&Commit{SHA: commit.SHA}).Signed())
+}
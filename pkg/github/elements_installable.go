@@ -91,6 +91,9 @@ func (i *Installable) GetArchVariants() []string {
 func (i *Installable) GetArchiveTypes() []string {
 	ret := []string{}
 	for _, v := range i.Variants {
+		if system.IsPackage(v.Name) {
+			continue
+		}
 		ext := filepath.Ext(v.Name)
 		if ext != "" {
 			if slices.Contains(system.ArchiveTypes, ext[1:]) {
@@ -104,11 +107,10 @@ func (i *Installable) GetArchiveTypes() []string {
 func (i *Installable) GetPackageTypes() []string {
 	ret := []string{}
 	for _, v := range i.Variants {
-		ext := filepath.Ext(v.Name)
-		if ext != "" {
-			if slices.Contains(system.PackageTypes, ext[1:]) {
-				ret = append(ret, ext)
-			}
+		// Package extensions can span several dots (.pkg.tar.zst)
+		t, ext := system.PackageExtensions.GetTypeExtensionFromFile(v.Name)
+		if t != "" && slices.Contains(system.PackageTypes, t) {
+			ret = append(ret, "."+ext)
 		}
 	}
 	return ret
@@ -184,6 +186,7 @@ func assetListToInstallableList(assets []AssetDataProvider) []AssetDataProvider
 				UpdatedAt:   asset.GetUpdatedAt(),
 				Arch:        arch,
 				Os:          os,
+				ArchLevel:   system.GetArchLevelFromFilename(arch, asset.GetName()),
 			},
 		)
 	}
@@ -234,8 +237,9 @@ func getOsFromFilename(filename string) string {
 		}
 	}
 
-	// If it's a package then we know
-	if strings.HasSuffix(filename, ".rpm") || strings.HasSuffix(filename, ".deb") || strings.HasSuffix(filename, ".apk") {
+	// If it's a package (or an AppImage) then we know
+	if strings.HasSuffix(filename, ".rpm") || strings.HasSuffix(filename, ".deb") || strings.HasSuffix(filename, ".apk") ||
+		system.PackageExtensions.GetTypeFromFile(filename) == system.PackagePacman || system.IsAppImage(filename) {
 		return system.OSLinux
 	}
 
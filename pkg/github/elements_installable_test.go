@@ -65,6 +65,9 @@ func TestGetOsFromFilename(t *testing.T) {
 			require.Equal(t, expect[i], res, "%d → %s", i, filename)
 		})
 	}
+	// pacman packages don't carry the OS label but only run on linux
+	require.Equal(t, system.OSLinux, getOsFromFilename("drop-1.0.0-1-x86_64.pkg.tar.zst"))
+	require.Equal(t, system.OSLinux, getOsFromFilename("Drop-1.0.0-x86_64.AppImage"))
 }
 
 func TestTrimSeparatorSuffix(t *testing.T) {
@@ -195,3 +198,21 @@ func TestFileListToInstallableList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetArchFromVariantFilename(t *testing.T) {
+	t.Parallel()
+	for filename, expect := range map[string]string{
+		"app-linux-amd64v3":          system.ArchX8664,
+		"app_linux_x86_64_v2.tar.gz": system.ArchX8664,
+		"app-linux-armv6":            system.ArchArm,
+		"app-linux-armv7.tar.gz":     system.ArchArm,
+		"app-linux-arm64-v8.2":       system.ArchArm64,
+		"app-linux-arm64v8":          system.ArchArm64,
+		"app-darwin-universal":       system.ArchUniversal,
+	} {
+		t.Run(filename, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, expect, getArchFromFilename(filename))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package integrity
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// checksumSuffixes are the extensions of the files holding the checksum of
+// a single asset, named after it (eg drop-linux-amd64.sha256).
+var checksumSuffixes = []string{".sha256", ".sha256sum", ".sha512", ".sha512sum"}
+
+// signatureSuffixes are the extensions of signature and certificate files,
+// which are never checksum files even when named after one.
+var signatureSuffixes = []string{
+	".sig", ".asc", ".gpg", ".minisig", ".sigstore.json", ".sigstore", ".bundle", ".pem", ".cert", ".crt",
+}
+
+// bsdChecksumRegex matches the lines of BSD style checksum files:
+// SHA256 (file) = digest
+var bsdChecksumRegex = regexp.MustCompile(`^(SHA256|SHA512) \((.+)\) = ([a-fA-F0-9]+)$`)
+
+// checksumEntry is a line of a checksums file
+type checksumEntry struct {
+	// Name is the file the digest applies to, empty in single checksum
+	// files holding only the digest.
+	Name      string
+	Algorithm string
+	Digest    string
+}
+
+// hasSuffix returns true if the name ends with any of the suffixes
+func hasSuffix(name string, suffixes []string) bool {
+	name = strings.ToLower(name)
+	return slices.ContainsFunc(suffixes, func(s string) bool { return strings.HasSuffix(name, s) })
+}
+
+// isChecksumFile returns true for release files listing the checksums of
+// the other assets (checksums.txt, SHA256SUMS and the like).
+func isChecksumFile(name string) bool {
+	if hasSuffix(name, signatureSuffixes) {
+		return false
+	}
+	lower := strings.ToLower(name)
+	for _, s := range []string{"checksum", "sha256sum", "sha512sum"} {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// checksumFiles returns the release files that may hold the checksum of an
+// asset: those named after it first, then the shared checksum files.
+func checksumFiles(files []string, name string) []string {
+	ret := []string{}
+	for _, s := range checksumSuffixes {
+		if slices.Contains(files, name+s) {
+			ret = append(ret, name+s)
+		}
+	}
+	for _, f := range files {
+		if f != name && !slices.Contains(ret, f) && isChecksumFile(f) {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// digestAlgorithm returns the algorithm producing a hex digest of the
+// length of the digest, empty when unsupported.
+func digestAlgorithm(digest string) string {
+	switch len(digest) {
+	case sha256.Size * 2:
+		return "sha256"
+	case sha512.Size * 2:
+		return "sha512"
+	default:
+		return ""
+	}
+}
+
+// isHex returns true if the string is hex encoded
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// parseChecksums reads the entries of a checksums file in the GNU
+// coreutils format (digest, whitespace, optional '*' and the file name) or
+// the BSD one. Lines with unsupported algorithms are ignored.
+func parseChecksums(data []byte) []checksumEntry {
+	ret := []checksumEntry{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := bsdChecksumRegex.FindStringSubmatch(line); m != nil {
+			ret = append(ret, checksumEntry{
+				Name: m[2], Algorithm: strings.ToLower(m[1]), Digest: strings.ToLower(m[3]),
+			})
+			continue
+		}
+
+		digest, name, _ := strings.Cut(line, " ")
+		if !isHex(digest) || digestAlgorithm(digest) == "" {
+			continue
+		}
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		ret = append(ret, checksumEntry{
+			Name: name, Algorithm: digestAlgorithm(digest), Digest: strings.ToLower(digest),
+		})
+	}
+	return ret
+}
+
+// matchChecksums checks the artifact against the checksum files in the
+// release, returning those listing it. A checksum that does not match the
+// artifact is an error.
+func matchChecksums(release Release, files []string, name string, digests *fileDigests) ([]string, error) {
+	ret := []string{}
+	for _, f := range checksumFiles(files, name) {
+		data, err := release.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		single := hasSuffix(f, checksumSuffixes)
+		listed := false
+		for _, entry := range parseChecksums(data) {
+			// Files named after the asset may hold only the digest
+			if path.Base(entry.Name) != name && (!single || entry.Name != "") {
+				continue
+			}
+			digest, err := digests.digest(entry.Algorithm)
+			if err != nil {
+				return nil, err
+			}
+			if digest != entry.Digest {
+				return nil, fmt.Errorf("%w in %s", ErrDigestMismatch, f)
+			}
+			listed = true
+		}
+		if listed {
+			ret = append(ret, f)
+		}
+	}
+	return ret, nil
+}
+
+// fileDigests computes the digests of a file as they are needed
+type fileDigests struct {
+	path    string
+	digests map[string]string
+}
+
+func newFileDigests(file string) *fileDigests {
+	return &fileDigests{path: file, digests: map[string]string{}}
+}
+
+// open returns a reader of the file contents
+func (d *fileDigests) open() (io.ReadCloser, error) {
+	f, err := os.Open(d.path) //nolint:gosec // the downloaded artifact
+	if err != nil {
+		return nil, fmt.Errorf("opening artifact: %w", err)
+	}
+	return f, nil
+}
+
+// digest returns the hex digest of the file with an algorithm
+func (d *fileDigests) digest(algorithm string) (string, error) {
+	if s, ok := d.digests[algorithm]; ok {
+		return s, nil
+	}
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+
+	f, err := d.open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing artifact: %w", err)
+	}
+	d.digests[algorithm] = hex.EncodeToString(h.Sum(nil))
+	return d.digests[algorithm], nil
+}
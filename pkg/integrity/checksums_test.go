@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package integrity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksums(t *testing.T) {
+	t.Parallel()
+	sha256 := strings.Repeat("a", 64)
+	sha512 := strings.Repeat("B", 128)
+	for _, tc := range []struct {
+		name   string
+		data   string
+		expect []checksumEntry
+	}{
+		{
+			name: "gnu",
+			data: sha256 + "  drop-linux-amd64\n" + sha512 + " *drop.exe\n",
+			expect: []checksumEntry{
+				{Name: "drop-linux-amd64", Algorithm: "sha256", Digest: sha256},
+				{Name: "drop.exe", Algorithm: "sha512", Digest: strings.ToLower(sha512)},
+			},
+		},
+		{
+			name:   "bsd",
+			data:   "SHA256 (drop.tar.gz) = " + sha256 + "\n",
+			expect: []checksumEntry{{Name: "drop.tar.gz", Algorithm: "sha256", Digest: sha256}},
+		},
+		{
+			name:   "digest-only",
+			data:   sha256 + "\n",
+			expect: []checksumEntry{{Name: "", Algorithm: "sha256", Digest: sha256}},
+		},
+		{
+			name:   "skips-comments-and-md5",
+			data:   "# checksums\n" + strings.Repeat("c", 32) + "  drop\n\nnot a checksum line\n",
+			expect: []checksumEntry{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, parseChecksums([]byte(tc.data)))
+		})
+	}
+}
+
+func TestChecksumFiles(t *testing.T) {
+	t.Parallel()
+	files := []string{
+		"drop-linux-amd64", "drop-linux-amd64.sha256", "drop-darwin-arm64.sha256",
+		"checksums.txt", "checksums.txt.sig", "SHA256SUMS", "SHA256SUMS.asc", "drop_1.0_checksums.txt",
+	}
+	require.Equal(t, []string{
+		"drop-linux-amd64.sha256", "checksums.txt", "SHA256SUMS", "drop_1.0_checksums.txt",
+	}, checksumFiles(files, "drop-linux-amd64"))
+}
@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package integrity verifies release artifacts of projects that publish no
+// AMPEL policies, using the checksums and signatures released along them.
+// The verification is tiered: sigstore bundles signed by the repository's
+// workflows are preferred, then minisign and GPG signatures made with keys
+// the user trusts and, as a last resort, the published checksums.
+package integrity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/jedisct1/go-minisign"
+	"github.com/sigstore/sigstore-go/pkg/root"
+)
+
+// Level is the assurance achieved when verifying an artifact
+type Level string
+
+const (
+	// LevelNone means the artifact was not verified
+	LevelNone Level = "none"
+
+	// LevelChecksum means the artifact matches the checksums published in
+	// its release. It catches corrupted downloads but not a compromised
+	// release.
+	LevelChecksum Level = "checksum"
+
+	// LevelSignature means the artifact (or its checksums) carries a
+	// minisign or GPG signature by a key the user trusts.
+	LevelSignature Level = "signature"
+
+	// LevelSigstore means the artifact (or its checksums) is signed by a
+	// workflow of its repository, as recorded in the sigstore transparency
+	// log.
+	LevelSigstore Level = "sigstore"
+
+	// LevelPolicy means the artifact passed the AMPEL policy verification
+	LevelPolicy Level = "policy"
+)
+
+// Levels lists the verification levels from the weakest to the strongest
+var Levels = []Level{LevelNone, LevelChecksum, LevelSignature, LevelSigstore, LevelPolicy}
+
+// Verification methods, the mechanism that verified an artifact
+const (
+	MethodChecksum = "checksum"
+	MethodSigstore = "sigstore"
+	MethodMinisign = "minisign"
+	MethodGPG      = "gpg"
+)
+
+var (
+	// ErrNoMaterial is returned when the release has no checksums or
+	// signatures that can verify the artifact.
+	ErrNoMaterial = errors.New("release has no checksums or signatures for the artifact")
+
+	// ErrDigestMismatch is returned when the artifact does not match its
+	// published checksum.
+	ErrDigestMismatch = errors.New("artifact does not match its published checksum")
+)
+
+// ParseLevel returns the level named by a string
+func ParseLevel(s string) (Level, error) {
+	l := Level(strings.ToLower(s))
+	if !slices.Contains(Levels, l) {
+		return "", fmt.Errorf("unknown verification level %q, valid levels are %v", s, Levels)
+	}
+	return l, nil
+}
+
+// Rank returns the position of the level in the tiers, unknown levels
+// rank below none.
+func (l Level) Rank() int {
+	return slices.Index(Levels, l)
+}
+
+// AtLeast returns true if the level is as strong as the minimum
+func (l Level) AtLeast(minimum Level) bool {
+	return l.Rank() >= minimum.Rank()
+}
+
+// Release gives access to the files published in the release of an
+// artifact.
+type Release interface {
+	// Files returns the names of the release assets
+	Files() []string
+
+	// ReadFile returns the contents of a release asset
+	ReadFile(name string) ([]byte, error)
+}
+
+// Result describes how an artifact was verified
+type Result struct {
+	Level Level
+
+	// Method is the mechanism that verified the artifact
+	Method string
+
+	// Files are the release files the verification used
+	Files []string
+
+	// Signer identifies who signed the artifact or its checksums: the
+	// certificate identity, the minisign key ID or the GPG fingerprint.
+	Signer string
+}
+
+// String returns a human readable description of the verification
+func (r *Result) String() string {
+	var s string
+	switch r.Method {
+	case MethodChecksum:
+		s = "matches the published checksums"
+	default:
+		s = r.Method + " signature"
+		if r.Signer != "" {
+			s += " by " + r.Signer
+		}
+	}
+	if len(r.Files) > 0 {
+		s += " (" + strings.Join(r.Files, ", ") + ")"
+	}
+	return s
+}
+
+// Identity returns the signer prefixed with the verification method, in
+// the format policy results use for sigstore identities. It is empty when
+// nobody signed the artifact.
+func (r *Result) Identity() string {
+	switch {
+	case r.Signer == "":
+		return ""
+	case r.Method == MethodSigstore:
+		return MethodSigstore + "::" + githubActionsIssuer + "::" + r.Signer
+	default:
+		return r.Method + "::" + r.Signer
+	}
+}
+
+// Verifier checks artifacts against the material in their release
+type Verifier struct {
+	repository   string
+	minisignKeys []minisign.PublicKey
+	gpgKeys      openpgp.EntityList
+	trusted      root.TrustedMaterial
+}
+
+type FuncOption func(*Verifier) error
+
+// WithRepository sets the URL of the repository publishing the artifacts.
+// Sigstore signatures are only accepted from its workflows.
+func WithRepository(url string) FuncOption {
+	return func(v *Verifier) error {
+		v.repository = strings.TrimSuffix(url, "/")
+		return nil
+	}
+}
+
+// WithMinisignKeys adds trusted minisign public keys, either the bare
+// base64 key or the contents of a minisign .pub file.
+func WithMinisignKeys(keys ...string) FuncOption {
+	return func(v *Verifier) error {
+		for _, k := range keys {
+			pk, err := parseMinisignKey(k)
+			if err != nil {
+				return err
+			}
+			v.minisignKeys = append(v.minisignKeys, pk)
+		}
+		return nil
+	}
+}
+
+// WithGPGKeys adds trusted GPG public keys, armored or binary
+func WithGPGKeys(keys ...[]byte) FuncOption {
+	return func(v *Verifier) error {
+		for _, k := range keys {
+			entities, err := ReadKeyRing(k)
+			if err != nil {
+				return err
+			}
+			v.gpgKeys = append(v.gpgKeys, entities...)
+		}
+		return nil
+	}
+}
+
+// WithTrustedMaterial sets the sigstore trust root. When not set, the root
+// of the public good instance is fetched when a bundle is first verified.
+func WithTrustedMaterial(tm root.TrustedMaterial) FuncOption {
+	return func(v *Verifier) error {
+		v.trusted = tm
+		return nil
+	}
+}
+
+// New returns a verifier configured with the options
+func New(funcs ...FuncOption) (*Verifier, error) {
+	v := &Verifier{}
+	for _, fn := range funcs {
+		if err := fn(v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// subject is a file that can carry signatures: the artifact itself or a
+// checksums file listing it.
+type subject struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+// Verify checks an artifact, downloaded to path, with the strongest
+// material found in its release. Signatures are tried on the artifact and
+// on the checksum files listing it. A checksum mismatch or an invalid
+// signature fails the verification instead of falling to a weaker tier.
+// When nothing in the release covers the artifact, ErrNoMaterial is
+// returned.
+func (v *Verifier) Verify(release Release, name, path string) (*Result, error) {
+	files := release.Files()
+	digests := newFileDigests(path)
+
+	subjects := []subject{{name: name, open: digests.open}}
+	sums, err := matchChecksums(release, files, name, digests)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range sums {
+		subjects = append(subjects, subject{name: f, open: func() (io.ReadCloser, error) {
+			data, err := release.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}})
+	}
+
+	for _, check := range []signatureCheck{v.checkSigstore, v.checkMinisign, v.checkGPG} {
+		for _, s := range subjects {
+			res, err := check(release, files, s)
+			if err != nil {
+				return nil, fmt.Errorf("verifying signature of %s: %w", s.name, err)
+			}
+			if res != nil {
+				if s.name != name {
+					res.Files = append([]string{s.name}, res.Files...)
+				}
+				return res, nil
+			}
+		}
+	}
+
+	if len(sums) > 0 {
+		return &Result{Level: LevelChecksum, Method: MethodChecksum, Files: sums}, nil
+	}
+	return nil, ErrNoMaterial
+}
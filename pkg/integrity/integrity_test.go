@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package integrity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/jedisct1/go-minisign"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelease serves release files from memory
+type fakeRelease map[string][]byte
+
+func (r fakeRelease) Files() []string {
+	ret := []string{}
+	for name := range r {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+func (r fakeRelease) ReadFile(name string) ([]byte, error) {
+	data, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("no file %s in release", name)
+	}
+	return data, nil
+}
+
+// newMinisignKey returns a minisign key pair, the public key encoded as
+// in .pub files.
+func newMinisignKey(t *testing.T) (sk *minisign.PrivateKey, pub string) {
+	t.Helper()
+	edPub, edPriv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	sk = &minisign.PrivateKey{SignatureAlgorithm: [2]byte{'E', 'd'}, KeyId: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}
+	copy(sk.SecretKey[:], edPriv)
+	raw := append([]byte("Ed"), sk.KeyId[:]...)
+	raw = append(raw, edPub...)
+	return sk, "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw)
+}
+
+func minisignSign(t *testing.T, sk *minisign.PrivateKey, data []byte) []byte {
+	t.Helper()
+	sig, err := sk.Sign(data, minisign.SignOptions{Hashed: true})
+	require.NoError(t, err)
+	return sig.Encode()
+}
+
+// newGPGKey returns a GPG entity and its armored public key
+func newGPGKey(t *testing.T) (entity *openpgp.Entity, pub []byte) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return entity, buf.Bytes()
+}
+
+func gpgSign(t *testing.T, entity *openpgp.Entity, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(data), nil))
+	return buf.Bytes()
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+	l, err := ParseLevel("Signature")
+	require.NoError(t, err)
+	require.Equal(t, LevelSignature, l)
+	_, err = ParseLevel("trusted")
+	require.Error(t, err)
+
+	require.True(t, LevelPolicy.AtLeast(LevelSigstore))
+	require.True(t, LevelChecksum.AtLeast(LevelChecksum))
+	require.False(t, LevelChecksum.AtLeast(LevelSignature))
+	require.False(t, Level("").AtLeast(LevelNone))
+}
+
+func TestResultIdentity(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		result Result
+		expect string
+	}{
+		{name: "checksum", result: Result{Method: MethodChecksum}, expect: ""},
+		{name: "minisign", result: Result{Method: MethodMinisign, Signer: "0807060504030201"}, expect: "minisign::0807060504030201"},
+		{
+			name:   "sigstore",
+			result: Result{Method: MethodSigstore, Signer: "https://github.com/org/repo/.github/workflows/release.yml@refs/tags/v1.0.0"},
+			expect: "sigstore::https://token.actions.githubusercontent.com::https://github.com/org/repo/.github/workflows/release.yml@refs/tags/v1.0.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, tc.result.Identity())
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	artifact := []byte("drop binary contents")
+	path := filepath.Join(t.TempDir(), "drop")
+	require.NoError(t, os.WriteFile(path, artifact, 0o600))
+	sum := sha256.Sum256(artifact)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  drop-linux-amd64\n" + hex.EncodeToString(make([]byte, 32)) + "  other\n")
+
+	sk, minisignPub := newMinisignKey(t)
+	otherSK, _ := newMinisignKey(t)
+	otherSK.KeyId = [8]byte{8, 7, 6, 5, 4, 3, 2, 1}
+	entity, gpgPub := newGPGKey(t)
+	otherEntity, _ := newGPGKey(t)
+
+	for _, tc := range []struct {
+		name    string
+		release fakeRelease
+		opts    []FuncOption
+		level   Level
+		method  string
+		files   []string
+		mustErr bool
+		errIs   error
+	}{
+		{name: "no-material", release: fakeRelease{"README.md": nil}, mustErr: true, errIs: ErrNoMaterial},
+		{
+			name: "checksums", release: fakeRelease{"checksums.txt": checksums},
+			level: LevelChecksum, method: MethodChecksum, files: []string{"checksums.txt"},
+		},
+		{
+			name:    "checksum-mismatch",
+			release: fakeRelease{"drop-linux-amd64.sha256": []byte(hex.EncodeToString(make([]byte, 32)))},
+			mustErr: true, errIs: ErrDigestMismatch,
+		},
+		{
+			name: "signatures-without-keys",
+			release: fakeRelease{
+				"checksums.txt": checksums, "checksums.txt.minisig": minisignSign(t, sk, checksums),
+			},
+			level: LevelChecksum, method: MethodChecksum, files: []string{"checksums.txt"},
+		},
+		{
+			name:    "minisign-artifact",
+			release: fakeRelease{"drop-linux-amd64.minisig": minisignSign(t, sk, artifact)},
+			opts:    []FuncOption{WithMinisignKeys(minisignPub)},
+			level:   LevelSignature, method: MethodMinisign, files: []string{"drop-linux-amd64.minisig"},
+		},
+		{
+			name: "minisign-checksums",
+			release: fakeRelease{
+				"checksums.txt": checksums, "checksums.txt.minisig": minisignSign(t, sk, checksums),
+			},
+			opts:  []FuncOption{WithMinisignKeys(minisignPub)},
+			level: LevelSignature, method: MethodMinisign, files: []string{"checksums.txt", "checksums.txt.minisig"},
+		},
+		{
+			name:    "minisign-tampered",
+			release: fakeRelease{"drop-linux-amd64.minisig": minisignSign(t, sk, []byte("other"))},
+			opts:    []FuncOption{WithMinisignKeys(minisignPub)},
+			mustErr: true,
+		},
+		{
+			name: "minisign-untrusted-key",
+			release: fakeRelease{
+				"checksums.txt": checksums, "checksums.txt.minisig": minisignSign(t, otherSK, checksums),
+			},
+			opts:  []FuncOption{WithMinisignKeys(minisignPub)},
+			level: LevelChecksum, method: MethodChecksum, files: []string{"checksums.txt"},
+		},
+		{
+			name: "gpg-checksums",
+			release: fakeRelease{
+				"SHA256SUMS": checksums, "SHA256SUMS.asc": gpgSign(t, entity, checksums),
+			},
+			opts:  []FuncOption{WithGPGKeys(gpgPub)},
+			level: LevelSignature, method: MethodGPG, files: []string{"SHA256SUMS", "SHA256SUMS.asc"},
+		},
+		{
+			name:    "gpg-tampered",
+			release: fakeRelease{"drop-linux-amd64.asc": gpgSign(t, entity, []byte("other"))},
+			opts:    []FuncOption{WithGPGKeys(gpgPub)},
+			mustErr: true,
+		},
+		{
+			name: "gpg-untrusted-key",
+			release: fakeRelease{
+				"SHA256SUMS": checksums, "SHA256SUMS.asc": gpgSign(t, otherEntity, checksums),
+			},
+			opts:  []FuncOption{WithGPGKeys(gpgPub)},
+			level: LevelChecksum, method: MethodChecksum, files: []string{"SHA256SUMS"},
+		},
+		{
+			name: "not-a-gpg-signature",
+			release: fakeRelease{
+				"checksums.txt": checksums, "checksums.txt.sig": []byte("MEUCIQDxyz=="),
+			},
+			opts:  []FuncOption{WithGPGKeys(gpgPub)},
+			level: LevelChecksum, method: MethodChecksum, files: []string{"checksums.txt"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := New(tc.opts...)
+			require.NoError(t, err)
+			res, err := v.Verify(tc.release, "drop-linux-amd64", path)
+			if tc.mustErr {
+				require.Error(t, err)
+				if tc.errIs != nil {
+					require.ErrorIs(t, err, tc.errIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.level, res.Level)
+			require.Equal(t, tc.method, res.Method)
+			require.Equal(t, tc.files, res.Files)
+		})
+	}
+}
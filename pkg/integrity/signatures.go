@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package integrity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/jedisct1/go-minisign"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sirupsen/logrus"
+)
+
+// githubActionsIssuer is the OIDC issuer of the certificates signed by
+// GitHub Actions workflows.
+const githubActionsIssuer = "https://token.actions.githubusercontent.com"
+
+// Signature file extensions of each method
+var (
+	sigstoreSuffixes = []string{".sigstore.json", ".sigstore", ".bundle"}
+	minisignSuffixes = []string{".minisig"}
+	gpgSuffixes      = []string{".asc", ".sig", ".gpg"}
+)
+
+// signatureCheck verifies the signatures of a subject with one method. It
+// returns nil when the release has no signature it can check.
+type signatureCheck func(release Release, files []string, s subject) (*Result, error)
+
+// signatureFiles returns the release files named after the subject with
+// one of the suffixes.
+func signatureFiles(files []string, name string, suffixes []string) []string {
+	ret := []string{}
+	for _, f := range files {
+		rest, ok := strings.CutPrefix(f, name)
+		if !ok {
+			continue
+		}
+		for _, s := range suffixes {
+			if strings.EqualFold(rest, s) {
+				ret = append(ret, f)
+			}
+		}
+	}
+	return ret
+}
+
+// trustedMaterial returns the sigstore trust root, fetching the one of the
+// public good instance on first use.
+func (v *Verifier) trustedMaterial() (root.TrustedMaterial, error) {
+	if v.trusted != nil {
+		return v.trusted, nil
+	}
+	tr, err := root.FetchTrustedRoot()
+	if err != nil {
+		return nil, fmt.Errorf("fetching sigstore trusted root: %w", err)
+	}
+	v.trusted = tr
+	return v.trusted, nil
+}
+
+// checkSigstore verifies the sigstore bundles of a subject. The signing
+// certificate must be issued to a GitHub Actions workflow building from the
+// artifact's repository.
+func (v *Verifier) checkSigstore(release Release, files []string, s subject) (*Result, error) {
+	if v.repository == "" {
+		return nil, nil
+	}
+	for _, f := range signatureFiles(files, s.name, sigstoreSuffixes) {
+		data, err := release.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		b := &bundle.Bundle{}
+		if err := b.UnmarshalJSON(data); err != nil {
+			logrus.Debugf("skipping %s, not a sigstore bundle: %v", f, err)
+			continue
+		}
+
+		tm, err := v.trustedMaterial()
+		if err != nil {
+			return nil, err
+		}
+		sev, err := verify.NewVerifier(
+			tm, verify.WithSignedCertificateTimestamps(1),
+			verify.WithTransparencyLog(1), verify.WithObserverTimestamps(1),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating sigstore verifier: %w", err)
+		}
+		san, err := verify.NewSANMatcher("", "^https://")
+		if err != nil {
+			return nil, err
+		}
+		issuer, err := verify.NewIssuerMatcher(githubActionsIssuer, "")
+		if err != nil {
+			return nil, err
+		}
+		identity, err := verify.NewCertificateIdentity(san, issuer, certificate.Extensions{
+			SourceRepositoryURI: v.repository,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := s.open()
+		if err != nil {
+			return nil, err
+		}
+		res, err := sev.Verify(b, verify.NewPolicy(verify.WithArtifact(r), verify.WithCertificateIdentity(identity)))
+		_ = r.Close() //nolint:errcheck
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+
+		signer := ""
+		if res.Signature != nil && res.Signature.Certificate != nil {
+			signer = res.Signature.Certificate.SubjectAlternativeName
+		}
+		return &Result{Level: LevelSigstore, Method: MethodSigstore, Files: []string{f}, Signer: signer}, nil
+	}
+	return nil, nil
+}
+
+// parseMinisignKey reads a minisign public key, bare or in the .pub file
+// format with its comment line.
+func parseMinisignKey(key string) (minisign.PublicKey, error) {
+	key = strings.TrimSpace(key)
+	var pk minisign.PublicKey
+	var err error
+	if strings.Contains(key, "\n") {
+		pk, err = minisign.DecodePublicKey(key)
+	} else {
+		pk, err = minisign.NewPublicKey(key)
+	}
+	if err != nil {
+		return pk, fmt.Errorf("parsing minisign public key: %w", err)
+	}
+	return pk, nil
+}
+
+// checkMinisign verifies the minisign signatures of a subject made by one
+// of the trusted keys. Signatures by unknown keys are ignored.
+func (v *Verifier) checkMinisign(release Release, files []string, s subject) (*Result, error) {
+	if len(v.minisignKeys) == 0 {
+		return nil, nil
+	}
+	for _, f := range signatureFiles(files, s.name, minisignSuffixes) {
+		data, err := release.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		sig, err := minisign.DecodeSignature(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", f, err)
+		}
+		for _, pk := range v.minisignKeys {
+			if pk.KeyId != sig.KeyId {
+				continue
+			}
+			r, err := s.open()
+			if err != nil {
+				return nil, err
+			}
+			contents, err := io.ReadAll(r)
+			_ = r.Close() //nolint:errcheck
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", s.name, err)
+			}
+			if _, err := pk.Verify(contents, sig); err != nil {
+				return nil, fmt.Errorf("%s: %w", f, err)
+			}
+			return &Result{
+				Level: LevelSignature, Method: MethodMinisign, Files: []string{f},
+				Signer: fmt.Sprintf("%X", keyIDBytes(pk.KeyId)),
+			}, nil
+		}
+		logrus.Debugf("skipping %s, signed by an untrusted key", f)
+	}
+	return nil, nil
+}
+
+// keyIDBytes returns the minisign key ID in the byte order minisign
+// prints it.
+func keyIDBytes(id [8]byte) []byte {
+	ret := make([]byte, len(id))
+	for i := range id {
+		ret[i] = id[len(id)-1-i]
+	}
+	return ret
+}
+
+// ReadKeyRing reads GPG public keys, armored or binary
+func ReadKeyRing(data []byte) (openpgp.EntityList, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading GPG public keys: %w", err)
+	}
+	return entities, nil
+}
+
+// checkGPG verifies the detached GPG signatures of a subject made by one of
+// the trusted keys. Signatures by unknown keys are ignored.
+func (v *Verifier) checkGPG(release Release, files []string, s subject) (*Result, error) {
+	if len(v.gpgKeys) == 0 {
+		return nil, nil
+	}
+	for _, f := range signatureFiles(files, s.name, gpgSuffixes) {
+		data, err := release.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		r, err := s.open()
+		if err != nil {
+			return nil, err
+		}
+		var signer *openpgp.Entity
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+			signer, err = openpgp.CheckArmoredDetachedSignature(v.gpgKeys, r, bytes.NewReader(data), nil)
+		} else {
+			signer, err = openpgp.CheckDetachedSignature(v.gpgKeys, r, bytes.NewReader(data), nil)
+		}
+		_ = r.Close() //nolint:errcheck
+		if err != nil {
+			// Files that are not GPG signatures (.sig is shared with other
+			// tools) or signed by unknown keys don't count against the subject
+			var structural pgperrors.StructuralError
+			var unsupported pgperrors.UnsupportedError
+			if errors.Is(err, pgperrors.ErrUnknownIssuer) || errors.As(err, &structural) || errors.As(err, &unsupported) {
+				logrus.Debugf("skipping %s: %v", f, err)
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		return &Result{
+			Level: LevelSignature, Method: MethodGPG, Files: []string{f},
+			Signer: fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint),
+		}, nil
+	}
+	return nil, nil
+}
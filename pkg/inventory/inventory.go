@@ -2,8 +2,9 @@
 // SPDX-License-Identifier: Apache-2.0
 
 // Package inventory keeps track of the artifacts drop installs in the local
-// system. The data lives in a single versioned JSON document in the user's
-// configuration directory and records, for every installed app, what was
+// system. The data lives in versioned JSON documents, one in the user's
+// configuration directory and a system-wide one for the apps installed in
+// system locations, and records, for every installed app, what was
 // installed and enough metadata to later verify, update or uninstall it.
 package inventory
 
@@ -13,23 +14,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // Version is the current schema version of the inventory file.
-const Version = 1
+const Version = 2
 
 const dirName = "drop"
 
 // FileName is the name of the inventory database file.
 const FileName = "installed.json"
 
+// Verification levels the inventory looks at, the names of the levels
+// defined in the integrity package. Records keep them as plain strings so
+// the inventory does not depend on the verification code.
+const (
+	levelNone   = "none"
+	levelPolicy = "policy"
+)
+
 // Inventory is the database of artifacts installed by drop.
 type Inventory struct {
 	Version  int                `json:"version"`
 	Installs map[string]*Record `json:"installs"`
 
 	path string
+
+	// migratedFrom is the version the file was upgraded from when loaded
+	// and original its contents, backed up before the first rewrite.
+	migratedFrom int
+	original     []byte
 }
 
 // Record stores the data of one installed app.
@@ -55,19 +70,66 @@ type Record struct {
 	// manager and the digest ties the record to the verified file.
 	Digest map[string]string `json:"digest,omitempty"`
 
-	// BinPath is the path where the binary was installed (binaries only).
+	// BinPath is the path where the binary (or AppImage) was installed.
 	BinPath string `json:"binPath,omitempty"`
 
 	// PackageFormat is the package type handed to the package manager
 	// (packages only).
 	PackageFormat string `json:"packageFormat,omitempty"`
 
-	// Verified records if the artifact passed policy verification or was
-	// installed with verification disabled.
-	Verified bool `json:"verified"`
+	// PackageName, PackageVersion and PackageArch are the package identity
+	// read from the package metadata (packages only). The name is the one
+	// the package manager knows, used to query and remove the package.
+	PackageName    string `json:"packageName,omitempty"`
+	PackageVersion string `json:"packageVersion,omitempty"`
+	PackageArch    string `json:"packageArch,omitempty"`
+
+	// Scope is the install scope, user or system
+	Scope string `json:"scope,omitempty"`
+
+	// Prefix is the user directory the app was installed into without
+	// the package manager. Files lists what was unpacked there from a
+	// package, to remove it cleanly, and Dirs the directories created for
+	// them, removed when left empty.
+	Prefix string   `json:"prefix,omitempty"`
+	Files  []string `json:"files,omitempty"`
+	Dirs   []string `json:"dirs,omitempty"`
+
+	// Fallback is the arch of the installed artifact when it was chosen
+	// from the platform's fallback chain instead of a native variant.
+	Fallback string `json:"fallback,omitempty"`
+
+	// Emulated is true when the fallback arch runs through emulation.
+	Emulated bool `json:"emulated,omitempty"`
+
+	// VerificationLevel records how the artifact was verified, from a
+	// policy evaluation down to none when verification was disabled.
+	VerificationLevel string `json:"verificationLevel"`
+
+	// Signers are the identities that signed the app (or its attestations)
+	// when it was first installed. Updates signed by someone else are
+	// flagged until the user trusts the new identity.
+	Signers []string `json:"signers,omitempty"`
+
+	// PolicyRepository, PolicyRef and PolicyCommit record the revision of
+	// the policy repository the app was verified with: the ref it was
+	// pinned to, if any, and the commit it resolved to.
+	PolicyRepository string `json:"policyRepository,omitempty"`
+	PolicyRef        string `json:"policyRef,omitempty"`
+	PolicyCommit     string `json:"policyCommit,omitempty"`
+
+	// SBOM is the release file of the SBOM stored with the app, SBOMFormat
+	// its format (spdx or cyclonedx). SBOMAttested is true when the SBOM
+	// matched the subject of an attestation published in the release.
+	SBOM         string `json:"sbom,omitempty"`
+	SBOMFormat   string `json:"sbomFormat,omitempty"`
+	SBOMAttested bool   `json:"sbomAttested,omitempty"`
 
 	InstalledAt time.Time `json:"installedAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// inventory is the file the record was loaded from by Installed
+	inventory string
 }
 
 // Key returns the string keying the record in the inventory.
@@ -75,8 +137,51 @@ func (r *Record) Key() string {
 	return fmt.Sprintf("%s/%s/%s#%s", r.Host, r.Org, r.Repo, r.Name)
 }
 
-// DefaultPath returns the location of the inventory database in the user's
-// configuration directory.
+// Unverified returns true if the installed artifact was not verified.
+func (r *Record) Unverified() bool {
+	return r.VerificationLevel == "" || r.VerificationLevel == levelNone
+}
+
+// Filter selects records by their attributes. Empty fields match every
+// record.
+type Filter struct {
+	// Org matches the organization of the app's repository, ignoring case
+	Org string
+
+	// Kind matches the artifact type installed
+	Kind string
+
+	// Unverified only matches apps installed without verification
+	Unverified bool
+}
+
+// Matches returns true if a record passes the filter.
+func (f *Filter) Matches(r *Record) bool {
+	switch {
+	case f.Org != "" && !strings.EqualFold(f.Org, r.Org):
+		return false
+	case f.Kind != "" && f.Kind != r.Kind:
+		return false
+	case f.Unverified && !r.Unverified():
+		return false
+	default:
+		return true
+	}
+}
+
+// Apply returns the records passing the filter.
+func (f *Filter) Apply(records []*Record) []*Record {
+	ret := []*Record{}
+	for _, r := range records {
+		if f.Matches(r) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// DefaultPath returns the location of the user inventory database in the
+// user's configuration directory.
 func DefaultPath() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
@@ -96,7 +201,9 @@ func Open() (*Inventory, error) {
 }
 
 // OpenFile loads the inventory from a file, returning an empty inventory
-// bound to the path if the file does not exist yet.
+// bound to the path if the file does not exist yet. Files of older schema
+// versions are migrated, the original is backed up when the inventory is
+// first saved.
 func OpenFile(path string) (*Inventory, error) {
 	inv := &Inventory{
 		Version:  Version,
@@ -112,12 +219,16 @@ func OpenFile(path string) (*Inventory, error) {
 		return nil, fmt.Errorf("reading inventory: %w", err)
 	}
 
-	if err := json.Unmarshal(data, inv); err != nil {
+	migrated, version, err := Migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(migrated, inv); err != nil {
 		return nil, fmt.Errorf("parsing inventory: %w", err)
 	}
-
-	if inv.Version > Version {
-		return nil, fmt.Errorf("inventory version %d is newer than the supported version %d", inv.Version, Version)
+	if version != Version {
+		inv.migratedFrom = version
+		inv.original = data
 	}
 
 	if inv.Installs == nil {
@@ -127,16 +238,24 @@ func OpenFile(path string) (*Inventory, error) {
 }
 
 // Save atomically writes the inventory back to the file it was loaded from.
+// Changes based on a previous read should go through Update instead, which
+// keeps other drop processes from writing in between.
 func (inv *Inventory) Save() error {
 	if inv.path == "" {
 		return errors.New("inventory is not bound to a file")
 	}
 
 	dir := filepath.Dir(inv.path)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
+	if err := os.MkdirAll(dir, dirMode(inv.path)); err != nil {
 		return fmt.Errorf("creating inventory directory: %w", err)
 	}
 
+	if inv.migratedFrom != 0 {
+		if err := inv.backup(); err != nil {
+			return err
+		}
+	}
+
 	data, err := json.MarshalIndent(inv, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling inventory: %w", err)
@@ -147,6 +266,15 @@ func (inv *Inventory) Save() error {
 		return fmt.Errorf("creating temporary file: %w", err)
 	}
 
+	// The system inventory is readable by every user
+	if shared(inv.path) {
+		if err := tmp.Chmod(0o644); err != nil {
+			_ = tmp.Close()           //nolint:errcheck
+			_ = os.Remove(tmp.Name()) //nolint:errcheck
+			return fmt.Errorf("setting inventory permissions: %w", err)
+		}
+	}
+
 	if _, err := tmp.Write(data); err != nil {
 		_ = tmp.Close()           //nolint:errcheck
 		_ = os.Remove(tmp.Name()) //nolint:errcheck
@@ -161,6 +289,31 @@ func (inv *Inventory) Save() error {
 		_ = os.Remove(tmp.Name()) //nolint:errcheck
 		return fmt.Errorf("replacing inventory: %w", err)
 	}
+	inv.migratedFrom = 0
+	inv.original = nil
+	return nil
+}
+
+// Writable checks that the current user can update an inventory file: take
+// its lock and replace it. The directory and lock file are created if
+// missing, as the first update would.
+func Writable(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirMode(path)); err != nil {
+		return fmt.Errorf("creating inventory directory: %w", err)
+	}
+	l, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0o600) //nolint:gosec // the path is the inventory's
+	if err != nil {
+		return fmt.Errorf("opening inventory lock: %w", err)
+	}
+	_ = l.Close() //nolint:errcheck
+
+	tmp, err := os.CreateTemp(dir, ".installed-*.json")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	_ = tmp.Close()           //nolint:errcheck
+	_ = os.Remove(tmp.Name()) //nolint:errcheck
 	return nil
 }
 
@@ -10,20 +10,22 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/integrity"
 )
 
 func testRecord() *Record {
 	return &Record{
-		Host:     "github.com",
-		Org:      "carabiner-dev",
-		Repo:     "drop",
-		Name:     "drop",
-		Version:  "v0.1.0",
-		Kind:     "binary",
-		Asset:    "drop-v0.1.0-linux-amd64",
-		Digest:   map[string]string{"sha256": "abc123"},
-		BinPath:  "/usr/local/bin/drop",
-		Verified: true,
+		Host:              "github.com",
+		Org:               "carabiner-dev",
+		Repo:              "drop",
+		Name:              "drop",
+		Version:           "v0.1.0",
+		Kind:              "binary",
+		Asset:             "drop-v0.1.0-linux-amd64",
+		Digest:            map[string]string{"sha256": "abc123"},
+		BinPath:           "/usr/local/bin/drop",
+		VerificationLevel: "policy",
 	}
 }
 
@@ -32,6 +34,12 @@ func TestRecordKey(t *testing.T) {
 	require.Equal(t, "github.com/carabiner-dev/drop#drop", testRecord().Key())
 }
 
+func TestLevelNames(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, string(integrity.LevelNone), levelNone)
+	require.Equal(t, string(integrity.LevelPolicy), levelPolicy)
+}
+
 func TestOpenFileMissing(t *testing.T) {
 	t.Parallel()
 	path := filepath.Join(t.TempDir(), "installed.json")
@@ -59,7 +67,7 @@ func TestRoundTrip(t *testing.T) {
 	require.Equal(t, record.Name, got.Name)
 	require.Equal(t, record.Digest, got.Digest)
 	require.Equal(t, record.BinPath, got.BinPath)
-	require.True(t, got.Verified)
+	require.Equal(t, "policy", got.VerificationLevel)
 	require.False(t, got.InstalledAt.IsZero())
 	require.False(t, got.UpdatedAt.IsZero())
 }
@@ -93,6 +101,22 @@ func TestRemove(t *testing.T) {
 	require.False(t, inv.Remove(record.Key()))
 }
 
+func TestWritable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "drop", FileName)
+	require.NoError(t, Writable(path))
+	require.FileExists(t, lockPath(path))
+	require.NoFileExists(t, path)
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := filepath.Join(dir, "ro")
+	require.NoError(t, os.Mkdir(readOnly, 0o500))
+	require.Error(t, Writable(filepath.Join(readOnly, "drop", FileName)))
+}
+
 func TestOpenFileNewerVersion(t *testing.T) {
 	t.Parallel()
 	path := filepath.Join(t.TempDir(), "installed.json")
@@ -102,6 +126,21 @@ func TestOpenFileNewerVersion(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestOpenFileUpgradeV1(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "installed.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "installs": {
+		"github.com/a/b#b": {"host": "github.com", "org": "a", "repo": "b", "name": "b", "verified": true},
+		"github.com/a/c#c": {"host": "github.com", "org": "a", "repo": "c", "name": "c", "verified": false}
+	}}`), 0o600))
+
+	inv, err := OpenFile(path)
+	require.NoError(t, err)
+	require.Equal(t, Version, inv.Version)
+	require.Equal(t, "policy", inv.Get("github.com/a/b#b").VerificationLevel)
+	require.Equal(t, "none", inv.Get("github.com/a/c#c").VerificationLevel)
+}
+
 func TestOpenFileCorrupt(t *testing.T) {
 	t.Parallel()
 	path := filepath.Join(t.TempDir(), "installed.json")
@@ -110,3 +149,35 @@ func TestOpenFileCorrupt(t *testing.T) {
 	_, err := OpenFile(path)
 	require.Error(t, err)
 }
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	verified := testRecord()
+	unverified := testRecord()
+	unverified.Name = "unverified"
+	unverified.VerificationLevel = "none"
+	pkg := testRecord()
+	pkg.Name = "pkg"
+	pkg.Org = "other"
+	pkg.Kind = "package"
+	pkg.VerificationLevel = ""
+	records := []*Record{verified, unverified, pkg}
+
+	for _, tc := range []struct {
+		name   string
+		filter Filter
+		expect []*Record
+	}{
+		{"empty", Filter{}, records},
+		{"org", Filter{Org: "Carabiner-Dev"}, []*Record{verified, unverified}},
+		{"kind", Filter{Kind: "package"}, []*Record{pkg}},
+		{"unverified", Filter{Unverified: true}, []*Record{unverified, pkg}},
+		{"combined", Filter{Org: "carabiner-dev", Unverified: true}, []*Record{unverified}},
+		{"no-match", Filter{Kind: "appimage"}, []*Record{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, tc.filter.Apply(records))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockTimeout is how long Update waits for other drop processes to release
+// the inventory.
+const LockTimeout = 30 * time.Second
+
+// lockRetry is the interval between attempts to take a held lock
+const lockRetry = 100 * time.Millisecond
+
+var ErrLockTimeout = errors.New("timed out waiting for the inventory lock")
+
+// fileLock is an advisory lock on the inventory. It is held on a sidecar
+// file next to the inventory, as Save replaces the inventory file itself.
+// The operating system releases it if the process dies.
+type fileLock struct {
+	f *os.File
+}
+
+// lockPath returns the path of the lock file of an inventory
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+// acquireLock takes the exclusive lock of an inventory file, waiting up to
+// the timeout for other processes holding it.
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirMode(path)); err != nil {
+		return nil, fmt.Errorf("creating inventory directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0o600) //nolint:gosec // the path is the inventory's
+	if err != nil {
+		return nil, fmt.Errorf("opening inventory lock: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close() //nolint:errcheck
+			return nil, fmt.Errorf("locking inventory: %w", err)
+		}
+		if locked {
+			return &fileLock{f: f}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close() //nolint:errcheck
+			return nil, fmt.Errorf("%w after %s (%s)", ErrLockTimeout, timeout, lockPath(path))
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// release frees the lock
+func (l *fileLock) release() error {
+	if err := unlock(l.f); err != nil {
+		_ = l.f.Close() //nolint:errcheck
+		return fmt.Errorf("unlocking inventory: %w", err)
+	}
+	return l.f.Close()
+}
+
+// Update runs a read-modify-write cycle on the inventory in its default
+// location while holding its lock, so concurrent drop processes do not
+// overwrite each other's changes. The inventory is saved unless fn returns
+// an error.
+func Update(fn func(*Inventory) error) error {
+	path, err := DefaultPath()
+	if err != nil {
+		return err
+	}
+	return UpdateFile(path, fn)
+}
+
+// UpdateFile runs a locked read-modify-write cycle on an inventory file,
+// see Update.
+func UpdateFile(path string, fn func(*Inventory) error) error {
+	return updateFile(path, LockTimeout, fn)
+}
+
+func updateFile(path string, timeout time.Duration, fn func(*Inventory) error) (err error) {
+	l, err := acquireLock(path, timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, l.release())
+	}()
+
+	inv, err := OpenFile(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(inv); err != nil {
+		return err
+	}
+	return inv.Save()
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateFileConcurrent(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "inventory", "installed.json")
+
+	// Every writer reads, modifies and saves. Without the lock, the last
+	// writer would drop the records of the others.
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range writers {
+		wg.Go(func() {
+			errs[i] = UpdateFile(path, func(inv *Inventory) error {
+				record := testRecord()
+				record.Name = fmt.Sprintf("app%d", i)
+				inv.Add(record)
+				return nil
+			})
+		})
+	}
+	wg.Wait()
+	require.NoError(t, errors.Join(errs...))
+
+	inv, err := OpenFile(path)
+	require.NoError(t, err)
+	require.Len(t, inv.Installs, writers)
+}
+
+func TestUpdateFileError(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "installed.json")
+	require.NoError(t, UpdateFile(path, func(inv *Inventory) error {
+		inv.Add(testRecord())
+		return nil
+	}))
+
+	// Changes are not saved when the function fails
+	fail := errors.New("failed")
+	err := UpdateFile(path, func(inv *Inventory) error {
+		inv.Remove(testRecord().Key())
+		return fail
+	})
+	require.ErrorIs(t, err, fail)
+
+	inv, err := OpenFile(path)
+	require.NoError(t, err)
+	require.NotNil(t, inv.Get(testRecord().Key()))
+}
+
+func TestUpdateFileTimeout(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "installed.json")
+
+	held, err := acquireLock(path, time.Second)
+	require.NoError(t, err)
+
+	called := false
+	err = updateFile(path, 3*lockRetry, func(*Inventory) error {
+		called = true
+		return nil
+	})
+	require.ErrorIs(t, err, ErrLockTimeout)
+	require.False(t, called)
+
+	require.NoError(t, held.release())
+	require.NoError(t, updateFile(path, 3*lockRetry, func(*Inventory) error { return nil }))
+}
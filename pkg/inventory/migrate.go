@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Migration upgrades inventory documents from one schema version to the
+// next. Migrations work on the generic JSON document so they do not depend
+// on the current shape of the Go types.
+type Migration struct {
+	// From is the version the migration upgrades, to From+1
+	From int
+
+	// Description tells what the migration changes
+	Description string
+
+	migrate func(doc map[string]any) error
+}
+
+// migrations is the upgrade chain, one migration per version up to the
+// current one. Bumping Version requires adding its migration here and a
+// fixture of the previous version in testdata.
+var migrations = []Migration{
+	{
+		From:        1,
+		Description: "replace the verified flag of records with their verification level",
+		migrate:     migrateV1,
+	},
+}
+
+// MigrationsFrom returns the migrations upgrading a document from a version
+// to the current one.
+func MigrationsFrom(version int) []Migration {
+	ret := []Migration{}
+	for _, m := range migrations {
+		if m.From >= version && m.From < Version {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// BackupPath returns where the original of an inventory file is saved
+// before it is first rewritten after migrating it from a version.
+func BackupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, version)
+}
+
+// Migrate upgrades an inventory document to the current schema version. It
+// returns the upgraded document and the version it was read as. Documents
+// already current are returned as they are.
+func Migrate(data []byte) ([]byte, int, error) {
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("parsing inventory: %w", err)
+	}
+
+	// Documents written before the version field are version 1
+	version := 1
+	if v, ok := doc["version"].(float64); ok && int(v) > 1 {
+		version = int(v)
+	}
+	if version > Version {
+		return nil, 0, fmt.Errorf("inventory version %d is newer than the supported version %d", version, Version)
+	}
+	if version == Version {
+		return data, version, nil
+	}
+
+	for _, m := range MigrationsFrom(version) {
+		if err := m.migrate(doc); err != nil {
+			return nil, 0, fmt.Errorf("migrating inventory from version %d: %w", m.From, err)
+		}
+		doc["version"] = m.From + 1
+	}
+	if doc["version"] != Version {
+		return nil, 0, fmt.Errorf("no migration path from inventory version %d", version)
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshaling migrated inventory: %w", err)
+	}
+	return out, version, nil
+}
+
+// MigratedFrom returns the schema version the inventory was upgraded from
+// when it was loaded, or 0 if it was already current.
+func (inv *Inventory) MigratedFrom() int {
+	return inv.migratedFrom
+}
+
+// backup writes the original document of a migrated inventory next to it.
+// An existing backup of the same version is kept.
+func (inv *Inventory) backup() error {
+	f, err := os.OpenFile(BackupPath(inv.path, inv.migratedFrom), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil
+		}
+		return fmt.Errorf("creating inventory backup: %w", err)
+	}
+	if _, err := f.Write(inv.original); err != nil {
+		_ = f.Close() //nolint:errcheck
+		return fmt.Errorf("writing inventory backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing inventory backup: %w", err)
+	}
+	return nil
+}
+
+// records returns the installs of a generic inventory document
+func records(doc map[string]any) (map[string]map[string]any, error) {
+	ret := map[string]map[string]any{}
+	installs, ok := doc["installs"].(map[string]any)
+	if !ok {
+		return ret, nil
+	}
+	for key, r := range installs {
+		record, ok := r.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("record %q is not an object", key)
+		}
+		ret[key] = record
+	}
+	return ret, nil
+}
+
+// migrateV1 converts the verified flag of version 1 records into the
+// verification level: verified apps passed a policy evaluation.
+func migrateV1(doc map[string]any) error {
+	recs, err := records(doc)
+	if err != nil {
+		return err
+	}
+	for _, record := range recs {
+		level := levelNone
+		if verified, _ := record["verified"].(bool); verified {
+			level = levelPolicy
+		}
+		record["verificationLevel"] = level
+		delete(record, "verified")
+	}
+	return nil
+}
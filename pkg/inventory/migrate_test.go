@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationChain(t *testing.T) {
+	t.Parallel()
+	// Every version before the current one needs a migration and a fixture
+	for v := 1; v < Version; v++ {
+		require.Len(t, MigrationsFrom(v), Version-v, "migrations from version %d", v)
+		require.FileExists(t, filepath.Join("testdata", fmt.Sprintf("installed-v%d.json", v)))
+	}
+	require.Empty(t, MigrationsFrom(Version))
+}
+
+func TestOpenFileFixtures(t *testing.T) {
+	t.Parallel()
+	for v := 1; v <= Version; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			t.Parallel()
+			inv, err := OpenFile(filepath.Join("testdata", fmt.Sprintf("installed-v%d.json", v)))
+			require.NoError(t, err)
+			require.Equal(t, Version, inv.Version)
+			if v == Version {
+				require.Zero(t, inv.MigratedFrom())
+			} else {
+				require.Equal(t, v, inv.MigratedFrom())
+			}
+
+			require.Len(t, inv.Installs, 2)
+			drop := inv.Get("github.com/carabiner-dev/drop#drop")
+			require.NotNil(t, drop)
+			require.Equal(t, "v0.1.0", drop.Version)
+			require.Equal(t, map[string]string{"sha256": "abc123"}, drop.Digest)
+			require.Equal(t, "policy", drop.VerificationLevel)
+			require.Equal(t, 2025, drop.InstalledAt.Year())
+
+			ampel := inv.Get("github.com/carabiner-dev/ampel#ampel")
+			require.NotNil(t, ampel)
+			require.Equal(t, "deb", ampel.PackageFormat)
+			require.Equal(t, "none", ampel.VerificationLevel)
+		})
+	}
+}
+
+func TestSaveBacksUpMigrated(t *testing.T) {
+	t.Parallel()
+	original, err := os.ReadFile(filepath.Join("testdata", "installed-v1.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "installed.json")
+	require.NoError(t, os.WriteFile(path, original, 0o600))
+
+	inv, err := OpenFile(path)
+	require.NoError(t, err)
+	require.NoFileExists(t, BackupPath(path, 1))
+	require.NoError(t, inv.Save())
+
+	backup, err := os.ReadFile(BackupPath(path, 1))
+	require.NoError(t, err)
+	require.Equal(t, original, backup)
+
+	// The rewritten file is current and later saves leave the backup alone
+	inv, err = OpenFile(path)
+	require.NoError(t, err)
+	require.Zero(t, inv.MigratedFrom())
+	inv.Add(testRecord())
+	require.NoError(t, inv.Save())
+	backup, err = os.ReadFile(BackupPath(path, 1))
+	require.NoError(t, err)
+	require.Equal(t, original, backup)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		from    int
+		mustErr bool
+	}{
+		{"current", `{"version": 2, "installs": {}}`, 2, false},
+		{"v1", `{"version": 1, "installs": {"a": {"verified": true}}}`, 1, false},
+		{"unversioned", `{"installs": {}}`, 1, false},
+		{"newer", `{"version": 99}`, 0, true},
+		{"bad-record", `{"version": 1, "installs": {"a": "b"}}`, 0, true},
+		{"not-json", `not json`, 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, from, err := Migrate([]byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.from, from)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Inventory scopes. The user inventory lives in the user's configuration
+// directory, the system inventory records the apps installed system-wide
+// and is readable by every user.
+const (
+	ScopeUser   = "user"
+	ScopeSystem = "system"
+)
+
+// Scopes lists the inventory scopes
+var Scopes = []string{ScopeUser, ScopeSystem}
+
+// SystemDir is the directory of the system-wide inventory
+const SystemDir = "/var/lib/drop"
+
+// SystemPath returns the location of the system-wide inventory
+func SystemPath() string {
+	return filepath.Join(SystemDir, FileName)
+}
+
+// ScopePath returns the location of the inventory of a scope
+func ScopePath(scope string) (string, error) {
+	switch scope {
+	case ScopeUser:
+		return DefaultPath()
+	case ScopeSystem:
+		return SystemPath(), nil
+	default:
+		return "", fmt.Errorf("invalid inventory scope %q, valid scopes are %v", scope, Scopes)
+	}
+}
+
+// shared returns true for files in the system inventory directory, which
+// other users must be able to read.
+func shared(path string) bool {
+	return filepath.Dir(filepath.Clean(path)) == SystemDir
+}
+
+// dirMode returns the permissions of the directory of an inventory file
+func dirMode(path string) os.FileMode {
+	if shared(path) {
+		return 0o755
+	}
+	return 0o750
+}
+
+// InventoryPath returns the inventory file the record was loaded from,
+// changes to the record are saved there.
+func (r *Record) InventoryPath() string {
+	return r.inventory
+}
+
+// Installed returns the records of the apps installed in a scope, or in
+// both scopes when empty, sorted by key. Records without a scope take the
+// scope of their inventory. Records of system installs left in the user
+// inventory by older versions are skipped when the system inventory has
+// the same app.
+func Installed(scope string) ([]*Record, error) {
+	paths := map[string]string{}
+	for _, s := range Scopes {
+		path, err := ScopePath(s)
+		if err != nil {
+			return nil, err
+		}
+		paths[s] = path
+	}
+	return installed(paths, scope)
+}
+
+// installed loads the records of the inventories keyed by scope
+func installed(paths map[string]string, scope string) ([]*Record, error) {
+	if scope != "" && !slices.Contains(Scopes, scope) {
+		return nil, fmt.Errorf("invalid inventory scope %q, valid scopes are %v", scope, Scopes)
+	}
+
+	inventories := map[string]*Inventory{}
+	for _, s := range Scopes {
+		inv, err := OpenFile(paths[s])
+		if err != nil {
+			return nil, fmt.Errorf("opening %s inventory: %w", s, err)
+		}
+		inventories[s] = inv
+	}
+
+	ret := []*Record{}
+	for _, s := range Scopes {
+		for key, record := range inventories[s].Installs {
+			record.inventory = paths[s]
+			if record.Scope == "" {
+				record.Scope = s
+			}
+			if s == ScopeUser && record.Scope == ScopeSystem && inventories[ScopeSystem].Get(key) != nil {
+				continue
+			}
+			if scope != "" && record.Scope != scope {
+				continue
+			}
+			ret = append(ret, record)
+		}
+	}
+	slices.SortFunc(ret, func(a, b *Record) int {
+		return cmp.Or(strings.Compare(a.Key(), b.Key()), strings.Compare(a.Scope, b.Scope))
+	})
+	return ret, nil
+}
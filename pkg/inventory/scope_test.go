@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopePath(t *testing.T) {
+	t.Parallel()
+	path, err := ScopePath(ScopeSystem)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(SystemDir, FileName), path)
+	require.True(t, shared(path))
+
+	path, err = ScopePath(ScopeUser)
+	require.NoError(t, err)
+	require.False(t, shared(path))
+
+	_, err = ScopePath("global")
+	require.Error(t, err)
+}
+
+func TestInstalled(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	paths := map[string]string{
+		ScopeUser:   filepath.Join(dir, "user", FileName),
+		ScopeSystem: filepath.Join(dir, "system", FileName),
+	}
+
+	user, err := OpenFile(paths[ScopeUser])
+	require.NoError(t, err)
+	mine := testRecord()
+	mine.Name = "mine"
+	user.Add(mine)
+	// A system install recorded in the user inventory by older versions,
+	// shadowed by the record in the system inventory.
+	legacy := testRecord()
+	legacy.Scope = ScopeSystem
+	user.Add(legacy)
+	// A system install the user could not record system-wide
+	fallback := testRecord()
+	fallback.Name = "fallback"
+	fallback.Scope = ScopeSystem
+	user.Add(fallback)
+	require.NoError(t, user.Save())
+
+	system, err := OpenFile(paths[ScopeSystem])
+	require.NoError(t, err)
+	shared := testRecord()
+	shared.Version = "v0.2.0"
+	shared.Scope = ScopeSystem
+	system.Add(shared)
+	require.NoError(t, system.Save())
+
+	for _, tc := range []struct {
+		name    string
+		scope   string
+		expect  []string
+		mustErr bool
+	}{
+		{"all", "", []string{"drop@v0.2.0", "fallback@v0.1.0", "mine@v0.1.0"}, false},
+		{"user", ScopeUser, []string{"mine@v0.1.0"}, false},
+		{"system", ScopeSystem, []string{"drop@v0.2.0", "fallback@v0.1.0"}, false},
+		{"invalid", "global", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			records, err := installed(paths, tc.scope)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := []string{}
+			for _, r := range records {
+				got = append(got, r.Name+"@"+r.Version)
+			}
+			require.Equal(t, tc.expect, got)
+		})
+	}
+
+	records, err := installed(paths, "")
+	require.NoError(t, err)
+	for _, r := range records {
+		switch r.Name {
+		case "mine":
+			// Records without a scope take the one of their inventory
+			require.Equal(t, ScopeUser, r.Scope)
+			require.Equal(t, paths[ScopeUser], r.InventoryPath())
+		case "fallback":
+			require.Equal(t, paths[ScopeUser], r.InventoryPath())
+		default:
+			require.Equal(t, paths[ScopeSystem], r.InventoryPath())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// tarMagicOffset is the position of the "ustar" magic in tar headers
+const tarMagicOffset = 257
+
+// WalkArchiveFile walks the entries of a release archive: zip files and
+// tarballs, plain or compressed with gzip, xz, zstd or bzip2. Entries are
+// reported with the same types as package payloads.
+func WalkArchiveFile(path string, fn PayloadFunc) error {
+	f, err := os.Open(path) //nolint:gosec // reading the archive is the point
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	br := bufio.NewReader(f)
+	head, err := br.Peek(tarMagicOffset + 5)
+	if err != nil && len(head) < 4 {
+		return fmt.Errorf("reading archive header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		st, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("reading archive size: %w", err)
+		}
+		zr, err := zip.NewReader(f, st.Size())
+		if err != nil {
+			return fmt.Errorf("opening zip archive: %w", err)
+		}
+		return walkZip(zr, fn)
+	case len(head) >= tarMagicOffset+5 && string(head[tarMagicOffset:tarMagicOffset+5]) == "ustar":
+		return walkTarPayload(br, false, fn)
+	default:
+		tarball, err := decompressByMagic(br)
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		defer tarball.Close() //nolint:errcheck
+		return walkTarPayload(tarball, false, fn)
+	}
+}
+
+// walkZip walks the entries of a zip archive. Symlinks are stored as files
+// holding the link target.
+func walkZip(zr *zip.Reader, fn PayloadFunc) error {
+	for _, zf := range zr.File {
+		name := payloadName(zf.Name)
+		if name == "" {
+			continue
+		}
+		mode := zf.Mode()
+		f := &PayloadFile{Name: name, Mode: mode.Perm()}
+
+		switch {
+		case mode.IsDir() || strings.HasSuffix(zf.Name, "/"):
+			f.Type = FileDir
+			if err := fn(f, bytes.NewReader(nil)); err != nil {
+				return err
+			}
+			continue
+		case mode&os.ModeSymlink != 0:
+			f.Type = FileSymlink
+		case mode.IsRegular():
+			f.Type = FileRegular
+		default:
+			continue
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", zf.Name, err)
+		}
+		if f.Type == FileSymlink {
+			target, err := io.ReadAll(io.LimitReader(rc, cpioMaxName))
+			rc.Close() //nolint:errcheck,gosec
+			if err != nil {
+				return fmt.Errorf("reading symlink %s: %w", zf.Name, err)
+			}
+			f.Linkname = path.Clean(string(target))
+			if err := fn(f, bytes.NewReader(nil)); err != nil {
+				return err
+			}
+			continue
+		}
+		err = fn(f, rc)
+		rc.Close() //nolint:errcheck,gosec
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// cpio "new ASCII" format constants, the payload format of RPM packages
+const (
+	cpioHeaderSize = 110
+	cpioTrailer    = "TRAILER!!!"
+	cpioMaxName    = 4096
+
+	cpioModeType    = 0o170000
+	cpioModeDir     = 0o040000
+	cpioModeRegular = 0o100000
+	cpioModeSymlink = 0o120000
+)
+
+// cpioHeader holds the fields of a newc header used to walk the archive
+type cpioHeader struct {
+	ino, mode, nlink, size, namesize int64
+}
+
+// walkCpio walks a newc (070701) or crc (070702) cpio archive. Hardlinked
+// files carry their data only in the last entry of the set, the earlier
+// names are reported as hardlinks to it once the data is seen.
+func walkCpio(r io.Reader, fn PayloadFunc) error {
+	pending := map[int64][]*PayloadFile{}
+	raw := make([]byte, cpioHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, raw); err != nil {
+			return fmt.Errorf("reading cpio header: %w", err)
+		}
+		hdr, err := parseCpioHeader(raw)
+		if err != nil {
+			return err
+		}
+
+		nameBytes := make([]byte, hdr.namesize)
+		if _, err := io.ReadFull(r, nameBytes); err != nil {
+			return fmt.Errorf("reading cpio entry name: %w", err)
+		}
+		if err := cpioSkip(r, cpioHeaderSize+hdr.namesize); err != nil {
+			return err
+		}
+		rawName := string(bytes.TrimRight(nameBytes, "\x00"))
+		if rawName == cpioTrailer {
+			break
+		}
+
+		data := io.LimitReader(r, hdr.size)
+		if err := emitCpioEntry(hdr, payloadName(rawName), data, pending, fn); err != nil {
+			return err
+		}
+
+		// Drain what the callback did not read, plus the alignment padding
+		if _, err := io.Copy(io.Discard, data); err != nil {
+			return fmt.Errorf("reading cpio entry data: %w", err)
+		}
+		if err := cpioSkip(r, hdr.size); err != nil {
+			return err
+		}
+	}
+
+	// Hardlink sets without data are empty files
+	for _, files := range pending {
+		for _, f := range files {
+			f.Type = FileRegular
+			if err := fn(f, bytes.NewReader(nil)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// emitCpioEntry calls fn for an archive entry, deferring hardlinks until
+// the entry holding their data is read.
+func emitCpioEntry(hdr *cpioHeader, name string, data io.Reader, pending map[int64][]*PayloadFile, fn PayloadFunc) error {
+	if name == "" {
+		return nil
+	}
+	f := &PayloadFile{Name: name, Mode: os.FileMode(hdr.mode).Perm()} //nolint:gosec // masked to perm bits
+	switch hdr.mode & cpioModeType {
+	case cpioModeDir:
+		f.Type = FileDir
+		return fn(f, bytes.NewReader(nil))
+	case cpioModeSymlink:
+		target, err := io.ReadAll(io.LimitReader(data, cpioMaxName))
+		if err != nil {
+			return fmt.Errorf("reading symlink target: %w", err)
+		}
+		f.Type = FileSymlink
+		f.Linkname = string(target)
+		return fn(f, bytes.NewReader(nil))
+	case cpioModeRegular:
+		f.Type = FileRegular
+	default:
+		return nil
+	}
+
+	if hdr.nlink > 1 && hdr.size == 0 {
+		pending[hdr.ino] = append(pending[hdr.ino], f)
+		return nil
+	}
+	if err := fn(f, data); err != nil {
+		return err
+	}
+	for _, link := range pending[hdr.ino] {
+		link.Type = FileHardlink
+		link.Linkname = name
+		if err := fn(link, bytes.NewReader(nil)); err != nil {
+			return err
+		}
+	}
+	delete(pending, hdr.ino)
+	return nil
+}
+
+// parseCpioHeader decodes the hexadecimal fields of a newc header
+func parseCpioHeader(raw []byte) (*cpioHeader, error) {
+	magic := string(raw[0:6])
+	if magic != "070701" && magic != "070702" {
+		return nil, fmt.Errorf("unsupported cpio format (magic %q)", magic)
+	}
+	field := func(i int) (int64, error) {
+		off := 6 + i*8
+		return strconv.ParseInt(string(raw[off:off+8]), 16, 64)
+	}
+
+	var hdr cpioHeader
+	for i, dst := range map[int]*int64{0: &hdr.ino, 1: &hdr.mode, 4: &hdr.nlink, 6: &hdr.size, 11: &hdr.namesize} {
+		v, err := field(i)
+		if err != nil {
+			return nil, errors.New("invalid cpio header field")
+		}
+		*dst = v
+	}
+	if hdr.namesize <= 0 || hdr.namesize > cpioMaxName {
+		return nil, errors.New("invalid cpio entry name size")
+	}
+	return &hdr, nil
+}
+
+// cpioSkip discards the padding aligning the next record to 4 bytes
+func cpioSkip(r io.Reader, n int64) error {
+	if pad := (4 - n%4) % 4; pad > 0 {
+		if _, err := io.CopyN(io.Discard, r, pad); err != nil {
+			return fmt.Errorf("reading cpio padding: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+// ReadDeb reads the package identity from the control file of a deb. Debs
+// are ar archives holding a control.tar (optionally compressed) with the
+// package control file.
+func ReadDeb(r io.Reader) (*Metadata, error) {
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != arMagic {
+		return nil, errors.New("not a deb package (bad ar header)")
+	}
+
+	hdr := make([]byte, arHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: deb has no control tarball", ErrNoMetadata)
+			}
+			return nil, fmt.Errorf("reading ar header: %w", err)
+		}
+
+		name := strings.TrimSuffix(strings.TrimSpace(string(hdr[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return nil, errors.New("invalid ar member size")
+		}
+
+		if strings.HasPrefix(name, "control.tar") {
+			tarball, err := decompressReader(name, io.LimitReader(r, size))
+			if err != nil {
+				return nil, err
+			}
+			defer tarball.Close() //nolint:errcheck
+			data, err := findTarFile(tarball, "control")
+			if err != nil {
+				return nil, fmt.Errorf("reading deb control: %w", err)
+			}
+			return parseDebControl(data), nil
+		}
+
+		// ar members are aligned to even offsets
+		if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+			return nil, fmt.Errorf("skipping ar member: %w", err)
+		}
+	}
+}
+
+// decompressReader wraps a reader with the decompressor matching the
+// extension of a control tarball name.
+func decompressReader(name string, r io.Reader) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip stream: %w", err)
+		}
+		return zr, nil
+	case strings.HasSuffix(name, ".xz"):
+		zr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("opening xz stream: %w", err)
+		}
+		return io.NopCloser(zr), nil
+	case strings.HasSuffix(name, ".zst"):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("opening zstd stream: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	case strings.HasSuffix(name, ".tar"):
+		return io.NopCloser(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression in %q", name)
+	}
+}
+
+// parseDebControl reads the package fields from a deb control file
+func parseDebControl(data []byte) *Metadata {
+	md := &Metadata{Format: system.PackageDeb}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Continuation lines belong to multiline fields (Description)
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.ToLower(k) {
+		case "package":
+			md.Name = v
+		case "version":
+			md.Version = v
+		case "architecture":
+			md.Arch = v
+		}
+	}
+	return md
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// FileType is the kind of entry found in a package payload
+type FileType string
+
+const (
+	FileRegular  FileType = "file"
+	FileDir      FileType = "dir"
+	FileSymlink  FileType = "symlink"
+	FileHardlink FileType = "hardlink"
+)
+
+// PayloadFile is an entry of the files a package installs
+type PayloadFile struct {
+	// Name is the path where the package manager would install the entry,
+	// relative to the filesystem root (usr/bin/drop)
+	Name string
+
+	// Type is the kind of entry
+	Type FileType
+
+	// Mode holds the permission bits of the entry
+	Mode os.FileMode
+
+	// Linkname is the symlink target or, for hardlinks, the payload Name
+	// of the linked file
+	Linkname string
+}
+
+// PayloadFunc is called for every entry in a package payload. For regular
+// files the reader returns the file contents.
+type PayloadFunc func(*PayloadFile, io.Reader) error
+
+// WalkPayloadFile walks the payload of a package file
+func WalkPayloadFile(format, path string, fn PayloadFunc) error {
+	f, err := os.Open(path) //nolint:gosec // reading the package is the point
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	return WalkPayload(format, f, fn)
+}
+
+// WalkPayload walks the files a package installs, calling fn for each one.
+// Package scripts and metadata files are not part of the payload.
+func WalkPayload(format string, r io.Reader, fn PayloadFunc) error {
+	switch format {
+	case system.PackageDeb:
+		return walkDebPayload(r, fn)
+	case system.PackageRPM:
+		return walkRPMPayload(r, fn)
+	case system.PackageApk:
+		// The apk segments read together as one tarball, the control
+		// files are the dot-files at its top.
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("not an apk v2 package: %w", err)
+		}
+		return walkTarPayload(zr, true, fn)
+	case system.PackagePacman:
+		tarball, err := decompressByMagic(r)
+		if err != nil {
+			return fmt.Errorf("reading pacman package: %w", err)
+		}
+		defer tarball.Close() //nolint:errcheck
+		return walkTarPayload(tarball, true, fn)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// walkDebPayload walks the data tarball of a deb
+func walkDebPayload(r io.Reader, fn PayloadFunc) error {
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != arMagic {
+		return errors.New("not a deb package (bad ar header)")
+	}
+
+	hdr := make([]byte, arHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("deb has no data tarball")
+			}
+			return fmt.Errorf("reading ar header: %w", err)
+		}
+
+		name := strings.TrimSuffix(strings.TrimSpace(string(hdr[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return errors.New("invalid ar member size")
+		}
+
+		if strings.HasPrefix(name, "data.tar") {
+			tarball, err := decompressReader(name, io.LimitReader(r, size))
+			if err != nil {
+				return err
+			}
+			defer tarball.Close() //nolint:errcheck
+			return walkTarPayload(tarball, false, fn)
+		}
+
+		if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+			return fmt.Errorf("skipping ar member: %w", err)
+		}
+	}
+}
+
+// walkRPMPayload walks the cpio archive following the rpm headers
+func walkRPMPayload(r io.Reader, fn PayloadFunc) error {
+	if _, err := readRPMHeaders(r); err != nil {
+		return err
+	}
+	archive, err := decompressByMagic(r)
+	if err != nil {
+		return fmt.Errorf("reading rpm payload: %w", err)
+	}
+	defer archive.Close() //nolint:errcheck
+	return walkCpio(archive, fn)
+}
+
+// payloadName cleans an archive path into a root-relative payload name.
+// It returns an empty string for the root itself and for paths escaping it.
+func payloadName(name string) string {
+	name = path.Clean("/" + strings.TrimPrefix(name, "./"))
+	return strings.TrimPrefix(name, "/")
+}
+
+// walkTarPayload walks the entries of a payload tarball. When skipControl
+// is set, dot-files at the top of the archive (.PKGINFO, .SIGN.*, install
+// scripts) are package metadata and skipped.
+func walkTarPayload(r io.Reader, skipControl bool, fn PayloadFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading tarball: %w", err)
+		}
+
+		name := payloadName(hdr.Name)
+		if name == "" || (skipControl && strings.HasPrefix(name, ".") && !strings.Contains(name, "/")) {
+			continue
+		}
+
+		f := &PayloadFile{Name: name, Mode: os.FileMode(hdr.Mode).Perm()} //nolint:gosec // masked to perm bits
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			f.Type = FileRegular
+		case tar.TypeDir:
+			f.Type = FileDir
+		case tar.TypeSymlink:
+			f.Type = FileSymlink
+			f.Linkname = hdr.Linkname
+		case tar.TypeLink:
+			f.Type = FileHardlink
+			f.Linkname = payloadName(hdr.Linkname)
+		default:
+			// Devices, fifos and other special files are not installable
+			// without privileges
+			continue
+		}
+		if err := fn(f, tr); err != nil {
+			return err
+		}
+	}
+}
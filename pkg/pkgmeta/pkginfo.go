@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// pkgInfoFile is the metadata file in apk and pacman packages
+const pkgInfoFile = ".PKGINFO"
+
+// ReadApk reads the package identity from the .PKGINFO file of an apk. The
+// apk v2 format is a concatenation of gzip streams (signature, control and
+// data) which read together form a single tarball.
+func ReadApk(r io.Reader) (*Metadata, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an apk v2 package: %w", err)
+	}
+	data, err := findTarFile(zr, pkgInfoFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading apk %s: %w", pkgInfoFile, err)
+	}
+	return pkgInfoMetadata(system.PackageApk, data), nil
+}
+
+// ReadPacman reads the package identity from the .PKGINFO of an Arch Linux
+// package, a zstd (or legacy xz) compressed tarball.
+func ReadPacman(r io.Reader) (*Metadata, error) {
+	tarball, err := decompressByMagic(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading pacman package: %w", err)
+	}
+	defer tarball.Close() //nolint:errcheck
+
+	data, err := findTarFile(tarball, pkgInfoFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading pacman %s: %w", pkgInfoFile, err)
+	}
+	return pkgInfoMetadata(system.PackagePacman, data), nil
+}
+
+// decompressByMagic detects the compression of a stream by its first bytes
+// and returns a reader of the decompressed data.
+func decompressByMagic(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(6)
+	if err != nil {
+		return nil, fmt.Errorf("reading compression header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("opening zstd stream: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		zr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("opening xz stream: %w", err)
+		}
+		return io.NopCloser(zr), nil
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip stream: %w", err)
+		}
+		return zr, nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return io.NopCloser(bzip2.NewReader(br)), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown compression", ErrUnsupportedFormat)
+	}
+}
+
+// pkgInfoMetadata builds the metadata from the .PKGINFO key/value pairs
+func pkgInfoMetadata(format string, data []byte) *Metadata {
+	kv := parseKeyValues(data)
+	return &Metadata{
+		Format:  format,
+		Name:    kv["pkgname"],
+		Version: kv["pkgver"],
+		Arch:    kv["arch"],
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pkgmeta reads the identity of system packages (deb, rpm, apk and
+// pacman) from the package files. Release assets are often named after the
+// project while the package inside registers under another name, so the
+// installer reads the real name and version from the package metadata before
+// handing it to the package manager. It also walks the package payloads for
+// installs that unpack packages without a package manager, and the contents
+// of release archives.
+package pkgmeta
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// maxMetadataSize caps the size of the metadata files read from packages
+const maxMetadataSize = 1 << 20
+
+// Formats are the package formats whose metadata and payload can be read
+var Formats = []string{system.PackageDeb, system.PackageRPM, system.PackageApk, system.PackagePacman}
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported package format")
+	ErrNoMetadata        = errors.New("package metadata not found")
+)
+
+// Metadata is the identity of a package as registered in the package manager
+type Metadata struct {
+	// Format is the package type (rpm, deb, apk, pacman)
+	Format string
+
+	// Name is the name the package manager knows the package by
+	Name string
+
+	// Version is the full package version as reported by the package
+	// manager (version-release in rpm, pkgver in apk and pacman). RPM
+	// epochs are kept in their own field.
+	Version string
+
+	// Epoch is the RPM epoch, empty when the package does not set one
+	Epoch string
+
+	// Arch is the package architecture label (x86_64, amd64, noarch...)
+	Arch string
+}
+
+// ReadFile reads the metadata of a package file of the specified format
+func ReadFile(format, path string) (*Metadata, error) {
+	f, err := os.Open(path) //nolint:gosec // reading the package is the point
+	if err != nil {
+		return nil, fmt.Errorf("opening package: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	return Read(format, f)
+}
+
+// Read reads the metadata of a package of the specified format from a reader
+func Read(format string, r io.Reader) (*Metadata, error) {
+	var md *Metadata
+	var err error
+	switch format {
+	case system.PackageDeb:
+		md, err = ReadDeb(r)
+	case system.PackageRPM:
+		md, err = ReadRPM(r)
+	case system.PackageApk:
+		md, err = ReadApk(r)
+	case system.PackagePacman:
+		md, err = ReadPacman(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if md.Name == "" {
+		return nil, fmt.Errorf("%w: package has no name", ErrNoMetadata)
+	}
+	return md, nil
+}
+
+// findTarFile scans a tarball and returns the contents of the first file
+// matching one of the names (leading ./ is ignored).
+func findTarFile(r io.Reader, names ...string) ([]byte, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoMetadata
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tarball: %w", err)
+		}
+		name := strings.TrimPrefix(hdr.Name, "./")
+		for _, n := range names {
+			if name == n {
+				return io.ReadAll(io.LimitReader(tr, maxMetadataSize))
+			}
+		}
+	}
+}
+
+// parseKeyValues parses the "key = value" lines of apk and pacman .PKGINFO
+// files. Only the first value of repeated keys is kept.
+func parseKeyValues(data []byte) map[string]string {
+	ret := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if _, ok := ret[k]; ok {
+			continue
+		}
+		ret[k] = strings.TrimSpace(v)
+	}
+	return ret
+}
+
+// FullVersion returns the version including the RPM epoch, if any
+func (md *Metadata) FullVersion() string {
+	if md.Epoch == "" || md.Epoch == "0" {
+		return md.Version
+	}
+	return md.Epoch + ":" + md.Version
+}
@@ -0,0 +1,361 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// tarball builds an uncompressed tarball with the specified files
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func compress(t *testing.T, algo string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch algo {
+	case "gz":
+		w = gzip.NewWriter(&buf)
+	case "xz":
+		w, err = xz.NewWriter(&buf)
+	case "zst":
+		w, err = zstd.NewWriter(&buf)
+	default:
+		return data
+	}
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// arArchive builds a deb-like ar archive
+func arArchive(members ...[2]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(arMagic)
+	for _, m := range members {
+		fmt.Fprintf(&buf, "%-16s%-12s%-6s%-6s%-8s%-10d`\n", m[0], "0", "0", "0", "100644", len(m[1]))
+		buf.WriteString(m[1])
+		if len(m[1])%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+const debControl = `Package: drop-cli
+Version: 1:1.2.3-1
+Architecture: amd64
+Maintainer: Carabiner Systems
+Description: drop installer
+ A longer description
+ Version: 9.9.9
+`
+
+func TestReadDeb(t *testing.T) {
+	t.Parallel()
+	for _, algo := range []string{"gz", "xz", "zst", "tar"} {
+		t.Run(algo, func(t *testing.T) {
+			t.Parallel()
+			ext := ".tar." + algo
+			if algo == "tar" {
+				ext = ".tar"
+			}
+			control := compress(t, algo, tarball(t, map[string]string{"./control": debControl}))
+			deb := arArchive(
+				[2]string{"debian-binary", "2.0\n"},
+				[2]string{"control" + ext, string(control)},
+				[2]string{"data.tar.gz", "not read"},
+			)
+
+			md, err := Read(system.PackageDeb, bytes.NewReader(deb))
+			require.NoError(t, err)
+			require.Equal(t, &Metadata{
+				Format: system.PackageDeb, Name: "drop-cli", Version: "1:1.2.3-1", Arch: "amd64",
+			}, md)
+		})
+	}
+
+	_, err := ReadDeb(bytes.NewReader([]byte("not an ar archive")))
+	require.Error(t, err)
+}
+
+// rpmHeaderBytes serializes a header structure with string and int32 tags
+func rpmHeaderBytes(strs map[uint32]string, ints map[uint32]uint32) []byte {
+	var index, store bytes.Buffer
+	entry := func(tag, typ, offset uint32) {
+		_ = binary.Write(&index, binary.BigEndian, []uint32{tag, typ, offset, 1}) //nolint:errcheck
+	}
+	for tag, v := range ints {
+		for store.Len()%4 != 0 {
+			store.WriteByte(0)
+		}
+		entry(tag, rpmTypeInt32, uint32(store.Len())) //nolint:gosec
+		_ = binary.Write(&store, binary.BigEndian, v) //nolint:errcheck
+	}
+	for tag, v := range strs {
+		entry(tag, rpmTypeString, uint32(store.Len())) //nolint:gosec
+		store.WriteString(v)
+		store.WriteByte(0)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(rpmHeaderMagic)
+	buf.Write([]byte{0, 0, 0, 0})
+	_ = binary.Write(&buf, binary.BigEndian, uint32(index.Len()/rpmIndexEntrySize)) //nolint:errcheck,gosec
+	_ = binary.Write(&buf, binary.BigEndian, uint32(store.Len()))                   //nolint:errcheck,gosec
+	buf.Write(index.Bytes())
+	buf.Write(store.Bytes())
+	return buf.Bytes()
+}
+
+func TestReadRPM(t *testing.T) {
+	t.Parallel()
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, rpmLeadMagic)
+
+	// A signature store of 5 bytes forces 3 bytes of padding
+	sig := rpmHeaderBytes(map[uint32]string{1004: "abcd"}, nil)
+	main := rpmHeaderBytes(map[uint32]string{
+		rpmTagName: "drop-cli", rpmTagVersion: "1.2.3", rpmTagRelease: "1.el9", rpmTagArch: "x86_64",
+	}, map[uint32]uint32{rpmTagEpoch: 2})
+
+	var rpm bytes.Buffer
+	rpm.Write(lead)
+	rpm.Write(sig)
+	rpm.Write([]byte{0, 0, 0})
+	rpm.Write(main)
+	rpm.WriteString("payload")
+
+	path := filepath.Join(t.TempDir(), "drop.rpm")
+	require.NoError(t, os.WriteFile(path, rpm.Bytes(), 0o600))
+
+	md, err := ReadFile(system.PackageRPM, path)
+	require.NoError(t, err)
+	require.Equal(t, "drop-cli", md.Name)
+	require.Equal(t, "1.2.3-1.el9", md.Version)
+	require.Equal(t, "2", md.Epoch)
+	require.Equal(t, "2:1.2.3-1.el9", md.FullVersion())
+	require.Equal(t, "x86_64", md.Arch)
+
+	_, err = ReadRPM(bytes.NewReader(make([]byte, rpmLeadSize)))
+	require.Error(t, err)
+}
+
+const pkgInfo = `# Generated by abuild
+pkgname = drop-cli
+pkgver = 1.2.3-r0
+arch = x86_64
+depend = so:libc.musl-x86_64.so.1
+`
+
+func TestReadApk(t *testing.T) {
+	t.Parallel()
+	// apk v2: the signature and control segments are separate gzip
+	// streams of tar fragments without end-of-archive blocks
+	sigTar := tarball(t, map[string]string{".SIGN.RSA.key.rsa.pub": "sig"})
+	sigTar = sigTar[:len(sigTar)-1024]
+	apk := append(compress(t, "gz", sigTar), compress(t, "gz", tarball(t, map[string]string{".PKGINFO": pkgInfo}))...)
+
+	md, err := Read(system.PackageApk, bytes.NewReader(apk))
+	require.NoError(t, err)
+	require.Equal(t, &Metadata{
+		Format: system.PackageApk, Name: "drop-cli", Version: "1.2.3-r0", Arch: "x86_64",
+	}, md)
+}
+
+func TestReadPacman(t *testing.T) {
+	t.Parallel()
+	for _, algo := range []string{"zst", "xz"} {
+		t.Run(algo, func(t *testing.T) {
+			t.Parallel()
+			pkg := compress(t, algo, tarball(t, map[string]string{".PKGINFO": pkgInfo}))
+			md, err := Read(system.PackagePacman, bytes.NewReader(pkg))
+			require.NoError(t, err)
+			require.Equal(t, "drop-cli", md.Name)
+			require.Equal(t, "1.2.3-r0", md.Version)
+		})
+	}
+}
+
+func TestReadUnsupported(t *testing.T) {
+	t.Parallel()
+	_, err := Read(system.PackageMSI, bytes.NewReader(nil))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	// A package without a name is not usable
+	control := compress(t, "gz", tarball(t, map[string]string{"control": "Version: 1.0\n"}))
+	_, err = Read(system.PackageDeb, bytes.NewReader(arArchive([2]string{"control.tar.gz", string(control)})))
+	require.ErrorIs(t, err, ErrNoMetadata)
+}
+
+// cpioArchive builds a newc cpio archive from entries of name, mode, ino,
+// nlink and data
+func cpioArchive(entries ...[5]string) []byte {
+	var buf bytes.Buffer
+	pad := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	for _, e := range append(entries, [5]string{cpioTrailer, "0", "0", "1", ""}) {
+		mode, _ := strconv.ParseInt(e[1], 8, 64)   //nolint:errcheck
+		ino, _ := strconv.ParseInt(e[2], 10, 64)   //nolint:errcheck
+		nlink, _ := strconv.ParseInt(e[3], 10, 64) //nolint:errcheck
+		fmt.Fprintf(&buf, "070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+			ino, mode, 0, 0, nlink, 0, len(e[4]), 0, 0, 0, 0, len(e[0])+1, 0)
+		buf.WriteString(e[0])
+		buf.WriteByte(0)
+		pad()
+		buf.WriteString(e[4])
+		pad()
+	}
+	return buf.Bytes()
+}
+
+// collectPayload walks a payload returning the entries and file contents
+func collectPayload(t *testing.T, format string, data []byte) map[string]string {
+	t.Helper()
+	ret := map[string]string{}
+	require.NoError(t, WalkPayload(format, bytes.NewReader(data), func(f *PayloadFile, r io.Reader) error {
+		switch f.Type {
+		case FileRegular:
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			ret[f.Name] = string(content)
+		default:
+			ret[f.Name] = string(f.Type) + ":" + f.Linkname
+		}
+		return nil
+	}))
+	return ret
+}
+
+func TestWalkPayload(t *testing.T) {
+	t.Parallel()
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, rpmLeadMagic)
+	rpm := append(lead, rpmHeaderBytes(nil, nil)...)
+	rpm = append(rpm, rpmHeaderBytes(map[uint32]string{rpmTagName: "drop"}, nil)...)
+	rpm = append(rpm, compress(t, "xz", cpioArchive(
+		[5]string{"./usr/bin", "040755", "1", "2", ""},
+		[5]string{"./usr/bin/drop-link", "0100755", "2", "2", ""},
+		[5]string{"./usr/bin/drop", "0100755", "2", "2", "binary"},
+		[5]string{"./usr/bin/d", "0120777", "3", "1", "drop"},
+		[5]string{"./dev/null", "020666", "4", "1", ""},
+	))...)
+
+	for _, tc := range []struct {
+		name   string
+		format string
+		data   []byte
+		expect map[string]string
+	}{
+		{
+			"deb", system.PackageDeb,
+			arArchive(
+				[2]string{"debian-binary", "2.0\n"},
+				[2]string{"control.tar.gz", string(compress(t, "gz", tarball(t, map[string]string{"./control": debControl})))},
+				[2]string{"data.tar.xz", string(compress(t, "xz", tarball(t, map[string]string{"./usr/bin/drop": "binary", "./": ""})))},
+			),
+			map[string]string{"usr/bin/drop": "binary"},
+		},
+		{
+			"rpm", system.PackageRPM, rpm,
+			map[string]string{
+				"usr/bin": "dir:", "usr/bin/drop": "binary",
+				"usr/bin/drop-link": "hardlink:usr/bin/drop", "usr/bin/d": "symlink:drop",
+			},
+		},
+		{
+			"apk", system.PackageApk,
+			compress(t, "gz", tarball(t, map[string]string{".PKGINFO": pkgInfo, ".post-install": "x", "usr/bin/drop": "binary"})),
+			map[string]string{"usr/bin/drop": "binary"},
+		},
+		{
+			"pacman", system.PackagePacman,
+			compress(t, "zst", tarball(t, map[string]string{".PKGINFO": pkgInfo, ".MTREE": "x", "usr/share/doc/.keep": "", "../etc/passwd": "x"})),
+			map[string]string{"usr/share/doc/.keep": "", "etc/passwd": "x"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, collectPayload(t, tc.format, tc.data))
+		})
+	}
+
+	require.ErrorIs(t, WalkPayload(system.PackageMSI, bytes.NewReader(nil), nil), ErrUnsupportedFormat)
+}
+
+func TestWalkArchiveFile(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{"drop-1.0.0/drop": "binary", "drop-1.0.0/completions/drop.bash": "complete"}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	for name, data := range map[string][]byte{
+		"drop.tar":     tarball(t, files),
+		"drop.tar.gz":  compress(t, "gz", tarball(t, files)),
+		"drop.tar.xz":  compress(t, "xz", tarball(t, files)),
+		"drop.tar.zst": compress(t, "zst", tarball(t, files)),
+		"drop.zip":     zipBuf.Bytes(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+
+			got := map[string]string{}
+			require.NoError(t, WalkArchiveFile(path, func(f *PayloadFile, r io.Reader) error {
+				content, err := io.ReadAll(r)
+				got[f.Name] = string(content)
+				return err
+			}))
+			require.Equal(t, files, got)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "drop.bin")
+	require.NoError(t, os.WriteFile(path, []byte("\x7fELF not an archive"), 0o600))
+	require.Error(t, WalkArchiveFile(path, nil))
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgmeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+// RPM file layout constants
+const (
+	rpmLeadSize        = 96
+	rpmIndexEntrySize  = 16
+	rpmHeaderIntroSize = 16
+
+	// Limits to avoid allocating absurd amounts of memory on bad files
+	rpmMaxIndexEntries = 1 << 16
+	rpmMaxStoreSize    = 64 << 20
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+	rpmTypeI18N   = 9
+
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+	rpmTagArch    = 1022
+)
+
+var (
+	rpmLeadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+type rpmIndexEntry struct {
+	Tag, Type, Offset, Count uint32
+}
+
+// rpmHeader is a parsed header structure: the index and its data store
+type rpmHeader struct {
+	entries []rpmIndexEntry
+	store   []byte
+}
+
+// ReadRPM reads the package identity from the main header of an RPM.
+func ReadRPM(r io.Reader) (*Metadata, error) {
+	hdr, err := readRPMHeaders(r)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &Metadata{
+		Format:  system.PackageRPM,
+		Name:    hdr.getString(rpmTagName),
+		Version: hdr.getString(rpmTagVersion),
+		Arch:    hdr.getString(rpmTagArch),
+	}
+	if release := hdr.getString(rpmTagRelease); release != "" {
+		md.Version += "-" + release
+	}
+	if epoch, ok := hdr.getInt32(rpmTagEpoch); ok {
+		md.Epoch = strconv.FormatUint(uint64(epoch), 10)
+	}
+	return md, nil
+}
+
+// readRPMHeaders reads the RPM file up to the end of the main header and
+// returns it, leaving the reader at the start of the payload. The file starts
+// with a fixed size lead followed by the signature header (padded to 8
+// bytes) and the main header holding the package tags.
+func readRPMHeaders(r io.Reader) (*rpmHeader, error) {
+	lead := make([]byte, rpmLeadSize)
+	if _, err := io.ReadFull(r, lead); err != nil || !bytes.Equal(lead[0:4], rpmLeadMagic) {
+		return nil, errors.New("not an rpm package (bad lead)")
+	}
+
+	sig, err := readRPMHeader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature header: %w", err)
+	}
+	if pad := (8 - len(sig.store)%8) % 8; pad > 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(pad)); err != nil {
+			return nil, fmt.Errorf("reading signature padding: %w", err)
+		}
+	}
+
+	hdr, err := readRPMHeader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading main header: %w", err)
+	}
+	return hdr, nil
+}
+
+// readRPMHeader reads a header structure: magic, index entries and store
+func readRPMHeader(r io.Reader) (*rpmHeader, error) {
+	intro := make([]byte, rpmHeaderIntroSize)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(intro[0:4], rpmHeaderMagic) {
+		return nil, errors.New("bad header magic")
+	}
+
+	nindex := binary.BigEndian.Uint32(intro[8:12])
+	hsize := binary.BigEndian.Uint32(intro[12:16])
+	if nindex > rpmMaxIndexEntries || hsize > rpmMaxStoreSize {
+		return nil, errors.New("header too large")
+	}
+
+	index := make([]byte, int(nindex)*rpmIndexEntrySize)
+	if _, err := io.ReadFull(r, index); err != nil {
+		return nil, err
+	}
+
+	h := &rpmHeader{
+		entries: make([]rpmIndexEntry, nindex),
+		store:   make([]byte, hsize),
+	}
+	for i := range h.entries {
+		e := index[i*rpmIndexEntrySize:]
+		h.entries[i] = rpmIndexEntry{
+			Tag:    binary.BigEndian.Uint32(e[0:4]),
+			Type:   binary.BigEndian.Uint32(e[4:8]),
+			Offset: binary.BigEndian.Uint32(e[8:12]),
+			Count:  binary.BigEndian.Uint32(e[12:16]),
+		}
+	}
+	if _, err := io.ReadFull(r, h.store); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *rpmHeader) find(tag uint32) *rpmIndexEntry {
+	for i := range h.entries {
+		if h.entries[i].Tag == tag {
+			return &h.entries[i]
+		}
+	}
+	return nil
+}
+
+// getString returns a string tag value or an empty string if not found
+func (h *rpmHeader) getString(tag uint32) string {
+	e := h.find(tag)
+	if e == nil || (e.Type != rpmTypeString && e.Type != rpmTypeI18N) || int(e.Offset) >= len(h.store) {
+		return ""
+	}
+	data := h.store[e.Offset:]
+	if end := bytes.IndexByte(data, 0); end != -1 {
+		data = data[:end]
+	}
+	return string(data)
+}
+
+// getInt32 returns the first value of an int32 tag
+func (h *rpmHeader) getInt32(tag uint32) (uint32, bool) {
+	e := h.find(tag)
+	if e == nil || e.Type != rpmTypeInt32 || int(e.Offset)+4 > len(h.store) {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(h.store[e.Offset:]), true
+}
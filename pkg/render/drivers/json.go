@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drivers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
+)
+
+// NewJSON returns a driver rendering machine readable JSON
+func NewJSON() *JSON {
+	return &JSON{}
+}
+
+// JSON renders the data as JSON documents, one per call
+type JSON struct{}
+
+type jsonRelease struct {
+	Host      string    `json:"host"`
+	Org       string    `json:"org"`
+	Repo      string    `json:"repo"`
+	Version   string    `json:"version"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type jsonAsset struct {
+	Name        string    `json:"name"`
+	Size        int       `json:"size"`
+	Author      string    `json:"author,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	DownloadURL string    `json:"downloadURL,omitempty"`
+}
+
+type jsonAssets struct {
+	Release jsonRelease `json:"release"`
+	Assets  []jsonAsset `json:"assets"`
+}
+
+// jsonApp is an installed app: its inventory record and where it lives
+type jsonApp struct {
+	*inventory.Record
+	Location string `json:"location"`
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+func newJSONRelease(release github.ReleaseDataProvider) jsonRelease {
+	return jsonRelease{
+		Host:      release.GetHost(),
+		Org:       release.GetOrg(),
+		Repo:      release.GetRepo(),
+		Version:   release.GetVersion(),
+		Author:    release.GetAuthor(),
+		CreatedAt: release.GetCreatedAt(),
+	}
+}
+
+func (j *JSON) renderAssets(w io.Writer, release github.ReleaseDataProvider, assets []github.AssetDataProvider) error {
+	doc := jsonAssets{Release: newJSONRelease(release), Assets: make([]jsonAsset, 0, len(assets))}
+	for _, a := range assets {
+		doc.Assets = append(doc.Assets, jsonAsset{
+			Name:        a.GetName(),
+			Size:        a.GetSize(),
+			Author:      a.GetAuthor(),
+			CreatedAt:   a.GetCreatedAt(),
+			UpdatedAt:   a.GetUpdatedAt(),
+			DownloadURL: a.GetDownloadURL(),
+		})
+	}
+	return encodeJSON(w, doc)
+}
+
+func (j *JSON) RenderReleaseInstallables(w io.Writer, release github.ReleaseDataProvider, assets []github.AssetDataProvider) error {
+	return j.renderAssets(w, release, assets)
+}
+
+func (j *JSON) RenderReleaseAssets(w io.Writer, release github.ReleaseDataProvider, assets []github.AssetDataProvider) error {
+	return j.renderAssets(w, release, assets)
+}
+
+func (j *JSON) RenderRepoReleases(w io.Writer, _ github.RepoDataProvider, releases []github.ReleaseDataProvider) error {
+	doc := make([]jsonRelease, 0, len(releases))
+	for _, r := range releases {
+		doc = append(doc, newJSONRelease(r))
+	}
+	return encodeJSON(w, doc)
+}
+
+func (j *JSON) RenderInstalledApps(w io.Writer, records []*inventory.Record) error {
+	doc := make([]jsonApp, 0, len(records))
+	for _, r := range records {
+		doc = append(doc, jsonApp{Record: r, Location: appLocation(r)})
+	}
+	return encodeJSON(w, doc)
+}
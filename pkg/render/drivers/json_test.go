@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package drivers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRenderInstalledApps(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	require.NoError(t, NewJSON().RenderInstalledApps(&b, testRecords()))
+
+	apps := []map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(b.String()), &apps))
+	require.Len(t, apps, 2)
+	require.Equal(t, "drop", apps[0]["name"])
+	require.Equal(t, "v0.1.0", apps[0]["version"])
+	require.Equal(t, "/home/user/.local/bin/drop", apps[0]["location"])
+	require.Equal(t, "policy", apps[0]["verificationLevel"])
+	require.Equal(t, "2025-03-01T12:00:00Z", apps[0]["installedAt"])
+	require.Equal(t, "rpm package cosign", apps[1]["location"])
+
+	// No apps render an empty list, not null
+	b.Reset()
+	require.NoError(t, NewJSON().RenderInstalledApps(&b, nil))
+	require.JSONEq(t, "[]", b.String())
+}
@@ -4,6 +4,7 @@
 package drivers
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"slices"
@@ -12,6 +13,7 @@ import (
 	"github.com/rodaine/table"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/system"
 )
 
@@ -189,3 +191,49 @@ func (ls *LsTTY) RenderRepoReleases(w io.Writer, repo github.RepoDataProvider, r
 	}
 	return nil
 }
+
+// appLocation returns where an installed app lives: its binary or AppImage,
+// the prefix it was unpacked into or the system package holding it.
+func appLocation(r *inventory.Record) string {
+	switch {
+	case r.BinPath != "":
+		return r.BinPath
+	case r.Prefix != "":
+		return r.Prefix
+	case r.PackageFormat != "":
+		return fmt.Sprintf("%s package %s", r.PackageFormat, cmp.Or(r.PackageName, r.Name))
+	default:
+		return "-"
+	}
+}
+
+// appDate formats the install and update dates of an app
+func appDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateOnly)
+}
+
+func (ls *LsTTY) RenderInstalledApps(w io.Writer, records []*inventory.Record) error {
+	if ls.Options.Long {
+		tbl := table.New("name", "version", "kind", "location", "verified", "installed", "updated")
+		tbl.WithWriter(w)
+
+		for _, r := range records {
+			tbl.AddRow(
+				r.Name, r.Version, r.Kind, appLocation(r), cmp.Or(r.VerificationLevel, "none"),
+				appDate(r.InstalledAt), appDate(r.UpdatedAt),
+			)
+		}
+
+		tbl.Print()
+	} else {
+		data := make([]string, 0, len(records))
+		for _, r := range records {
+			data = append(data, r.Name)
+		}
+		columnTable(w, 3, data)
+	}
+	return nil
+}
@@ -6,8 +6,11 @@ package drivers
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/drop/pkg/inventory"
 )
 
 func TestColumnTablePadsLastRow(t *testing.T) {
@@ -35,3 +38,41 @@ func TestColumnTablePadsLastRow(t *testing.T) {
 		})
 	}
 }
+
+func testRecords() []*inventory.Record {
+	installed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*inventory.Record{
+		{
+			Host: "github.com", Org: "carabiner-dev", Repo: "drop", Name: "drop", Version: "v0.1.0",
+			Kind: "binary", BinPath: "/home/user/.local/bin/drop", VerificationLevel: "policy",
+			InstalledAt: installed, UpdatedAt: installed,
+		},
+		{
+			Host: "github.com", Org: "sigstore", Repo: "cosign", Name: "cosign", Version: "v2.6.0",
+			Kind: "package", PackageFormat: "rpm", PackageName: "cosign",
+		},
+	}
+}
+
+func TestLsTTYRenderInstalledApps(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		long   bool
+		expect []string
+	}{
+		{"long", true, []string{"drop", "v0.1.0", "/home/user/.local/bin/drop", "policy", "rpm package cosign", "none", "-"}},
+		{"short", false, []string{"drop", "cosign"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			drv := NewLsTTY()
+			drv.Options.Long = tc.long
+			var b strings.Builder
+			require.NoError(t, drv.RenderInstalledApps(&b, testRecords()))
+			for _, s := range tc.expect {
+				require.Contains(t, b.String(), s)
+			}
+		})
+	}
+}
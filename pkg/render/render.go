@@ -8,6 +8,7 @@ import (
 	"io"
 
 	"github.com/carabiner-dev/drop/pkg/github"
+	"github.com/carabiner-dev/drop/pkg/inventory"
 	"github.com/carabiner-dev/drop/pkg/render/drivers"
 )
 
@@ -43,6 +44,7 @@ type Driver interface {
 	RenderReleaseAssets(io.Writer, github.ReleaseDataProvider, []github.AssetDataProvider) error
 	RenderRepoReleases(io.Writer, github.RepoDataProvider, []github.ReleaseDataProvider) error
 	RenderReleaseInstallables(io.Writer, github.ReleaseDataProvider, []github.AssetDataProvider) error
+	RenderInstalledApps(io.Writer, []*inventory.Record) error
 }
 
 func (e *Engine) RenderReleaseInstallables(w io.Writer, release github.ReleaseDataProvider, assets []github.AssetDataProvider) error {
@@ -56,3 +58,7 @@ func (e *Engine) RenderReleaseAssets(w io.Writer, release github.ReleaseDataProv
 func (e *Engine) RenderRepoReleases(w io.Writer, repo github.RepoDataProvider, releases []github.ReleaseDataProvider) error {
 	return e.driver.RenderRepoReleases(w, repo, releases)
 }
+
+func (e *Engine) RenderInstalledApps(w io.Writer, records []*inventory.Record) error {
+	return e.driver.RenderInstalledApps(w, records)
+}
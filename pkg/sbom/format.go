@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/reader"
+	"github.com/protobom/protobom/pkg/writer"
+)
+
+// SBOM formats, the names of the document families
+const (
+	FormatSPDX      = formats.SPDXFORMAT
+	FormatCycloneDX = formats.CDXFORMAT
+)
+
+// Formats lists the formats SBOMs can be exported in
+var Formats = []string{FormatSPDX, FormatCycloneDX}
+
+var ErrUnknownFormat = errors.New("document is not an SPDX or CycloneDX SBOM")
+
+// exportFormats are the documents written when converting to a format
+var exportFormats = map[string]formats.Format{
+	FormatSPDX:      formats.SPDX23JSON,
+	FormatCycloneDX: formats.CDX16JSON,
+}
+
+// Detect returns the format of an SBOM
+func Detect(data []byte) (string, error) {
+	f, err := (&formats.Sniffer{}).SniffReader(bytes.NewReader(data))
+	if err != nil || f.Type() == "" {
+		return "", ErrUnknownFormat
+	}
+	return f.Type(), nil
+}
+
+// ParseFormat returns the format named by a string, accepting cdx as short
+// for CycloneDX.
+func ParseFormat(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case FormatSPDX:
+		return FormatSPDX, nil
+	case FormatCycloneDX, "cdx":
+		return FormatCycloneDX, nil
+	default:
+		return "", fmt.Errorf("unknown SBOM format %q, valid formats are %v", s, Formats)
+	}
+}
+
+// Convert writes an SBOM in a format. Documents already in the format are
+// copied as published, others are translated to SPDX 2.3 or CycloneDX 1.6
+// JSON. Translating loses the data the target format cannot express.
+func Convert(data []byte, format string, w io.Writer) error {
+	target, ok := exportFormats[format]
+	if !ok {
+		return fmt.Errorf("unknown SBOM format %q, valid formats are %v", format, Formats)
+	}
+	current, err := Detect(data)
+	if err != nil {
+		return err
+	}
+	if current == format {
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("writing SBOM: %w", err)
+		}
+		return nil
+	}
+
+	doc, err := reader.New().ParseStream(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parsing SBOM: %w", err)
+	}
+	if err := writer.New(writer.WithFormat(target)).WriteStream(doc, w); err != nil {
+		return fmt.Errorf("writing %s SBOM: %w", format, err)
+	}
+	return nil
+}
+
+// Components returns the package URLs of the components listed in an SBOM
+func Components(data []byte) ([]string, error) {
+	doc, err := reader.New().ParseStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing SBOM: %w", err)
+	}
+	ret := []string{}
+	for _, node := range doc.GetNodeList().GetNodes() {
+		if purl := string(node.Purl()); purl != "" && !slices.Contains(ret, purl) {
+			ret = append(ret, purl)
+		}
+	}
+	return ret, nil
+}
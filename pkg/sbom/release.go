@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/carabiner-dev/drop/pkg/system"
+)
+
+var ErrSubjectMismatch = errors.New("SBOM does not match the subjects of its attestation")
+
+// suffixes are the extensions of the SBOMs published in releases
+var suffixes = []string{
+	".spdx.json", ".spdx", ".cdx.json", ".cyclonedx.json", ".bom.json", ".sbom.json", ".sbom",
+}
+
+// trimSuffix returns a file name without its SBOM extension and true, or
+// false when the file is not an SBOM.
+func trimSuffix(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(lower, suffix) && len(name) > len(suffix) {
+			return name[:len(name)-len(suffix)], true
+		}
+	}
+	return "", false
+}
+
+// IsSBOMFile returns true for release files holding an SBOM
+func IsSBOMFile(name string) bool {
+	_, ok := trimSuffix(name)
+	return ok
+}
+
+// hasPlatform returns true if a file name has an OS or arch label
+func hasPlatform(name string) bool {
+	for _, aliases := range system.OSAliases {
+		if aliases.ToRegex().MatchString(name) {
+			return true
+		}
+	}
+	for _, aliases := range system.ArchAliases {
+		if aliases.ToRegex().MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the SBOM of an artifact among the files of its release, or
+// an empty string when there is none. An SBOM named after the artifact is
+// preferred, then the most specific one named after a prefix of the
+// artifact (tool_linux_amd64.spdx.json for tool_linux_amd64.tar.gz) and,
+// last, the only SBOM of the release not naming a platform.
+func Find(files []string, artifact string) string {
+	artifact = strings.ToLower(artifact)
+	var prefixed, prefixBase string
+	var generic []string
+	for _, f := range files {
+		base, ok := trimSuffix(f)
+		if !ok {
+			continue
+		}
+		base = strings.ToLower(base)
+		if base == artifact {
+			return f
+		}
+		if len(base) > len(prefixBase) && len(artifact) > len(base) && strings.HasPrefix(artifact, base) {
+			if _, ok := system.FilenameSeparators[artifact[len(base):len(base)+1]]; ok {
+				prefixed, prefixBase = f, base
+			}
+		}
+		if !hasPlatform(base) {
+			generic = append(generic, f)
+		}
+	}
+	if prefixed != "" {
+		return prefixed
+	}
+	if len(generic) == 1 {
+		return generic[0]
+	}
+	return ""
+}
+
+// envelope is the part of a DSSE envelope, or of a sigstore bundle
+// wrapping one, that carries the statement.
+type envelope struct {
+	Payload      string    `json:"payload"`
+	DSSEEnvelope *envelope `json:"dsseEnvelope"`
+}
+
+// statement is the part of an in-toto statement listing its subjects
+type statement struct {
+	Subject []struct {
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// CheckAttestation checks that an SBOM is a subject of the in-toto
+// statements in an attestation file. Statements may be bare, wrapped in DSSE
+// envelopes or in sigstore bundles, one per line or as a single document.
+// Only the subject digest is checked, not the signatures of the statements.
+func CheckAttestation(attestation []byte, digest string) error {
+	found := false
+	dec := json.NewDecoder(bytes.NewReader(attestation))
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("parsing attestation: %w", err)
+		}
+		data, err := statementData(raw)
+		if err != nil {
+			return err
+		}
+		s := &statement{}
+		if err := json.Unmarshal(data, s); err != nil {
+			return fmt.Errorf("parsing attestation statement: %w", err)
+		}
+		for _, subject := range s.Subject {
+			found = true
+			if strings.EqualFold(subject.Digest["sha256"], digest) {
+				return nil
+			}
+		}
+	}
+	if !found {
+		return errors.New("attestation has no in-toto statements")
+	}
+	return ErrSubjectMismatch
+}
+
+// statementData unwraps the statement of an envelope or bundle, documents
+// with no payload are taken as the statement itself.
+func statementData(raw []byte) ([]byte, error) {
+	env := &envelope{}
+	if err := json.Unmarshal(raw, env); err != nil {
+		return nil, fmt.Errorf("parsing attestation: %w", err)
+	}
+	if env.DSSEEnvelope != nil {
+		env = env.DSSEEnvelope
+	}
+	if env.Payload == "" {
+		return raw, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation payload: %w", err)
+	}
+	return data, nil
+}
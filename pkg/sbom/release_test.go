@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		files    []string
+		artifact string
+		expect   string
+	}{
+		{
+			name:     "named-after-artifact",
+			files:    []string{"cosign-linux-amd64", "cosign-linux-amd64.sbom.json", "cosign-linux-arm64.sbom.json"},
+			artifact: "cosign-linux-amd64",
+			expect:   "cosign-linux-amd64.sbom.json",
+		},
+		{
+			name:     "archive-prefix",
+			files:    []string{"tool_linux_amd64.tar.gz", "tool_linux_amd64.spdx.json", "tool_linux_arm64.spdx.json"},
+			artifact: "tool_linux_amd64.tar.gz",
+			expect:   "tool_linux_amd64.spdx.json",
+		},
+		{
+			name:     "longest-prefix",
+			files:    []string{"tool.spdx.json", "tool_linux_amd64.spdx.json"},
+			artifact: "tool_linux_amd64.tar.gz",
+			expect:   "tool_linux_amd64.spdx.json",
+		},
+		{
+			name:     "release-wide",
+			files:    []string{"tool_linux_amd64.tar.gz", "sbom.cdx.json", "checksums.txt"},
+			artifact: "tool_linux_amd64.tar.gz",
+			expect:   "sbom.cdx.json",
+		},
+		{
+			name:     "other-platform-only",
+			files:    []string{"tool_linux_arm64.spdx.json", "tool_darwin_amd64.spdx.json"},
+			artifact: "tool_linux_amd64.tar.gz",
+		},
+		{
+			name:     "ambiguous-release-wide",
+			files:    []string{"source.spdx.json", "container.spdx.json"},
+			artifact: "tool_linux_amd64.tar.gz",
+		},
+		{
+			name:     "no-separator",
+			files:    []string{"tool.spdx.json", "toolbox_linux_amd64.spdx.json"},
+			artifact: "toolbox_linux_amd64_v2",
+			expect:   "toolbox_linux_amd64.spdx.json",
+		},
+		{name: "none", files: []string{"tool_linux_amd64.tar.gz"}, artifact: "tool_linux_amd64.tar.gz"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, Find(tc.files, tc.artifact))
+		})
+	}
+}
+
+func TestCheckAttestation(t *testing.T) {
+	t.Parallel()
+	digest := "5c8e1c0a2a6f1c7e7b0a6f3e2d1c0b9a8f7e6d5c4b3a29180706050403020100"
+	statement := `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"tool.spdx.json","digest":{"sha256":"` + digest + `"}}]}`
+	other := `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"tool","digest":{"sha256":"0000"}}]}`
+	payload := base64.StdEncoding.EncodeToString([]byte(statement))
+
+	for _, tc := range []struct {
+		name        string
+		attestation string
+		mustErr     bool
+	}{
+		{name: "statement", attestation: statement},
+		{name: "dsse", attestation: `{"payloadType":"application/vnd.in-toto+json","payload":"` + payload + `","signatures":[]}`},
+		{name: "bundle", attestation: `{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","dsseEnvelope":{"payload":"` + payload + `"}}`},
+		{name: "jsonl", attestation: other + "\n" + statement + "\n"},
+		{name: "mismatch", attestation: other, mustErr: true},
+		{name: "no-statements", attestation: `{"hello":"world"}`, mustErr: true},
+		{name: "invalid", attestation: `{`, mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := CheckAttestation([]byte(tc.attestation), digest)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
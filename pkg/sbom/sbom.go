@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sbom keeps the software bills of materials published along the
+// artifacts drop installs. SBOMs are keyed by the sha256 digest of the
+// installed artifact and live in drop's data directory, next to the
+// verification evidence. The package also finds the SBOM of an artifact
+// among its release files and converts SBOMs between SPDX and CycloneDX.
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+const dirName = "drop"
+
+var ErrNotFound = errors.New("no SBOM stored for artifact")
+
+// digestRegex matches the hex sha256 digests keying the entries
+var digestRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// Store is a directory of SBOMs
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the location of the SBOM store in the user's data
+// directory ($XDG_DATA_HOME or ~/.local/share).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, dirName, "sboms"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", dirName, "sboms"), nil
+}
+
+// Open returns the store in its default location
+func Open() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(dir), nil
+}
+
+// NewStore returns a store rooted at a directory
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// entryPath returns the path of the SBOM of a digest. The name comes from a
+// release asset so it must be a plain file name.
+func (s *Store) entryPath(digest, name string) (string, error) {
+	if !digestRegex.MatchString(digest) {
+		return "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." || name == "" {
+		return "", fmt.Errorf("invalid SBOM file name %q", name)
+	}
+	return filepath.Join(s.dir, "sha256", digest, name), nil
+}
+
+// Save stores the SBOM of an artifact, replacing any previous one
+func (s *Store) Save(digest, name string, data []byte) error {
+	path, err := s.entryPath(digest, name)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("removing previous SBOM: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("creating SBOM directory: %w", err)
+	}
+
+	// Write aside and rename so readers never see a partial SBOM
+	tmp := filepath.Join(dir, ".tmp-"+name)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing SBOM: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("storing SBOM: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the path of the SBOM stored for a digest
+func (s *Store) Lookup(digest, name string) (string, error) {
+	path, err := s.entryPath(digest, name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("reading SBOM: %w", err)
+	}
+	return path, nil
+}
+
+// Prune removes the SBOMs of the digests not in the keep list, returning
+// the number of entries removed.
+func (s *Store) Prune(keep []string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "sha256"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading SBOM store: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() || !digestRegex.MatchString(e.Name()) || slices.Contains(keep, e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.dir, "sha256", e.Name())); err != nil {
+			return removed, fmt.Errorf("removing SBOM: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
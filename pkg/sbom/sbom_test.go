@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sbom
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDigest  = strings.Repeat("a", 64)
+	otherDigest = strings.Repeat("b", 64)
+)
+
+const testSPDX = `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "drop",
+  "documentNamespace": "https://example.com/drop",
+  "creationInfo": {"created": "2025-01-01T00:00:00Z", "creators": ["Tool: test"]},
+  "documentDescribes": ["SPDXRef-Package-drop"],
+  "packages": [
+    {"name": "drop", "SPDXID": "SPDXRef-Package-drop", "versionInfo": "1.0.0", "downloadLocation": "NOASSERTION"}
+  ]
+}`
+
+const testCDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "components": [{"type": "library", "name": "drop", "version": "1.0.0"}]
+}`
+
+func TestSaveLookup(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir())
+
+	_, err := store.Lookup(testDigest, "drop.spdx.json")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Save(testDigest, "drop.spdx.json", []byte(testSPDX)))
+	path, err := store.Lookup(testDigest, "drop.spdx.json")
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, testSPDX, string(data))
+
+	// Saving again replaces the SBOM
+	require.NoError(t, store.Save(testDigest, "drop.cdx.json", []byte(testCDX)))
+	_, err = store.Lookup(testDigest, "drop.spdx.json")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Lookup(testDigest, "drop.cdx.json")
+	require.NoError(t, err)
+
+	for _, name := range []string{"../evil.spdx.json", "", ".."} {
+		require.Error(t, store.Save(testDigest, name, nil), name)
+	}
+	require.Error(t, store.Save("../../etc", "drop.spdx.json", nil))
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Save(testDigest, "drop.spdx.json", []byte(testSPDX)))
+	require.NoError(t, store.Save(otherDigest, "drop.spdx.json", []byte(testSPDX)))
+
+	removed, err := store.Prune([]string{testDigest})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	_, err = store.Lookup(testDigest, "drop.spdx.json")
+	require.NoError(t, err)
+	_, err = store.Lookup(otherDigest, "drop.spdx.json")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		expect  string
+		mustErr bool
+	}{
+		{name: "spdx", data: testSPDX, expect: FormatSPDX},
+		{name: "cyclonedx", data: testCDX, expect: FormatCycloneDX},
+		{name: "not-sbom", data: `{"hello": "world"}`, mustErr: true},
+		{name: "garbage", data: "not an sbom", mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			format, err := Detect([]byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, format)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		format  string
+		mustErr bool
+	}{
+		{name: "spdx-as-is", data: testSPDX, format: FormatSPDX},
+		{name: "spdx-to-cyclonedx", data: testSPDX, format: FormatCycloneDX},
+		{name: "cyclonedx-to-spdx", data: testCDX, format: FormatSPDX},
+		{name: "unknown-format", data: testSPDX, format: "swid", mustErr: true},
+		{name: "not-sbom", data: `{}`, format: FormatSPDX, mustErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			err := Convert([]byte(tc.data), tc.format, &buf)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			format, err := Detect(buf.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, tc.format, format)
+			require.Contains(t, buf.String(), "drop")
+		})
+	}
+}
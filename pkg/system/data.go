@@ -6,7 +6,7 @@ package system
 import "strings"
 
 var (
-	PackageTypes = []string{PackageRPM, PackageDeb, PackageApk, PackageDmg, PackageMSI}
+	PackageTypes = []string{PackageRPM, PackageDeb, PackageApk, PackagePacman, PackageDmg, PackageMSI}
 	ArchiveTypes = []string{ArchiveZip, ArchiveTar, ArchiveBz2, ArchiveGz, ArchiveXz, ArchiveRar, ArchiveL7, ArchiveTgz}
 )
 
@@ -24,13 +24,44 @@ var OSAliases = map[string]LabelList{
 
 // Arch alias maps
 var ArchAliases = map[string]LabelList{
-	ArchX8664:   {ArchX8664, ArchAMD64, Arch64Bit, ArchX64, ArchX86},
-	ArchArm64:   {ArchArm64, ArchAarch64},
-	ArchArm:     {ArchArm, ArchArmHF, ArchArmV7, ArchArmV7HL},
-	Arch386:     {Arch386, ArchI686, ArchI386, Arch32Bit},
-	ArchRiscV64: {ArchRiscV64},
-	ArchS390X:   {ArchS390X},
-	ArchPPC64LE: {ArchPPC64LE, ArchPPC64EL, ArchPPC64},
+	ArchX8664:     {ArchX8664, ArchAMD64, Arch64Bit, ArchX64, ArchX86},
+	ArchArm64:     {ArchArm64, ArchAarch64},
+	ArchArm:       {ArchArm, ArchArmHF, ArchArmV5, ArchArmV6, ArchArmV7, ArchArmV7HL},
+	Arch386:       {Arch386, ArchI686, ArchI386, Arch32Bit},
+	ArchRiscV64:   {ArchRiscV64},
+	ArchS390X:     {ArchS390X},
+	ArchPPC64LE:   {ArchPPC64LE, ArchPPC64EL, ArchPPC64},
+	ArchUniversal: {ArchUniversal},
+}
+
+// ArchFallback is an alternative arch whose artifacts can run on a
+// platform when no native variant is published.
+type ArchFallback struct {
+	Arch string
+
+	// Emulated is true when the fallback only runs through an emulation
+	// layer (Rosetta, qemu-user, Windows on ARM x64 emulation...)
+	Emulated bool
+}
+
+// ArchFallbacks captures the ordered fallback chains of each platform, keyed
+// by OS and native arch.
+var ArchFallbacks = map[string]map[string][]ArchFallback{
+	OSDarwin: {
+		ArchX8664: {{Arch: ArchUniversal}},
+		ArchArm64: {{Arch: ArchUniversal}, {Arch: ArchX8664, Emulated: true}},
+	},
+	OSLinux: {
+		ArchX8664: {{Arch: Arch386}},
+		ArchArm64: {{Arch: ArchX8664, Emulated: true}},
+	},
+	OSWindows: {
+		ArchX8664: {{Arch: Arch386}},
+		ArchArm64: {{Arch: ArchX8664, Emulated: true}, {Arch: Arch386, Emulated: true}},
+	},
+	OSFreeBSD: {
+		ArchX8664: {{Arch: Arch386}},
+	},
 }
 
 // Platform constants
@@ -64,6 +95,8 @@ const (
 
 	// Aliases
 	ArchArmHF   = "armhf"
+	ArchArmV5   = "armv5"
+	ArchArmV6   = "armv6"
 	ArchArmV7   = "armv7"
 	ArchArmV7HL = "armv7hl"
 	ArchAarch64 = "aarch64"
@@ -76,14 +109,34 @@ const (
 	ArchX64     = "x64"
 )
 
+// CPU feature levels. x86_64 uses the psABI microarchitecture levels,
+// 32 bit arm the architecture version and arm64 the ARMv8/v9 revisions.
+const (
+	ArchLevelX8664V1 = "v1"
+	ArchLevelX8664V2 = "v2"
+	ArchLevelX8664V3 = "v3"
+	ArchLevelX8664V4 = "v4"
+
+	ArchLevelArmV5 = "v5"
+	ArchLevelArmV6 = "v6"
+	ArchLevelArmV7 = "v7"
+
+	ArchLevelArm64V80 = "v8.0"
+	ArchLevelArm64V81 = "v8.1"
+	ArchLevelArm64V82 = "v8.2"
+	ArchLevelArm64V83 = "v8.3"
+	ArchLevelArm64V90 = "v9.0"
+)
+
 // Recognized package types
 const (
-	PackageRPM = "rpm"
-	PackageDeb = "deb"
-	PackageApk = "apk"
-	PackageDmg = "dmg"
-	PackageMSI = "msi"
-	PackageWhl = "whl" // Python wheel
+	PackageRPM    = "rpm"
+	PackageDeb    = "deb"
+	PackageApk    = "apk"
+	PackagePacman = "pacman" // Arch Linux (.pkg.tar.zst)
+	PackageDmg    = "dmg"
+	PackageMSI    = "msi"
+	PackageWhl    = "whl" // Python wheel
 
 	ArchiveZip = "zip"
 	ArchiveTar = "tar"
@@ -103,19 +156,48 @@ const (
 const (
 	OSFamilyAlpine     = "alpine"
 	OSFamilyAlma       = "alma"
+	OSFamilyAmazon     = "amazon"
 	OSFamilyArch       = "arch"
+	OSFamilyCentOS     = "centos"
 	OSFamilyDebian     = "debian"
 	OSFamilyDistroless = "distroless"
 	OSFamilyFedora     = "fedora"
+	OSFamilyOpenSUSE   = "opensuse"
+	OSFamilyOracle     = "oracle"
 	OSFamilyRocky      = "rocky"
 	OSFamilyUbuntu     = "ubuntu"
 	OSFamilyRHEL       = "rhel"
+	OSFamilySLES       = "sles"
 	OSFamilyWolfi      = "wolfi"
 
 	OSFamilyMacOS   = "macos"
 	OSFamilyWindows = "windows"
 )
 
+// OSReleaseFamilies maps the distribution identifiers found in the ID and
+// ID_LIKE fields of /etc/os-release to the OS families.
+var OSReleaseFamilies = map[string]string{
+	"alpine":              OSFamilyAlpine,
+	"almalinux":           OSFamilyAlma,
+	"amzn":                OSFamilyAmazon,
+	"arch":                OSFamilyArch,
+	"centos":              OSFamilyCentOS,
+	"debian":              OSFamilyDebian,
+	"distroless":          OSFamilyDistroless,
+	"fedora":              OSFamilyFedora,
+	"ol":                  OSFamilyOracle,
+	"opensuse":            OSFamilyOpenSUSE,
+	"opensuse-leap":       OSFamilyOpenSUSE,
+	"opensuse-tumbleweed": OSFamilyOpenSUSE,
+	"rhel":                OSFamilyRHEL,
+	"rocky":               OSFamilyRocky,
+	"sled":                OSFamilySLES,
+	"sles":                OSFamilySLES,
+	"suse":                OSFamilyOpenSUSE,
+	"ubuntu":              OSFamilyUbuntu,
+	"wolfi":               OSFamilyWolfi,
+}
+
 type ExtensionList map[string][]string
 
 func (el *ExtensionList) GetTypeFromFile(filename string) string {
@@ -146,19 +228,31 @@ func IsPackage(filename string) bool {
 	return t != ""
 }
 
-// IsArchive takes a filename and returns true if it matches a known archive type
+// IsArchive takes a filename and returns true if it matches a known archive type.
+// Packages shipped as compressed tarballs (pacman) are not considered archives.
 func IsArchive(filename string) bool {
 	t := ArchiveExtensions.GetTypeFromFile(filename)
-	return t != ""
+	return t != "" && !IsPackage(filename)
+}
+
+// AppImage is the type of self-contained Linux desktop apps. AppImages are
+// executables (an ELF runtime followed by a squashfs image), so they are
+// neither packages nor archives.
+const AppImage = "appimage"
+
+// IsAppImage takes a filename and returns true if it is an AppImage
+func IsAppImage(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), "."+AppImage)
 }
 
 var PackageExtensions = ExtensionList{
-	PackageRPM: {"rpm"},
-	PackageDeb: {"deb"},
-	PackageApk: {"apk"},
-	PackageDmg: {"dmg"},
-	PackageMSI: {"msi"},
-	PackageWhl: {"whl"},
+	PackageRPM:    {"rpm"},
+	PackageDeb:    {"deb"},
+	PackageApk:    {"apk"},
+	PackagePacman: {"pkg.tar.zst", "pkg.tar.xz"},
+	PackageDmg:    {"dmg"},
+	PackageMSI:    {"msi"},
+	PackageWhl:    {"whl"},
 }
 
 var ArchiveExtensions = ExtensionList{
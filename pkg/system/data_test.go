@@ -53,3 +53,28 @@ func TestGetTypeExtensionFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestPacmanPackages(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"drop-1.0.0-1-x86_64.pkg.tar.zst", "drop-1.0.0-1-x86_64.pkg.tar.xz"} {
+		require.Equal(t, PackagePacman, PackageExtensions.GetTypeFromFile(name))
+		require.True(t, IsPackage(name))
+		require.False(t, IsArchive(name))
+	}
+	require.True(t, IsArchive("drop-1.0.0-linux-amd64.tar.xz"))
+	require.Equal(t, PackagePacman, GetPreferredPackage(OSFamilyArch))
+}
+
+func TestIsAppImage(t *testing.T) {
+	t.Parallel()
+	for name, expect := range map[string]bool{
+		"Drop-1.0.0-x86_64.AppImage":       true,
+		"drop-1.0.0-aarch64.appimage":      true,
+		"drop-1.0.0-x86_64.AppImage.zsync": false,
+		"drop-linux-amd64":                 false,
+	} {
+		require.Equal(t, expect, IsAppImage(name), name)
+		require.False(t, IsPackage(name))
+		require.False(t, IsArchive(name))
+	}
+}
@@ -11,6 +11,13 @@ type Info struct {
 	Os     string
 	Arch   string
 	Family string
+
+	// OSVersion is the distribution version (VERSION_ID in os-release)
+	OSVersion string
+
+	// ArchLevel is the highest CPU feature level the machine supports
+	// (x86_64 v1-v4, arm v5-v7, arm64 v8.x).
+	ArchLevel string
 }
 
 // GetInfo returns information about the running system
@@ -19,5 +26,9 @@ func GetInfo() (*Info, error) {
 		Os:     runtime.GOOS,
 		Arch:   runtime.GOARCH,
 		Family: GetSystemOSFamily(),
+
+		OSVersion: GetSystemOSVersion(),
+
+		ArchLevel: GetArchLevel(),
 	}, nil
 }
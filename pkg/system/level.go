@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package system
+
+import (
+	"cmp"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/cpu"
+)
+
+// levelPatterns capture the feature level markers projects append to the
+// arch labels in their filenames (amd64v3, x86_64_v2, armv6, arm64-v8.2...)
+var levelPatterns = map[string]*regexp.Regexp{
+	ArchX8664: regexp.MustCompile(`(?i)(?:x86_64|x86-64|amd64|x64)[-_]?(v[1-4])(?:[^0-9]|$)`),
+	ArchArm:   regexp.MustCompile(`(?i)arm(?:hf)?(?:v|[-_]v?)([5-7])(?:[^0-9]|$)`),
+	ArchArm64: regexp.MustCompile(`(?i)(?:arm64|aarch64)[-_]?(v[89](?:\.[0-9])?)(?:[^0-9]|$)`),
+}
+
+// GetArchLevelFromFilename looks for a CPU feature level marker for the
+// specified (canonical) arch in a filename. It returns an empty string when
+// the file does not specify a level.
+func GetArchLevelFromFilename(arch, filename string) string {
+	re, ok := levelPatterns[arch]
+	if !ok {
+		return ""
+	}
+	m := re.FindStringSubmatch(filename)
+	if m == nil {
+		return ""
+	}
+	return NormalizeArchLevel(arch, m[1])
+}
+
+// NormalizeArchLevel returns the canonical form of a level label for an
+// arch or an empty string if the level is not valid for it.
+func NormalizeArchLevel(arch, level string) string {
+	level = strings.ToLower(level)
+	if level != "" && !strings.HasPrefix(level, "v") {
+		level = "v" + level
+	}
+	switch arch {
+	case ArchX8664:
+		switch level {
+		case ArchLevelX8664V1, ArchLevelX8664V2, ArchLevelX8664V3, ArchLevelX8664V4:
+			return level
+		}
+	case ArchArm:
+		switch level {
+		case ArchLevelArmV5, ArchLevelArmV6, ArchLevelArmV7:
+			return level
+		}
+	case ArchArm64:
+		if level == "v8" || level == "v9" {
+			level += ".0"
+		}
+		if major, minor, ok := parseArchLevel(level); ok && (major == 8 || major == 9) && minor < 10 {
+			return level
+		}
+	}
+	return ""
+}
+
+// parseArchLevel splits a level label (v3, v8.2) into its numeric parts
+func parseArchLevel(level string) (major, minor int, ok bool) {
+	if !strings.HasPrefix(level, "v") {
+		return 0, 0, false
+	}
+	maj, mnr, hasMinor := strings.Cut(level[1:], ".")
+	major, err := strconv.Atoi(maj)
+	if err != nil {
+		return 0, 0, false
+	}
+	if hasMinor {
+		minor, err = strconv.Atoi(mnr)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return major, minor, true
+}
+
+// CompareArchLevels compares two levels of the same arch. An empty level
+// denotes an artifact built for the architecture baseline, so it sorts
+// before any explicit level.
+func CompareArchLevels(a, b string) int {
+	amaj, amin, aok := parseArchLevel(a)
+	bmaj, bmin, bok := parseArchLevel(b)
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return -1
+	case !bok:
+		return 1
+	}
+	if c := cmp.Compare(amaj, bmaj); c != 0 {
+		return c
+	}
+	return cmp.Compare(amin, bmin)
+}
+
+// ArchLevelWeight returns a number that grows with the level (v3 is 30,
+// v8.2 is 82) to weigh levels when ranking artifacts. Empty or invalid
+// levels weigh zero.
+func ArchLevelWeight(level string) int {
+	major, minor, ok := parseArchLevel(level)
+	if !ok {
+		return 0
+	}
+	return major*10 + minor
+}
+
+// ArchLevelSupported returns true if an artifact built for level can run
+// on a machine supporting up to maxLevel. When either is unknown the
+// artifact is assumed to be compatible.
+func ArchLevelSupported(level, maxLevel string) bool {
+	if level == "" || maxLevel == "" {
+		return true
+	}
+	return CompareArchLevels(level, maxLevel) <= 0
+}
+
+// GetArchLevel returns the highest CPU feature level the local machine
+// supports for its architecture. It returns an empty string on arches
+// without levels or when the level cannot be determined.
+func GetArchLevel() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return x8664Level()
+	case "arm":
+		return armLevel()
+	case "arm64":
+		return arm64Level()
+	default:
+		return ""
+	}
+}
+
+// x8664Level maps the x86 CPU features to the x86-64 psABI levels
+func x8664Level() string {
+	x := cpu.X86
+	if !x.HasCX16 || !x.HasPOPCNT || !x.HasSSE3 || !x.HasSSSE3 || !x.HasSSE41 || !x.HasSSE42 {
+		return ArchLevelX8664V1
+	}
+	if !x.HasAVX || !x.HasAVX2 || !x.HasBMI1 || !x.HasBMI2 || !x.HasFMA || !x.HasOSXSAVE {
+		return ArchLevelX8664V2
+	}
+	if !x.HasAVX512F || !x.HasAVX512BW || !x.HasAVX512CD || !x.HasAVX512DQ || !x.HasAVX512VL {
+		return ArchLevelX8664V3
+	}
+	return ArchLevelX8664V4
+}
+
+// armLevel infers the 32 bit ARM architecture version from the floating
+// point units reported by the kernel.
+func armLevel() string {
+	switch {
+	case cpu.ARM.HasVFPv3 || cpu.ARM.HasNEON:
+		return ArchLevelArmV7
+	case cpu.ARM.HasVFP:
+		return ArchLevelArmV6
+	case runtime.GOOS == OSLinux:
+		return ArchLevelArmV5
+	default:
+		// Feature flags are only populated on linux
+		return ""
+	}
+}
+
+// arm64Level maps the ARM64 feature flags to the architecture revisions
+// that made them mandatory.
+func arm64Level() string {
+	a := cpu.ARM64
+	switch {
+	case a.HasSVE2:
+		return ArchLevelArm64V90
+	case a.HasJSCVT && a.HasFCMA && a.HasLRCPC && a.HasDCPOP && a.HasATOMICS:
+		return ArchLevelArm64V83
+	case a.HasDCPOP && a.HasATOMICS && a.HasASIMDRDM:
+		return ArchLevelArm64V82
+	case a.HasATOMICS && a.HasASIMDRDM:
+		return ArchLevelArm64V81
+	default:
+		return ArchLevelArm64V80
+	}
+}
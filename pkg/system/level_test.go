@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArchLevelFromFilename(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		arch     string
+		filename string
+		expect   string
+	}{
+		{"amd64-glued", ArchX8664, "app-linux-amd64v3", ArchLevelX8664V3},
+		{"x86_64-underscore", ArchX8664, "app_linux_x86_64_v2.tar.gz", ArchLevelX8664V2},
+		{"amd64-goreleaser", ArchX8664, "app_linux_amd64_v1.tar.gz", ArchLevelX8664V1},
+		{"amd64-none", ArchX8664, "app-linux-amd64", ""},
+		{"amd64-invalid", ArchX8664, "app-linux-amd64v5", ""},
+		{"armv6", ArchArm, "app-linux-armv6", ArchLevelArmV6},
+		{"armv7hl", ArchArm, "app-1.0-1.armv7hl.rpm", ArchLevelArmV7},
+		{"arm-goreleaser", ArchArm, "app_linux_arm_6.tar.gz", ArchLevelArmV6},
+		{"arm-none", ArchArm, "app-linux-arm", ""},
+		{"arm64-minor", ArchArm64, "app-linux-arm64-v8.2", ArchLevelArm64V82},
+		{"arm64-major", ArchArm64, "app-linux-arm64v8", ArchLevelArm64V80},
+		{"aarch64-none", ArchArm64, "app-linux-aarch64.tar.gz", ""},
+		{"no-levels", ArchRiscV64, "app-linux-riscv64", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, GetArchLevelFromFilename(tc.arch, tc.filename))
+		})
+	}
+}
+
+func TestArchLevelSupported(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		level    string
+		maxLevel string
+		expect   bool
+	}{
+		{"baseline", "", ArchLevelX8664V2, true},
+		{"unknown-host", ArchLevelX8664V4, "", true},
+		{"lower", ArchLevelX8664V2, ArchLevelX8664V3, true},
+		{"same", ArchLevelX8664V3, ArchLevelX8664V3, true},
+		{"higher", ArchLevelX8664V4, ArchLevelX8664V3, false},
+		{"arm64-minor-higher", ArchLevelArm64V82, ArchLevelArm64V81, false},
+		{"arm64-minor-lower", ArchLevelArm64V81, ArchLevelArm64V90, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, ArchLevelSupported(tc.level, tc.maxLevel))
+		})
+	}
+}
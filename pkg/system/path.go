@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SystemBinDir is where binaries are installed system-wide
+const SystemBinDir = "/usr/local/bin"
+
+// Shells supported when setting up the PATH
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// PathMarker tags the lines drop adds to shell rc files
+const PathMarker = "# Added by drop: user binaries directory"
+
+// UserBinDir returns the per-user binaries directory: $XDG_BIN_HOME when set
+// or ~/.local/bin (as defined in the XDG base directory spec).
+func UserBinDir() (string, error) {
+	if dir := os.Getenv("XDG_BIN_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "bin"), nil
+}
+
+// pathEntries splits a PATH value into its cleaned entries
+func pathEntries(pathEnv string) []string {
+	ret := []string{}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir != "" {
+			ret = append(ret, filepath.Clean(dir))
+		}
+	}
+	return ret
+}
+
+// DirInPath returns true when a directory is listed in the PATH value
+func DirInPath(dir, pathEnv string) bool {
+	return slices.Contains(pathEntries(pathEnv), filepath.Clean(dir))
+}
+
+// ShadowingBinary returns the path of an executable with the same name as
+// the binary at target found in a PATH entry listed before target's
+// directory. It returns an empty string when the binary is not shadowed or
+// its directory is not in the PATH.
+func ShadowingBinary(target, pathEnv string) string {
+	dir, name := filepath.Split(target)
+	dir = filepath.Clean(dir)
+	if !DirInPath(dir, pathEnv) {
+		return ""
+	}
+	for _, entry := range pathEntries(pathEnv) {
+		if entry == dir {
+			return ""
+		}
+		candidate := filepath.Join(entry, name)
+		st, err := os.Stat(candidate)
+		if err != nil || st.IsDir() || (st.Mode().Perm()&0o111 == 0 && !strings.HasSuffix(name, ".exe")) {
+			continue
+		}
+		// Symlinks to the installed binary are not shadowing it
+		if same, err := os.Stat(target); err == nil && os.SameFile(st, same) {
+			return ""
+		}
+		return candidate
+	}
+	return ""
+}
+
+// ShellFromEnv returns the name of the user's shell from $SHELL
+func ShellFromEnv() string {
+	return filepath.Base(os.Getenv("SHELL"))
+}
+
+// PathSnippet returns the shell code that adds a directory to the PATH
+func PathSnippet(shell, dir string) (string, error) {
+	switch shell {
+	case ShellBash, ShellZsh:
+		return fmt.Sprintf("%s\nexport PATH=%q:\"$PATH\"\n", PathMarker, dir), nil
+	case ShellFish:
+		return fmt.Sprintf("%s\nfish_add_path --path %q\n", PathMarker, dir), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q, supported shells are bash, zsh and fish", shell)
+	}
+}
+
+// ShellRCFile returns the startup file where PATH changes go for a shell
+func ShellRCFile(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	switch shell {
+	case ShellBash:
+		return filepath.Join(home, ".bashrc"), nil
+	case ShellZsh:
+		if dir := os.Getenv("ZDOTDIR"); dir != "" {
+			return filepath.Join(dir, ".zshrc"), nil
+		}
+		return filepath.Join(home, ".zshrc"), nil
+	case ShellFish:
+		config, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving configuration directory: %w", err)
+		}
+		return filepath.Join(config, "fish", "config.fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q, supported shells are bash, zsh and fish", shell)
+	}
+}
+
+// AppendPathSnippet adds the PATH snippet for a directory to a shell rc file
+// unless the file already has it. It returns true when the file was changed.
+func AppendPathSnippet(rcFile, shell, dir string) (bool, error) {
+	snippet, err := PathSnippet(shell, dir)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(rcFile) //nolint:gosec // the user's own rc file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("reading %s: %w", rcFile, err)
+	}
+	if strings.Contains(string(data), snippet) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0o755); err != nil {
+		return false, fmt.Errorf("creating %s: %w", filepath.Dir(rcFile), err)
+	}
+	f, err := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // rc files are not secret
+	if err != nil {
+		return false, fmt.Errorf("opening %s: %w", rcFile, err)
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		snippet = "\n" + snippet
+	}
+	if _, err := f.WriteString("\n" + snippet); err != nil {
+		f.Close() //nolint:errcheck,gosec
+		return false, fmt.Errorf("writing %s: %w", rcFile, err)
+	}
+	return true, f.Close()
+}
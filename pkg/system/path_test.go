@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirInPath(t *testing.T) {
+	t.Parallel()
+	pathEnv := strings.Join([]string{"/usr/bin", "/home/u/.local/bin/", "", "/bin"}, string(os.PathListSeparator))
+	require.True(t, DirInPath("/home/u/.local/bin", pathEnv))
+	require.True(t, DirInPath("/usr/bin/", pathEnv))
+	require.False(t, DirInPath("/usr/local/bin", pathEnv))
+}
+
+func TestShadowingBinary(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on windows")
+	}
+	early, user := t.TempDir(), t.TempDir()
+	target := filepath.Join(user, "drop")
+	require.NoError(t, os.WriteFile(target, []byte("new"), 0o755)) //nolint:gosec
+
+	pathEnv := strings.Join([]string{early, user}, string(os.PathListSeparator))
+	require.Empty(t, ShadowingBinary(target, pathEnv))
+
+	// A non executable file does not shadow
+	require.NoError(t, os.WriteFile(filepath.Join(early, "drop"), []byte("old"), 0o600))
+	require.Empty(t, ShadowingBinary(target, pathEnv))
+
+	require.NoError(t, os.Chmod(filepath.Join(early, "drop"), 0o755)) //nolint:gosec
+	require.Equal(t, filepath.Join(early, "drop"), ShadowingBinary(target, pathEnv))
+
+	// Entries after the install directory don't matter
+	pathEnv = strings.Join([]string{user, early}, string(os.PathListSeparator))
+	require.Empty(t, ShadowingBinary(target, pathEnv))
+
+	// Not in the PATH at all
+	require.Empty(t, ShadowingBinary(target, early))
+}
+
+func TestAppendPathSnippet(t *testing.T) {
+	t.Parallel()
+	for _, shell := range []string{ShellBash, ShellZsh, ShellFish} {
+		t.Run(shell, func(t *testing.T) {
+			t.Parallel()
+			rc := filepath.Join(t.TempDir(), "rc")
+			require.NoError(t, os.WriteFile(rc, []byte("alias ll='ls -l'"), 0o600))
+
+			changed, err := AppendPathSnippet(rc, shell, "/home/u/.local/bin")
+			require.NoError(t, err)
+			require.True(t, changed)
+
+			changed, err = AppendPathSnippet(rc, shell, "/home/u/.local/bin")
+			require.NoError(t, err)
+			require.False(t, changed)
+
+			data, err := os.ReadFile(rc)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(data), "alias ll='ls -l'\n"))
+			require.Equal(t, 1, strings.Count(string(data), PathMarker))
+			require.Contains(t, string(data), "/home/u/.local/bin")
+		})
+	}
+
+	_, err := AppendPathSnippet(filepath.Join(t.TempDir(), "rc"), "tcsh", "/bin")
+	require.Error(t, err)
+}
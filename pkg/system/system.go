@@ -15,6 +15,9 @@ import (
 	"sync"
 )
 
+// levelSuffix matches an optional feature level appended to an arch label
+const levelSuffix = `(?:v\d+(?:\.\d+)?)?`
+
 var (
 	regexCache         = sync.Map{}
 	FilenameSeparators = map[string]string{
@@ -46,6 +49,12 @@ func GetOS(label string) string {
 	return ""
 }
 
+// GetArchFallbacks returns the fallback chain of a platform, the list of
+// alternative arches that can run on it, in order of preference.
+func GetArchFallbacks(osName, arch string) []ArchFallback {
+	return slices.Clone(ArchFallbacks[osName][arch])
+}
+
 // MainSplitPattern dynamically builds a regex pattern with the know OS and arch
 // patterns to split and parse filenames to deduct platform, kind and other data.
 func MainSplitPattern() string {
@@ -96,7 +105,8 @@ func (ll *LabelList) ToRegex() *regexp.Regexp {
 	// to avoid a match of "arm" on "arm64.exe
 	sepChars := `[` + strings.Join(chrs, "") + `]`
 	for i := range list {
-		item := list[i]
+		// Labels may carry a CPU feature level glued to them (amd64v3)
+		item := list[i] + levelSuffix
 		list[i] = item + sepChars
 		// ... but also the arch at end of the string
 		list[i] += "|" + item + "$"
@@ -125,8 +135,11 @@ func GetPreferredPackage(family string) string {
 		return PackageApk
 	case OSFamilyDebian, OSFamilyUbuntu:
 		return PackageDeb
-	case OSFamilyAlma, OSFamilyArch, OSFamilyFedora, OSFamilyRocky, OSFamilyRHEL:
+	case OSFamilyAlma, OSFamilyAmazon, OSFamilyCentOS, OSFamilyFedora,
+		OSFamilyOpenSUSE, OSFamilyOracle, OSFamilyRocky, OSFamilyRHEL, OSFamilySLES:
 		return PackageRPM
+	case OSFamilyArch:
+		return PackagePacman
 	case OSFamilyMacOS:
 		return PackageDmg
 	case OSFamilyWindows:
@@ -136,10 +149,18 @@ func GetPreferredPackage(family string) string {
 	}
 }
 
-// parseOSRelease returns the
-func parseOSReleaseForFamily(r io.Reader) string {
+// osRelease holds the fields of /etc/os-release used to identify the system
+type osRelease struct {
+	ID        string
+	IDLike    []string
+	VersionID string
+}
+
+// parseOSRelease reads the identification fields from an os-release file
+func parseOSRelease(r io.Reader) *osRelease {
+	osr := &osRelease{}
 	if r == nil {
-		return ""
+		return osr
 	}
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -148,43 +169,58 @@ func parseOSReleaseForFamily(r io.Reader) string {
 			continue
 		}
 
-		if k != "ID" {
-			continue
-		}
 		v = strings.TrimSpace(v)
 		if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
 			v = strings.TrimPrefix(v, `"`)
 			v = strings.TrimSuffix(v, `"`)
 		}
 
-		switch v {
-		case "alpine":
-			return OSFamilyAlpine
-		case "almalinux":
-			return OSFamilyAlma
-		case "arch":
-			return OSFamilyArch
-		case "fedora":
-			return OSFamilyFedora
-		case "debian":
-			return OSFamilyDebian
-		case "distroless":
-			return OSFamilyDistroless
-		case "rocky":
-			return OSFamilyRocky
-		case "rhel":
-			return OSFamilyRHEL
-		case "ubuntu":
-			return OSFamilyUbuntu
-		case "wolfi":
-			return OSFamilyWolfi
-		default:
-			return ""
+		switch k {
+		case "ID":
+			osr.ID = strings.ToLower(v)
+		case "ID_LIKE":
+			osr.IDLike = strings.Fields(strings.ToLower(v))
+		case "VERSION_ID":
+			osr.VersionID = v
+		}
+	}
+	return osr
+}
+
+// Family returns the OS family of the distribution. When the ID is not
+// known, the distributions it derives from (ID_LIKE) are tried in order so
+// derivatives like Linux Mint or Pop!_OS resolve to their parent family.
+func (osr *osRelease) Family() string {
+	if osr == nil {
+		return ""
+	}
+	if fam, ok := OSReleaseFamilies[osr.ID]; ok {
+		return fam
+	}
+	for _, id := range osr.IDLike {
+		if fam, ok := OSReleaseFamilies[id]; ok {
+			return fam
 		}
 	}
 	return ""
 }
 
+// parseOSReleaseForFamily returns the OS family from an os-release file
+func parseOSReleaseForFamily(r io.Reader) string {
+	return parseOSRelease(r).Family()
+}
+
+// readOSRelease parses the local /etc/os-release file, it returns nil when
+// the file cannot be read.
+func readOSRelease() *osRelease {
+	f, err := os.Open("/etc/os-release")
+	if err != nil {
+		return nil
+	}
+	defer f.Close() //nolint:errcheck
+	return parseOSRelease(f)
+}
+
 // GetSystemOSFamily returns the constant representing the local system
 func GetSystemOSFamily() string {
 	// We don't really have families on these two
@@ -196,10 +232,51 @@ func GetSystemOSFamily() string {
 	}
 
 	// If not win or max, parse the OS release file
-	f, err := os.Open("/etc/os-release")
-	if err != nil {
+	return readOSRelease().Family()
+}
+
+// GetSystemOSVersion returns the version of the local linux distribution
+// (VERSION_ID in /etc/os-release) or an empty string when unknown.
+func GetSystemOSVersion() string {
+	if runtime.GOOS != OSLinux {
 		return ""
 	}
-	defer f.Close() //nolint:errcheck
-	return parseOSReleaseForFamily(f)
+	osr := readOSRelease()
+	if osr == nil {
+		return ""
+	}
+	return osr.VersionID
+}
+
+// distroTagRegex matches a distro version tag inside a package filename
+var distroTagRegex = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])((?:el|fc|amzn)[0-9]+)(?:[^0-9]|$)`)
+
+// GetDistroTags returns the tags packagers use in filenames to mark builds
+// for a distribution version (el9, fc40, amzn2023). It returns nil when the
+// family does not use them or the version is unknown.
+func GetDistroTags(family, versionID string) []string {
+	major, _, _ := strings.Cut(versionID, ".")
+	if major == "" {
+		return nil
+	}
+	switch family {
+	case OSFamilyRHEL, OSFamilyAlma, OSFamilyCentOS, OSFamilyOracle, OSFamilyRocky:
+		return []string{"el" + major}
+	case OSFamilyFedora:
+		return []string{"fc" + major}
+	case OSFamilyAmazon:
+		return []string{"amzn" + major}
+	default:
+		return nil
+	}
+}
+
+// GetDistroTagFromFilename returns the distro version tag (el9, fc40...)
+// in a filename or an empty string if it has none.
+func GetDistroTagFromFilename(filename string) string {
+	m := distroTagRegex.FindStringSubmatch(filename)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[1])
 }
@@ -9,7 +9,7 @@ import (
 
 func TestMainSplitPattern(t *testing.T) {
 	s := MainSplitPattern()
-	require.Equal(t, "(?i)(aarch64|armv7hl|freebsd|illumos|openbsd|ppc64el|ppc64le|riscv64|solaris|windows|darwin|netbsd|x86_64|32bit|64bit|amd64|arm64|armhf|armv7|linux|macos|ppc64|s390x|i386|i686|386|arm|osx|x64|x86)", s)
+	require.Equal(t, "(?i)(universal|aarch64|armv7hl|freebsd|illumos|openbsd|ppc64el|ppc64le|riscv64|solaris|windows|darwin|netbsd|x86_64|32bit|64bit|amd64|arm64|armhf|armv5|armv6|armv7|linux|macos|ppc64|s390x|i386|i686|386|arm|osx|x64|x86)", s)
 }
 
 func TestParseOSReleaseForFamily(t *testing.T) {
@@ -22,6 +22,11 @@ func TestParseOSReleaseForFamily(t *testing.T) {
 		{"alpine", "testdata/alpine.osrelease.txt", OSFamilyAlpine},
 		{"fedora", "testdata/fedora.osrelease.txt", OSFamilyFedora},
 		{"ubi", "testdata/ubi.osrelease.txt", OSFamilyRHEL},
+		{"mint-id-like", "testdata/mint.osrelease.txt", OSFamilyUbuntu},
+		{"amazon", "testdata/amzn.osrelease.txt", OSFamilyAmazon},
+		{"opensuse", "testdata/opensuse.osrelease.txt", OSFamilyOpenSUSE},
+		{"sles", "testdata/sles.osrelease.txt", OSFamilySLES},
+		{"unknown", "testdata/unknown.osrelease.txt", ""},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -33,3 +38,30 @@ func TestParseOSReleaseForFamily(t *testing.T) {
 		})
 	}
 }
+
+func TestParseOSReleaseVersion(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/ubi.osrelease.txt")
+	require.NoError(t, err)
+
+	osr := parseOSRelease(f)
+	require.Equal(t, "rhel", osr.ID)
+	require.Equal(t, []string{"fedora"}, osr.IDLike)
+	require.Equal(t, "9.5", osr.VersionID)
+	require.Equal(t, []string{"el9"}, GetDistroTags(osr.Family(), osr.VersionID))
+}
+
+func TestGetDistroTagFromFilename(t *testing.T) {
+	t.Parallel()
+	for filename, expected := range map[string]string{
+		"drop-1.0.0-1.el9.x86_64.rpm":       "el9",
+		"drop-1.0.0-1.el9_4.x86_64.rpm":     "el9",
+		"drop-1.0.0-1.fc40.x86_64.rpm":      "fc40",
+		"drop-1.0.0-1.amzn2023.aarch64.rpm": "amzn2023",
+		"drop-1.0.0-1.x86_64.rpm":           "",
+		"drop-shell-1.0.0.x86_64.rpm":       "",
+		"drop_1.0.0_amd64.deb":              "",
+	} {
+		require.Equal(t, expected, GetDistroTagFromFilename(filename), filename)
+	}
+}
@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const dirName = "drop"
+
+var ErrNoDatabase = errors.New("vulnerability database not found")
+
+// Database is a local copy of OSV records: a directory holding the JSON
+// records and the zip archives OSV publishes per ecosystem
+// (https://osv-vulnerabilities.storage.googleapis.com/<ecosystem>/all.zip),
+// or a single archive.
+type Database struct {
+	path string
+}
+
+// DefaultDir returns the location of the database in the user's data
+// directory ($XDG_DATA_HOME or ~/.local/share).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, dirName, "osv"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", dirName, "osv"), nil
+}
+
+// OpenDatabase returns the database at a path, its default location when
+// empty. ErrNoDatabase is returned if it does not exist.
+func OpenDatabase(path string) (*Database, error) {
+	if path == "" {
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		path = dir
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w at %s", ErrNoDatabase, path)
+		}
+		return nil, fmt.Errorf("opening vulnerability database: %w", err)
+	}
+	return &Database{path: path}, nil
+}
+
+// Path returns the location of the database
+func (db *Database) Path() string {
+	return db.path
+}
+
+// Scan looks up packages in the database, returning the vulnerabilities
+// affecting them. The records are streamed, the database is never held in
+// memory.
+func (db *Database) Scan(pkgs []*Package) ([]*Finding, error) {
+	index := map[string][]*Package{}
+	for _, p := range pkgs {
+		index[p.key()] = append(index[p.key()], p)
+	}
+	s := &scan{index: index, seen: map[string]bool{}}
+
+	info, err := os.Stat(db.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening vulnerability database: %w", err)
+	}
+	if !info.IsDir() {
+		if err := s.file(db.path); err != nil {
+			return nil, err
+		}
+		return s.findings, nil
+	}
+	err = filepath.WalkDir(db.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return s.file(path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability database: %w", err)
+	}
+	return s.findings, nil
+}
+
+// scan matches the records read from the database against the packages
+type scan struct {
+	index    map[string][]*Package
+	seen     map[string]bool
+	findings []*Finding
+}
+
+// file reads the records in a database file, other files are ignored
+func (s *scan) file(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".zip":
+		return s.archive(path)
+	case ".json":
+		f, err := os.Open(path) //nolint:gosec // reading the database is the point
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close() //nolint:errcheck
+		return s.record(path, f)
+	default:
+		return nil
+	}
+}
+
+// archive reads the records in an OSV zip archive
+func (s *scan) archive(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer zr.Close() //nolint:errcheck
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s from %s: %w", f.Name, path, err)
+		}
+		err = s.record(f.Name, r)
+		r.Close() //nolint:errcheck,gosec
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record decodes an OSV record and adds the findings it produces
+func (s *scan) record(name string, r io.Reader) error {
+	e := &entry{}
+	if err := json.NewDecoder(r).Decode(e); err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	if e.Withdrawn != "" {
+		return nil
+	}
+	for i := range e.Affected {
+		a := &e.Affected[i]
+		for _, key := range a.keys() {
+			for _, p := range s.index[key] {
+				id := e.ID + "\x00" + p.key() + "\x00" + p.Version
+				if s.seen[id] || !a.contains(p.Version) {
+					continue
+				}
+				s.seen[id] = true
+				s.findings = append(s.findings, &Finding{
+					ID:       e.ID,
+					Aliases:  slices.Clone(e.Aliases),
+					Summary:  e.Summary,
+					Severity: e.severity(),
+					Package:  p,
+					Fixed:    a.fixed(p.Version),
+				})
+			}
+		}
+	}
+	return nil
+}
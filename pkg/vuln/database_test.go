@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGoRecord = `{
+  "id": "GO-2025-0001",
+  "aliases": ["CVE-2025-0001"],
+  "summary": "Request smuggling in net/http",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"}],
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.23.0"}]}]
+  }]
+}`
+
+const testGitRecord = `{
+  "id": "OSV-2025-0002",
+  "summary": "Path traversal",
+  "database_specific": {"severity": "LOW"},
+  "affected": [{
+    "versions": ["v1.0.0"],
+    "ranges": [{"type": "GIT", "repo": "https://github.com/Org/Repo.git", "events": [{"introduced": "0"}]}]
+  }]
+}`
+
+const testWithdrawnRecord = `{
+  "id": "GO-2025-0003",
+  "withdrawn": "2025-02-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+  }]
+}`
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OSV-2025-0002.json"), []byte(testGitRecord), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a record"), 0o600))
+
+	// The Go records are in an ecosystem archive
+	f, err := os.Create(filepath.Join(dir, "Go.zip"))
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, data := range map[string]string{
+		"GO-2025-0001.json": testGoRecord,
+		"GO-2025-0003.json": testWithdrawnRecord,
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	db, err := OpenDatabase(dir)
+	require.NoError(t, err)
+
+	app := AppPackages("github.com", "org", "repo", "v1.0.0")
+	net := &Package{Ecosystem: EcosystemGo, Name: "golang.org/x/net", Version: "v0.20.0"}
+	fixedNet := &Package{Ecosystem: EcosystemGo, Name: "golang.org/x/net", Version: "v0.23.0"}
+	findings, err := db.Scan(append(app, net, fixedNet))
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	byID := map[string]*Finding{}
+	for _, f := range findings {
+		byID[f.ID] = f
+	}
+	require.Contains(t, byID, "GO-2025-0001")
+	require.Equal(t, net, byID["GO-2025-0001"].Package)
+	require.Equal(t, SeverityHigh, byID["GO-2025-0001"].Severity)
+	require.Equal(t, "0.23.0", byID["GO-2025-0001"].Fixed)
+	require.Equal(t, []string{"GO-2025-0001", "CVE-2025-0001"}, byID["GO-2025-0001"].IDs())
+
+	require.Contains(t, byID, "OSV-2025-0002")
+	require.Equal(t, app[0], byID["OSV-2025-0002"].Package)
+	require.Equal(t, SeverityLow, byID["OSV-2025-0002"].Severity)
+}
+
+func TestOpenDatabase(t *testing.T) {
+	t.Parallel()
+	_, err := OpenDatabase(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, ErrNoDatabase)
+}
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	gocvss30 "github.com/pandatix/go-cvss/30"
+	gocvss31 "github.com/pandatix/go-cvss/31"
+	gocvss40 "github.com/pandatix/go-cvss/40"
+)
+
+// OSV range types drop can evaluate. ECOSYSTEM ranges are compared as
+// semantic versions, which holds for most language ecosystems.
+const (
+	rangeSemver    = "SEMVER"
+	rangeEcosystem = "ECOSYSTEM"
+	rangeGit       = "GIT"
+)
+
+// entry is the part of an OSV record drop reads
+type entry struct {
+	ID        string      `json:"id"`
+	Aliases   []string    `json:"aliases"`
+	Summary   string      `json:"summary"`
+	Withdrawn string      `json:"withdrawn"`
+	Severity  []severity  `json:"severity"`
+	Affected  []affected  `json:"affected"`
+	Database  specificSev `json:"database_specific"`
+}
+
+type severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// specificSev is the severity rating some databases (GitHub advisories
+// among them) add to their records.
+type specificSev struct {
+	Severity string `json:"severity"`
+}
+
+type affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges    []osvRange  `json:"ranges"`
+	Versions  []string    `json:"versions"`
+	Ecosystem specificSev `json:"ecosystem_specific"`
+}
+
+type osvRange struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo"`
+	Events []event `json:"events"`
+}
+
+type event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// keys returns the package keys an affected entry applies to: its package
+// and, for git ranges, the repository.
+func (a *affected) keys() []string {
+	ret := []string{}
+	if a.Package.Name != "" {
+		ret = append(ret, packageKey(a.Package.Ecosystem, a.Package.Name))
+	}
+	for _, r := range a.Ranges {
+		if r.Type == rangeGit && r.Repo != "" {
+			ret = append(ret, packageKey(EcosystemGit, r.Repo))
+		}
+	}
+	return ret
+}
+
+// contains returns true if a version is affected. The enumerated versions
+// are checked first, git ranges can only be matched through them as tags
+// cannot be ordered.
+func (a *affected) contains(version string) bool {
+	for _, v := range a.Versions {
+		if sameVersion(v, version) {
+			return true
+		}
+	}
+	for i := range a.Ranges {
+		if a.Ranges[i].contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixed returns the first version after an affected one that fixes the
+// vulnerability, or an empty string when no fix is known.
+func (a *affected) fixed(version string) string {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return ""
+	}
+	var ret *semver.Version
+	var fix string
+	for _, r := range a.Ranges {
+		if r.Type == rangeGit {
+			continue
+		}
+		for _, e := range r.Events {
+			fv, err := semver.NewVersion(e.Fixed)
+			if e.Fixed == "" || err != nil || !fv.GreaterThan(v) {
+				continue
+			}
+			if ret == nil || fv.LessThan(ret) {
+				ret, fix = fv, e.Fixed
+			}
+		}
+	}
+	return fix
+}
+
+// sameVersion compares versions ignoring a leading v, as tags and
+// ecosystem versions differ on it.
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// contains evaluates a semver or ecosystem range. Events are applied in
+// version order: a version is affected from an introduced event up to a
+// fixed one or past a last_affected one.
+func (r *osvRange) contains(version string) bool {
+	if r.Type != rangeSemver && r.Type != rangeEcosystem {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	type point struct {
+		version *semver.Version
+		event   event
+	}
+	points := make([]point, 0, len(r.Events))
+	for _, e := range r.Events {
+		s := cmp.Or(e.Introduced, e.Fixed, e.LastAffected)
+		if s == "0" {
+			s = "0.0.0"
+		}
+		ev, err := semver.NewVersion(s)
+		if err != nil {
+			// A range that cannot be ordered cannot be evaluated
+			return false
+		}
+		points = append(points, point{version: ev, event: e})
+	}
+	slices.SortStableFunc(points, func(a, b point) int { return a.version.Compare(b.version) })
+
+	affected := false
+	for _, p := range points {
+		switch {
+		case p.event.Introduced != "":
+			if !v.LessThan(p.version) {
+				affected = true
+			}
+		case p.event.Fixed != "":
+			if !v.LessThan(p.version) {
+				affected = false
+			}
+		case p.event.LastAffected != "":
+			if v.GreaterThan(p.version) {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// severity returns the severity of the record: the highest CVSS score it
+// carries or, without scores, the rating of its database.
+func (e *entry) severity() Severity {
+	score := -1.0
+	for _, s := range e.Severity {
+		if sc, ok := cvssScore(s.Score); ok {
+			score = max(score, sc)
+		}
+	}
+	if score >= 0 {
+		return scoreSeverity(score)
+	}
+
+	ratings := []string{e.Database.Severity}
+	for _, a := range e.Affected {
+		ratings = append(ratings, a.Ecosystem.Severity)
+	}
+	ret := SeverityUnknown
+	for _, r := range ratings {
+		if sev, err := ParseSeverity(r); err == nil && sev.Rank() > ret.Rank() {
+			ret = sev
+		}
+	}
+	return ret
+}
+
+// cvssScore returns the base score of a CVSS 3.0, 3.1 or 4.0 vector
+func cvssScore(vector string) (float64, bool) {
+	switch {
+	case strings.HasPrefix(vector, "CVSS:3.0/"):
+		if c, err := gocvss30.ParseVector(vector); err == nil {
+			return c.BaseScore(), true
+		}
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		if c, err := gocvss31.ParseVector(vector); err == nil {
+			return c.BaseScore(), true
+		}
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		if c, err := gocvss40.ParseVector(vector); err == nil {
+			return c.Score(), true
+		}
+	}
+	return 0, false
+}
+
+// scoreSeverity returns the qualitative rating of a CVSS score
+func scoreSeverity(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
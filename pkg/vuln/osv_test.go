@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffectedContains(t *testing.T) {
+	t.Parallel()
+	ranged := affected{
+		Ranges: []osvRange{
+			{Type: rangeSemver, Events: []event{{Introduced: "0"}, {Fixed: "1.2.3"}}},
+			{Type: rangeEcosystem, Events: []event{{Introduced: "2.0.0"}, {LastAffected: "2.1.0"}}},
+		},
+	}
+	listed := affected{
+		Versions: []string{"v0.9.0"},
+		Ranges:   []osvRange{{Type: rangeGit, Repo: "https://github.com/org/repo", Events: []event{{Introduced: "0"}}}},
+	}
+	for _, tc := range []struct {
+		name     string
+		affected affected
+		version  string
+		expect   bool
+	}{
+		{"in-semver-range", ranged, "1.0.0", true},
+		{"v-prefix", ranged, "v1.2.2", true},
+		{"at-fix", ranged, "1.2.3", false},
+		{"between-ranges", ranged, "1.5.0", false},
+		{"introduced", ranged, "2.0.0", true},
+		{"last-affected", ranged, "2.1.0", true},
+		{"past-last-affected", ranged, "2.1.1", false},
+		{"unparseable", ranged, "latest", false},
+		{"listed-version", listed, "0.9.0", true},
+		{"git-range-only", listed, "v1.0.0", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, tc.affected.contains(tc.version))
+		})
+	}
+}
+
+func TestAffectedFixed(t *testing.T) {
+	t.Parallel()
+	a := affected{
+		Ranges: []osvRange{
+			{Type: rangeSemver, Events: []event{{Introduced: "0"}, {Fixed: "1.2.3"}}},
+			{Type: rangeSemver, Events: []event{{Introduced: "1.3.0"}, {Fixed: "1.4.1"}}},
+		},
+	}
+	require.Equal(t, "1.2.3", a.fixed("1.0.0"))
+	require.Equal(t, "1.4.1", a.fixed("1.3.5"))
+	require.Empty(t, a.fixed("2.0.0"))
+}
+
+func TestEntrySeverity(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		entry  entry
+		expect Severity
+	}{
+		{
+			"cvss31-critical",
+			entry{Severity: []severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}},
+			SeverityCritical,
+		},
+		{
+			"cvss30-medium",
+			entry{Severity: []severity{{Type: "CVSS_V3", Score: "CVSS:3.0/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:L/A:N"}}},
+			SeverityMedium,
+		},
+		{
+			"highest-score",
+			entry{Severity: []severity{
+				{Type: "CVSS_V3", Score: "CVSS:3.0/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:L/A:N"},
+				{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+			}},
+			SeverityCritical,
+		},
+		{
+			"database-rating",
+			entry{Database: specificSev{Severity: "MODERATE"}},
+			SeverityMedium,
+		},
+		{
+			"ecosystem-rating",
+			entry{Affected: []affected{{Ecosystem: specificSev{Severity: "HIGH"}}}},
+			SeverityHigh,
+		},
+		{
+			"unscored",
+			entry{Severity: []severity{{Type: "CVSS_V3", Score: "garbage"}}},
+			SeverityUnknown,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, tc.entry.severity())
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// vexSuffixes are the extensions of the OpenVEX documents in releases
+var vexSuffixes = []string{".openvex.json", ".vex.json", ".openvex"}
+
+// digestRegex matches the hex sha256 digests keying the stored documents
+var digestRegex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// IsVEXFile returns true for release files holding OpenVEX documents
+func IsVEXFile(name string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range vexSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseVEX parses OpenVEX documents
+func ParseVEX(docs ...[]byte) ([]*vex.VEX, error) {
+	ret := make([]*vex.VEX, 0, len(docs))
+	for _, data := range docs {
+		doc, err := vex.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing OpenVEX document: %w", err)
+		}
+		ret = append(ret, doc)
+	}
+	return ret, nil
+}
+
+// ApplyVEX splits findings into those still affecting the products and those
+// the VEX documents dismiss, because the latest statement about them says
+// the product is not affected or has the fix. Products are the identifiers
+// (usually package URLs) of the app, components are matched as the
+// subcomponents of the statements.
+func ApplyVEX(docs []*vex.VEX, products []string, findings []*Finding) (kept, dismissed []*Finding) {
+	for _, f := range findings {
+		if vexDismisses(docs, products, f) {
+			dismissed = append(dismissed, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	return kept, dismissed
+}
+
+// vexDismisses returns true if the newest statement matching the finding,
+// across all the documents and the IDs and aliases of the finding, says the
+// products are not affected or have the fix.
+func vexDismisses(docs []*vex.VEX, products []string, f *Finding) bool {
+	var subcomponents []string
+	if f.Package.PURL != "" {
+		subcomponents = []string{f.Package.PURL}
+	}
+
+	var (
+		newest     *vex.Statement
+		newestTime time.Time
+	)
+	for _, doc := range docs {
+		for _, id := range f.IDs() {
+			for _, product := range products {
+				for _, st := range doc.Matches(id, product, subcomponents) {
+					t := statementTime(doc, &st)
+					if newest == nil || !t.Before(newestTime) {
+						newest, newestTime = &st, t
+					}
+				}
+			}
+		}
+	}
+	if newest == nil {
+		return false
+	}
+	return newest.Status == vex.StatusNotAffected || newest.Status == vex.StatusFixed
+}
+
+// statementTime returns when a statement was last updated or issued.
+// Statements without timestamps inherit the one of their document.
+func statementTime(doc *vex.VEX, st *vex.Statement) time.Time {
+	for _, t := range []*time.Time{st.LastUpdated, st.Timestamp, doc.Timestamp} {
+		if t != nil && !t.IsZero() {
+			return *t
+		}
+	}
+	return time.Time{}
+}
+
+// VEXStore keeps the OpenVEX documents published with installed artifacts,
+// keyed by the artifact's sha256 digest, so installed apps can be scanned
+// without network access.
+type VEXStore struct {
+	dir string
+}
+
+// DefaultVEXDir returns the location of the VEX store in the user's data
+// directory ($XDG_DATA_HOME or ~/.local/share).
+func DefaultVEXDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, dirName, "vex"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", dirName, "vex"), nil
+}
+
+// OpenVEXStore returns the VEX store in its default location
+func OpenVEXStore() (*VEXStore, error) {
+	dir, err := DefaultVEXDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewVEXStore(dir), nil
+}
+
+// NewVEXStore returns a VEX store rooted at a directory
+func NewVEXStore(dir string) *VEXStore {
+	return &VEXStore{dir: dir}
+}
+
+// entryDir returns the directory of the documents of a digest
+func (s *VEXStore) entryDir(digest string) (string, error) {
+	if !digestRegex.MatchString(digest) {
+		return "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return filepath.Join(s.dir, "sha256", digest), nil
+}
+
+// Save stores the documents of an artifact keyed by file name, replacing
+// the previous ones.
+func (s *VEXStore) Save(digest string, docs map[string][]byte) error {
+	dir, err := s.entryDir(digest)
+	if err != nil {
+		return err
+	}
+	for name := range docs {
+		if name != filepath.Base(name) || name == "." || name == ".." || name == "" {
+			return fmt.Errorf("invalid VEX file name %q", name)
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("removing previous VEX documents: %w", err)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("creating VEX directory: %w", err)
+	}
+	for name, data := range docs {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return fmt.Errorf("writing VEX document: %w", err)
+		}
+	}
+	return nil
+}
+
+// Load returns the documents stored for a digest, none when there are none
+func (s *VEXStore) Load(digest string) ([][]byte, error) {
+	dir, err := s.entryDir(digest)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading VEX documents: %w", err)
+	}
+	ret := [][]byte{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading VEX document: %w", err)
+		}
+		ret = append(ret, data)
+	}
+	return ret, nil
+}
+
+// Prune removes the documents of the digests not in the keep list,
+// returning the number of entries removed.
+func (s *VEXStore) Prune(keep []string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "sha256"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading VEX store: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() || !digestRegex.MatchString(e.Name()) || slices.Contains(keep, e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.dir, "sha256", e.Name())); err != nil {
+			return removed, fmt.Errorf("removing VEX documents: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
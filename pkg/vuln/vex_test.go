@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testVEX = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/repo-v1.0.0",
+  "author": "Org Security",
+  "timestamp": "2025-03-01T00:00:00Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2025-0001"},
+      "products": [{
+        "@id": "pkg:github/org/repo@v1.0.0",
+        "subcomponents": [{"@id": "pkg:golang/golang.org/x/net@v0.20.0"}]
+      }],
+      "status": "not_affected",
+      "justification": "vulnerable_code_not_in_execute_path"
+    },
+    {
+      "vulnerability": {"name": "OSV-2025-0002"},
+      "products": [{"@id": "pkg:github/org/repo@v1.0.0"}],
+      "status": "affected",
+      "action_statement": "Upgrade to v1.0.1"
+    }
+  ]
+}`
+
+func TestIsVEXFile(t *testing.T) {
+	t.Parallel()
+	require.True(t, IsVEXFile("repo.openvex.json"))
+	require.True(t, IsVEXFile("REPO.VEX.JSON"))
+	require.False(t, IsVEXFile("repo.spdx.json"))
+}
+
+func TestApplyVEX(t *testing.T) {
+	t.Parallel()
+	docs, err := ParseVEX([]byte(testVEX))
+	require.NoError(t, err)
+
+	net := &Finding{
+		ID: "GO-2025-0001", Aliases: []string{"CVE-2025-0001"},
+		Package: &Package{Name: "golang.org/x/net", Version: "v0.20.0", PURL: "pkg:golang/golang.org/x/net@v0.20.0"},
+	}
+	otherNet := &Finding{
+		ID: "GO-2025-0001", Aliases: []string{"CVE-2025-0001"},
+		Package: &Package{Name: "golang.org/x/net", Version: "v0.19.0", PURL: "pkg:golang/golang.org/x/net@v0.19.0"},
+	}
+	app := &Finding{
+		ID:      "OSV-2025-0002",
+		Package: &Package{Ecosystem: EcosystemGit, Name: "https://github.com/org/repo", Version: "v1.0.0"},
+	}
+	unlisted := &Finding{
+		ID:      "GO-2025-0009",
+		Package: &Package{Name: "golang.org/x/net", Version: "v0.20.0", PURL: "pkg:golang/golang.org/x/net@v0.20.0"},
+	}
+
+	kept, dismissed := ApplyVEX(docs, []string{"pkg:github/org/repo@v1.0.0"}, []*Finding{net, otherNet, app, unlisted})
+	require.Equal(t, []*Finding{otherNet, app, unlisted}, kept)
+	require.Equal(t, []*Finding{net}, dismissed)
+
+	// Statements about other products dismiss nothing
+	kept, dismissed = ApplyVEX(docs, []string{"pkg:github/org/repo@v2.0.0"}, []*Finding{net})
+	require.Equal(t, []*Finding{net}, kept)
+	require.Empty(t, dismissed)
+
+	_, err = ParseVEX([]byte("not json"))
+	require.Error(t, err)
+}
+
+// testVEXUpdate revises the analysis of testVEX: the app turned out to be
+// affected by the x/net vulnerability, named here by its Go ID.
+const testVEXUpdate = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/repo-v1.0.0-2",
+  "author": "Org Security",
+  "timestamp": "2025-04-01T00:00:00Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "GO-2025-0001"},
+      "products": [{
+        "@id": "pkg:github/org/repo@v1.0.0",
+        "subcomponents": [{"@id": "pkg:golang/golang.org/x/net@v0.20.0"}]
+      }],
+      "status": "affected",
+      "action_statement": "Upgrade to v1.0.1"
+    },
+    {
+      "vulnerability": {"name": "OSV-2025-0002"},
+      "products": [{"@id": "pkg:github/org/repo@v1.0.0"}],
+      "status": "fixed"
+    }
+  ]
+}`
+
+func TestApplyVEXConflictingDocuments(t *testing.T) {
+	t.Parallel()
+	net := &Finding{
+		ID: "GO-2025-0001", Aliases: []string{"CVE-2025-0001"},
+		Package: &Package{Name: "golang.org/x/net", Version: "v0.20.0", PURL: "pkg:golang/golang.org/x/net@v0.20.0"},
+	}
+	app := &Finding{
+		ID:      "OSV-2025-0002",
+		Package: &Package{Ecosystem: EcosystemGit, Name: "https://github.com/org/repo", Version: "v1.0.0"},
+	}
+
+	// The newest statement wins regardless of the order of the documents
+	for _, order := range [][]string{{testVEX, testVEXUpdate}, {testVEXUpdate, testVEX}} {
+		docs, err := ParseVEX([]byte(order[0]), []byte(order[1]))
+		require.NoError(t, err)
+		kept, dismissed := ApplyVEX(docs, []string{"pkg:github/org/repo@v1.0.0"}, []*Finding{net, app})
+		require.Equal(t, []*Finding{net}, kept)
+		require.Equal(t, []*Finding{app}, dismissed)
+	}
+}
+
+func TestVEXStore(t *testing.T) {
+	t.Parallel()
+	store := NewVEXStore(t.TempDir())
+	digest := strings.Repeat("a", 64)
+	other := strings.Repeat("b", 64)
+
+	docs, err := store.Load(digest)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	require.NoError(t, store.Save(digest, map[string][]byte{"repo.openvex.json": []byte(testVEX)}))
+	require.NoError(t, store.Save(other, map[string][]byte{"other.vex.json": []byte(testVEX)}))
+	docs, err = store.Load(digest)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte(testVEX)}, docs)
+
+	require.Error(t, store.Save("notadigest", nil))
+	require.Error(t, store.Save(digest, map[string][]byte{"../escape.vex.json": nil}))
+
+	removed, err := store.Prune([]string{digest})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	docs, err = store.Load(other)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+	docs, err = store.Load(digest)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
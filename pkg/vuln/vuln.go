@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vuln checks apps and the components listed in their SBOMs against
+// a local copy of the OSV vulnerability database. The database is read from
+// the JSON files or the ecosystem zip archives published by OSV, so scans
+// run without network access. Findings can be dismissed by the OpenVEX
+// documents published with a release.
+package vuln
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/package-url/packageurl-go"
+)
+
+// Severity is how serious a vulnerability is, from its CVSS score or the
+// rating of its advisory.
+type Severity string
+
+const (
+	// SeverityUnknown is the severity of vulnerabilities with no score or
+	// rating. They are reported but never block installs.
+	SeverityUnknown  Severity = "unknown"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists the severities from the mildest to the most serious
+var Severities = []Severity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// Ecosystems of the packages matched by repository instead of by name
+const (
+	EcosystemGit = "GIT"
+	EcosystemGo  = "Go"
+)
+
+var ErrUnsupportedPURL = errors.New("package type has no OSV ecosystem")
+
+// purlEcosystems maps package URL types to their OSV ecosystem
+var purlEcosystems = map[string]string{
+	packageurl.TypeGolang:   EcosystemGo,
+	packageurl.TypeNPM:      "npm",
+	packageurl.TypePyPi:     "PyPI",
+	packageurl.TypeCargo:    "crates.io",
+	packageurl.TypeMaven:    "Maven",
+	packageurl.TypeGem:      "RubyGems",
+	packageurl.TypeNuget:    "NuGet",
+	packageurl.TypeComposer: "Packagist",
+	packageurl.TypeHex:      "Hex",
+	packageurl.TypePub:      "Pub",
+	"githubactions":         "GitHub Actions",
+}
+
+// ParseSeverity returns the severity named by a string, accepting the
+// moderate rating of GitHub advisories as medium.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(s))
+	if sev == "moderate" {
+		sev = SeverityMedium
+	}
+	if !slices.Contains(Severities, sev) {
+		return "", fmt.Errorf("unknown severity %q, valid severities are %v", s, Severities)
+	}
+	return sev, nil
+}
+
+// Rank returns the position of the severity in the scale, unknown
+// severities rank lowest.
+func (s Severity) Rank() int {
+	return max(slices.Index(Severities, s), 0)
+}
+
+// Exceeds returns true if the severity is more serious than the maximum.
+// Unknown severities never exceed it.
+func (s Severity) Exceeds(maximum Severity) bool {
+	return s != SeverityUnknown && s.Rank() > maximum.Rank()
+}
+
+// Package is a piece of software to look up in the database
+type Package struct {
+	// Ecosystem and Name identify the package in OSV. Apps matched by their
+	// repository use the GIT ecosystem and the repository URL.
+	Ecosystem string
+	Name      string
+	Version   string
+
+	// PURL is the package URL the package was read from, empty for apps
+	PURL string
+}
+
+// String returns the package name and version
+func (p *Package) String() string {
+	return p.Name + "@" + p.Version
+}
+
+// key returns the string indexing the package in a scan
+func (p *Package) key() string {
+	return packageKey(p.Ecosystem, p.Name)
+}
+
+// packageKey joins an ecosystem and a package name, repository URLs are
+// compared ignoring case and a trailing .git.
+func packageKey(ecosystem, name string) string {
+	if ecosystem == EcosystemGit {
+		name = strings.TrimSuffix(strings.ToLower(strings.TrimSuffix(name, "/")), ".git")
+	}
+	return ecosystem + "\x00" + name
+}
+
+// AppPackages returns the packages an app released from a repository is
+// looked up as: the repository, whose advisories list the affected tags,
+// and the Go module of the same name (with its major version suffix from
+// v2 on) for GitHub hosted projects.
+func AppPackages(host, org, repo, version string) []*Package {
+	pkgs := []*Package{
+		{Ecosystem: EcosystemGit, Name: fmt.Sprintf("https://%s/%s/%s", host, org, repo), Version: version},
+	}
+	if host != "github.com" {
+		return pkgs
+	}
+	module := fmt.Sprintf("%s/%s/%s", host, org, repo)
+	if v, err := semver.NewVersion(version); err == nil && v.Major() >= 2 {
+		module = fmt.Sprintf("%s/v%d", module, v.Major())
+	}
+	return append(pkgs, &Package{Ecosystem: EcosystemGo, Name: module, Version: version})
+}
+
+// PackageFromPURL returns the package identified by a package URL
+func PackageFromPURL(purl string) (*Package, error) {
+	p, err := packageurl.FromString(purl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing package URL: %w", err)
+	}
+	ecosystem, ok := purlEcosystems[p.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPURL, p.Type)
+	}
+	if p.Version == "" {
+		return nil, fmt.Errorf("package URL %s has no version", purl)
+	}
+
+	name := p.Name
+	if p.Namespace != "" {
+		sep := "/"
+		if p.Type == packageurl.TypeMaven {
+			sep = ":"
+		}
+		name = p.Namespace + sep + p.Name
+	}
+	return &Package{Ecosystem: ecosystem, Name: name, Version: p.Version, PURL: purl}, nil
+}
+
+// Finding is a vulnerability affecting a package
+type Finding struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	Severity Severity
+	Package  *Package
+
+	// Fixed is the first version fixing the vulnerability, when known
+	Fixed string
+}
+
+// String returns a one line description of the finding
+func (f *Finding) String() string {
+	id := f.ID
+	if len(f.Aliases) > 0 {
+		id += " (" + strings.Join(f.Aliases, ", ") + ")"
+	}
+	s := fmt.Sprintf("%s [%s] in %s", id, f.Severity, f.Package)
+	if f.Fixed != "" {
+		s += ", fixed in " + f.Fixed
+	}
+	if f.Summary != "" {
+		s += ": " + f.Summary
+	}
+	return s
+}
+
+// IDs returns the vulnerability ID followed by its aliases
+func (f *Finding) IDs() []string {
+	return append([]string{f.ID}, f.Aliases...)
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package vuln
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		input   string
+		expect  Severity
+		mustErr bool
+	}{
+		{"low", "low", SeverityLow, false},
+		{"uppercase", "HIGH", SeverityHigh, false},
+		{"moderate", "MODERATE", SeverityMedium, false},
+		{"unknown", "unknown", SeverityUnknown, false},
+		{"invalid", "severe", "", true},
+		{"empty", "", "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sev, err := ParseSeverity(tc.input)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, sev)
+		})
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		severity Severity
+		maximum  Severity
+		expect   bool
+	}{
+		{"above", SeverityHigh, SeverityMedium, true},
+		{"equal", SeverityMedium, SeverityMedium, false},
+		{"below", SeverityLow, SeverityMedium, false},
+		{"critical-over-high", SeverityCritical, SeverityHigh, true},
+		{"unknown-never", SeverityUnknown, SeverityUnknown, false},
+		{"max-unknown", SeverityLow, SeverityUnknown, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, tc.severity.Exceeds(tc.maximum))
+		})
+	}
+}
+
+func TestPackageFromPURL(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		purl    string
+		expect  *Package
+		mustErr bool
+	}{
+		{
+			"golang", "pkg:golang/golang.org/x/net@v0.17.0",
+			&Package{Ecosystem: EcosystemGo, Name: "golang.org/x/net", Version: "v0.17.0"}, false,
+		},
+		{
+			"npm-scoped", "pkg:npm/%40babel/core@7.0.0",
+			&Package{Ecosystem: "npm", Name: "@babel/core", Version: "7.0.0"}, false,
+		},
+		{
+			"maven", "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			&Package{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"}, false,
+		},
+		{
+			"pypi", "pkg:pypi/requests@2.31.0",
+			&Package{Ecosystem: "PyPI", Name: "requests", Version: "2.31.0"}, false,
+		},
+		{"no-version", "pkg:golang/golang.org/x/net", nil, true},
+		{"unsupported", "pkg:deb/debian/curl@7.88.1", nil, true},
+		{"invalid", "not a purl", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := PackageFromPURL(tc.purl)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.expect.PURL = tc.purl
+			require.Equal(t, tc.expect, p)
+		})
+	}
+}
+
+func TestAppPackages(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		host    string
+		version string
+		expect  []*Package
+	}{
+		{
+			"v1", "github.com", "v1.2.0", []*Package{
+				{Ecosystem: EcosystemGit, Name: "https://github.com/org/repo", Version: "v1.2.0"},
+				{Ecosystem: EcosystemGo, Name: "github.com/org/repo", Version: "v1.2.0"},
+			},
+		},
+		{
+			"v3", "github.com", "v3.0.1", []*Package{
+				{Ecosystem: EcosystemGit, Name: "https://github.com/org/repo", Version: "v3.0.1"},
+				{Ecosystem: EcosystemGo, Name: "github.com/org/repo/v3", Version: "v3.0.1"},
+			},
+		},
+		{
+			"other-host", "git.example.com", "v3.0.1", []*Package{
+				{Ecosystem: EcosystemGit, Name: "https://git.example.com/org/repo", Version: "v3.0.1"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, AppPackages(tc.host, "org", "repo", tc.version))
+		})
+	}
+}